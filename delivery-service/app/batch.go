@@ -0,0 +1,195 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+)
+
+// BatchConfig contains the settings for grouping deliveries into batches.
+type BatchConfig struct {
+	// Enabled enables batching deliveries in the open pool.
+	Enabled bool
+	// MaxSize is the maximum number of deliveries in a batch.
+	MaxSize int
+	// PickupRadiusKm is the maximum distance between the restaurants of the deliveries in a batch.
+	PickupRadiusKm float64
+	// MaxAngleDeg is the maximum difference in direction from the restaurant to the destinations in a batch.
+	MaxAngleDeg float64
+	// MaxAge is the time a batch is available before it is split up if no driver claims it.
+	MaxAge time.Duration
+	// Interval is how often the open pool is batched.
+	Interval time.Duration
+}
+
+func (c BatchConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval <= 0 {
+		return errors.New("batch interval must be greater than 0")
+	}
+	if c.MaxSize < 2 {
+		return errors.New("batch max size must be at least 2")
+	}
+	return nil
+}
+
+// GetBatches returns the batches that can be claimed by the driver.
+func (d *App) GetBatches(ctx context.Context, driverID string) ([]*models.Batch, error) {
+	if err := d.requireOnline(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return d.batches.GetOpenBatches(ctx)
+}
+
+// ClaimBatch assigns all deliveries in the batch to the driver.
+func (d *App) ClaimBatch(ctx context.Context, driverID string, batchID bson.ObjectID) (*models.Batch, error) {
+	if err := d.requireOnline(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	batch, err := d.batches.ClaimBatch(ctx, batchID, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.changeDriverStatus(ctx, driverID, models.DriverAvailable, models.DriverBusy)
+
+	for _, delivery := range batch.Deliveries {
+		if delivery.DriverId == nil || *delivery.DriverId != driverID {
+			continue
+		}
+		deliveriesClaimed.WithLabelValues("batch").Inc()
+
+		d.updateEstimate(ctx, delivery)
+
+		err = d.orders.SetDeliveryDriver(ctx, delivery.OrderId, driverID)
+		if err != nil {
+			zap.L().Error("Failed to send order state update", zap.Error(err))
+		}
+		d.sendEstimate(ctx, delivery)
+	}
+
+	return batch, nil
+}
+
+// RunBatcher groups deliveries in the open pool into batches until ctx is cancelled.
+// This does nothing if batching is disabled.
+func (d *App) RunBatcher(ctx context.Context) {
+	if !d.batch.Enabled {
+		return
+	}
+
+	ticker := time.NewTicker(d.batch.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := d.batches.ExpireBatches(ctx, time.Now()); err != nil {
+			zap.L().Error("Failed to expire batches", zap.Error(err))
+			continue
+		}
+
+		deliveries, err := d.batches.GetBatchable(ctx)
+		if err != nil {
+			zap.L().Error("Failed to get deliveries for batching", zap.Error(err))
+			continue
+		}
+
+		for _, group := range groupDeliveries(deliveries, d.batch) {
+			now := time.Now()
+			batch := &models.Batch{Route: routeOrder(group), CreatedAt: now, ExpiresAt: now.Add(d.batch.MaxAge)}
+
+			ok, err := d.batches.CreateBatch(ctx, batch)
+			if err != nil {
+				zap.L().Error("Failed to create batch", zap.Error(err))
+			} else if ok {
+				zap.L().Info("Created delivery batch", zap.String("batchId", batch.Id.Hex()), zap.Int("size", len(group)))
+			}
+		}
+	}
+}
+
+// groupDeliveries groups deliveries from nearby restaurants heading in a similar direction.
+// Each delivery is added to the first group it fits in. Groups with a single delivery are not returned.
+func groupDeliveries(deliveries []*models.Delivery, cfg BatchConfig) [][]*models.Delivery {
+	type group struct {
+		deliveries []*models.Delivery
+		bearing    float64
+	}
+
+	var groups []*group
+	for _, delivery := range deliveries {
+		bearing := deliveryBearing(delivery)
+
+		var target *group
+		for _, g := range groups {
+			first := g.deliveries[0]
+			if len(g.deliveries) < cfg.MaxSize &&
+				pointDistance(first.Pickup.Location, delivery.Pickup.Location) <= cfg.PickupRadiusKm &&
+				location.AngleDiffDeg(g.bearing, bearing) <= cfg.MaxAngleDeg {
+				target = g
+				break
+			}
+		}
+
+		if target == nil {
+			groups = append(groups, &group{bearing: bearing, deliveries: []*models.Delivery{delivery}})
+		} else {
+			target.deliveries = append(target.deliveries, delivery)
+		}
+	}
+
+	var result [][]*models.Delivery
+	for _, g := range groups {
+		if len(g.deliveries) > 1 {
+			result = append(result, g.deliveries)
+		}
+	}
+	return result
+}
+
+// routeOrder orders the drop-offs using the nearest neighbor heuristic starting from the first restaurant.
+func routeOrder(deliveries []*models.Delivery) []bson.ObjectID {
+	remaining := append([]*models.Delivery{}, deliveries...)
+	route := make([]bson.ObjectID, 0, len(deliveries))
+
+	current := deliveries[0].Pickup.Location
+	for len(remaining) > 0 {
+		nearest := 0
+		for i, delivery := range remaining {
+			if pointDistance(current, delivery.Destination.Position) < pointDistance(current, remaining[nearest].Destination.Position) {
+				nearest = i
+			}
+		}
+
+		route = append(route, remaining[nearest].Id)
+		current = remaining[nearest].Destination.Position
+		remaining = append(remaining[:nearest], remaining[nearest+1:]...)
+	}
+
+	return route
+}
+
+// deliveryBearing returns the direction from the restaurant to the destination of the delivery.
+func deliveryBearing(delivery *models.Delivery) float64 {
+	from, to := delivery.Pickup.Location.Coordinates, delivery.Destination.Position.Coordinates
+	return location.BearingDeg(from[1], from[0], to[1], to[0])
+}
+
+// pointDistance returns the distance between two points in kilometers.
+func pointDistance(a, b models.Point) float64 {
+	return location.DistanceKm(a.Coordinates[1], a.Coordinates[0], b.Coordinates[1], b.Coordinates[0])
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// testDelivery creates a delivery from the pickup to the destination. Coordinates are [longitude, latitude].
+func testDelivery(orderId string, pickup, destination [2]float64) *models.Delivery {
+	return &models.Delivery{
+		Id:          bson.NewObjectID(),
+		OrderId:     orderId,
+		Pickup:      models.Restaurant{Location: models.Point{Coordinates: pickup}},
+		Destination: models.Address{Position: models.Point{Coordinates: destination}},
+	}
+}
+
+func orderIds(groups [][]*models.Delivery) [][]string {
+	result := [][]string{}
+	for _, group := range groups {
+		ids := []string{}
+		for _, delivery := range group {
+			ids = append(ids, delivery.OrderId)
+		}
+		result = append(result, ids)
+	}
+	return result
+}
+
+func TestGroupDeliveries(t *testing.T) {
+	restaurant := [2]float64{79.86, 6.90}
+	// about 0.5km east of restaurant
+	nearby := [2]float64{79.8645, 6.90}
+	// about 10km east of restaurant
+	far := [2]float64{79.95, 6.90}
+
+	north := testDelivery("north", restaurant, [2]float64{79.86, 6.95})
+	northNearby := testDelivery("north nearby", nearby, [2]float64{79.865, 6.94})
+	northEast := testDelivery("north east", restaurant, [2]float64{79.87, 6.96})
+	south := testDelivery("south", restaurant, [2]float64{79.86, 6.85})
+	northFar := testDelivery("north far", far, [2]float64{79.95, 6.95})
+
+	cfg := BatchConfig{MaxSize: 3, PickupRadiusKm: 1, MaxAngleDeg: 45}
+
+	tests := []struct {
+		name       string
+		deliveries []*models.Delivery
+		maxSize    int
+		expected   [][]string
+	}{
+		{
+			name:       "same direction",
+			deliveries: []*models.Delivery{north, northNearby, northEast},
+			expected:   [][]string{{"north", "north nearby", "north east"}},
+		},
+		{
+			name:       "opposite direction",
+			deliveries: []*models.Delivery{north, south},
+			expected:   [][]string{},
+		},
+		{
+			name:       "restaurant too far",
+			deliveries: []*models.Delivery{north, northFar},
+			expected:   [][]string{},
+		},
+		{
+			name:       "max size",
+			deliveries: []*models.Delivery{north, northNearby, northEast},
+			maxSize:    2,
+			expected:   [][]string{{"north", "north nearby"}},
+		},
+		{
+			name:       "separate groups",
+			deliveries: []*models.Delivery{north, south, northNearby, testDelivery("south 2", restaurant, [2]float64{79.855, 6.86})},
+			expected:   [][]string{{"north", "north nearby"}, {"south", "south 2"}},
+		},
+		{
+			name:       "single delivery",
+			deliveries: []*models.Delivery{north},
+			expected:   [][]string{},
+		},
+	}
+
+	for _, test := range tests {
+		cfg := cfg
+		if test.maxSize != 0 {
+			cfg.MaxSize = test.maxSize
+		}
+
+		got := orderIds(groupDeliveries(test.deliveries, cfg))
+		if !slices.EqualFunc(got, test.expected, slices.Equal) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestRouteOrder(t *testing.T) {
+	restaurant := [2]float64{79.86, 6.90}
+
+	far := testDelivery("far", restaurant, [2]float64{79.86, 6.98})
+	near := testDelivery("near", restaurant, [2]float64{79.86, 6.92})
+	middle := testDelivery("middle", restaurant, [2]float64{79.86, 6.95})
+	// closer to the restaurant than middle, but further from near
+	side := testDelivery("side", restaurant, [2]float64{79.80, 6.92})
+
+	tests := []struct {
+		name       string
+		deliveries []*models.Delivery
+		expected   []bson.ObjectID
+	}{
+		{"nearest first", []*models.Delivery{far, near, middle}, []bson.ObjectID{near.Id, middle.Id, far.Id}},
+		{"from previous drop-off", []*models.Delivery{side, middle, near}, []bson.ObjectID{near.Id, middle.Id, side.Id}},
+		{"single delivery", []*models.Delivery{far}, []bson.ObjectID{far.Id}},
+	}
+
+	for _, test := range tests {
+		input := slices.Clone(test.deliveries)
+		if got := routeOrder(test.deliveries); !slices.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+		if !slices.Equal(input, test.deliveries) {
+			t.Errorf("%s: the deliveries should not be modified", test.name)
+		}
+	}
+}
+
+func TestBatchConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg   BatchConfig
+		valid bool
+	}{
+		{BatchConfig{Enabled: true, MaxSize: 3, Interval: time.Second}, true},
+		{BatchConfig{Enabled: true, MaxSize: 3}, false},
+		{BatchConfig{Enabled: true, MaxSize: 3, Interval: -time.Second}, false},
+		{BatchConfig{Enabled: true, MaxSize: 1, Interval: time.Second}, false},
+		// the batcher does not run, so the interval is not used
+		{BatchConfig{}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.cfg.validate(); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid to be %v got %v", test.cfg, test.valid, err)
+		}
+	}
+}
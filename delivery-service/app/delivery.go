@@ -3,10 +3,14 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.uber.org/zap"
@@ -14,50 +18,247 @@ import (
 
 type ServiceConfig struct {
 	Order string
+	User  string
+	// Upload is the http url of upload-service.
+	Upload  string
+	Clients grpcclient.Config
+}
+
+// Orders sends delivery updates to order-service.
+type Orders interface {
+	SetOrderDelivered(ctx context.Context, orderId string) error
+	SetOrderFailed(ctx context.Context, orderId string, reason string) error
+	SetOrderReturned(ctx context.Context, orderId string) error
+	// SetOrderPickUp marks the order as picked up and sends the estimated delivery time if it is not nil.
+	SetOrderPickUp(ctx context.Context, orderId string, deliveryAt *time.Time) error
+	// SetEstimatedDelivery sends the estimated delivery time of an order that is waiting for pickup.
+	SetEstimatedDelivery(ctx context.Context, orderId string, deliveryAt time.Time) error
+	SetDeliveryDriver(ctx context.Context, orderId string, driverId string) error
+}
+
+// Users sends driver updates to user-service.
+type Users interface {
+	// SetDriverStatus updates the driver status stored in user-service.
+	// If the user is not a driver, [grpc.ErrNotDriver] is returned.
+	SetDriverStatus(ctx context.Context, driverId string, driverStatus models.DriverStatus) error
 }
 
 type App struct {
-	orders *grpc.OrderClient
-	db     repo.DeliveryRepo
+	orders     Orders
+	users      Users
+	db         repo.DeliveryRepo
+	drivers    repo.DriverRepo
+	dispatcher *Dispatcher
+	release    ReleaseConfig
+	proof      ProofConfig
+	uploads    Uploads
+	batches    repo.BatchRepo
+	batch      BatchConfig
+	eta        eta.Config
+	earningsDb repo.EarningsRepo
+	earnings   EarningsConfig
+	// locationMaxAge is the maximum age of a driver location used for estimates.
+	locationMaxAge time.Duration
 }
 
-func New(cfg ServiceConfig, mongodb *mongo.Client) (*App, error) {
+func New(cfg ServiceConfig, dispatch DispatchConfig, release ReleaseConfig, proof ProofConfig, batch BatchConfig, estimates eta.Config, earnings EarningsConfig, mongodb *mongo.Client) (*App, error) {
+	if err := dispatch.validate(); err != nil {
+		return nil, err
+	}
+	if err := release.validate(); err != nil {
+		return nil, err
+	}
+	if err := proof.validate(); err != nil {
+		return nil, err
+	}
+	if err := batch.validate(); err != nil {
+		return nil, err
+	}
+
 	db := mongodb.Database("delivery-service")
 	delivery, err := repo.NewDeliveryRepo(db)
 	if err != nil {
 		return nil, err
 	}
 
-	orderClient, err := grpc.NewOrderClient(cfg.Order)
+	drivers, err := repo.NewDriverRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := repo.NewBatchRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	earningsDb, err := repo.NewEarningsRepo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	orderClient, err := grpc.NewOrderClient(cfg.Order, cfg.Clients)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to connect to order service: %w", err)
 	}
 	zap.S().Infof("Connected to restaurant service at %s", cfg.Order)
 
-	app := &App{db: delivery, orders: orderClient}
+	userClient, err := grpc.NewUserClient(cfg.User, cfg.Clients)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to user service: %w", err)
+	}
+	zap.S().Infof("Connected to user service at %s", cfg.User)
+
+	app := &App{
+		db: delivery, orders: orderClient, users: userClient, drivers: drivers, release: release, proof: proof, uploads: NewUploadClient(cfg.Upload, cfg.Clients.Timeout), batches: batches, batch: batch,
+		eta: estimates, locationMaxAge: dispatch.LocationMaxAge, earningsDb: earningsDb, earnings: earnings,
+	}
+	if dispatch.Enabled {
+		app.dispatcher = NewDispatcher(dispatch, delivery, drivers)
+	}
 
 	return app, nil
 }
 
 func (d *App) CreateDelivery(ctx context.Context, data *models.Delivery) (string, error) {
-	order, err := d.db.AddDelivery(ctx, data)
+	var err error
+	data.Pin, err = newPin()
 	if err != nil {
 		return "", err
 	}
 
-	return order, nil
-}
+	data.State = models.DeliveryStateUnclaimed
+	data.EstimatedReadyAt, data.EstimatedDeliveryAt = d.estimate(data, nil, time.Now())
 
-func (d *App) GetUserDeliveries(ctx context.Context, userID string) ([]*models.Delivery, error) {
-	deliveries, err := d.db.GetByDeliveryDriver(ctx, userID)
+	deliveryId, err := d.db.AddDelivery(ctx, data)
 	if err != nil {
+		return "", err
+	}
+	deliveriesCreated.Inc()
+
+	if d.dispatcher != nil {
+		data.Id, _ = bson.ObjectIDFromHex(deliveryId)
+
+		// the delivery stays in the open pool if dispatching fails
+		err = d.dispatcher.Dispatch(ctx, data)
+		if err != nil {
+			zap.L().Error("Failed to dispatch delivery", zap.String("deliveryId", deliveryId), zap.Error(err))
+		}
+	}
+
+	return deliveryId, nil
+}
+
+// RunDispatcher runs the automatic dispatcher until ctx is cancelled.
+// This does nothing if the dispatcher is disabled.
+func (d *App) RunDispatcher(ctx context.Context) {
+	if d.dispatcher != nil {
+		d.dispatcher.Run(ctx)
+	}
+}
+
+func (d *App) GetOffers(ctx context.Context, driverID string) ([]*models.Delivery, error) {
+	if err := d.requireOnline(ctx, driverID); err != nil {
 		return nil, err
 	}
 
-	return deliveries, nil
+	return d.db.GetOffers(ctx, driverID)
+}
+
+func (d *App) DeclineOffer(ctx context.Context, driverID string, deliveryID bson.ObjectID) error {
+	return d.db.DeclineOffer(ctx, deliveryID, driverID)
+}
+
+// GetDriverStatus gets the availability of the driver.
+func (d *App) GetDriverStatus(ctx context.Context, driverID string) (models.DriverStatus, error) {
+	return d.drivers.GetStatus(ctx, driverID)
+}
+
+// SetDriverOnline marks the driver as online or offline.
+// Drivers that go online while they have active deliveries are marked as busy.
+func (d *App) SetDriverOnline(ctx context.Context, driverID string, online bool) (models.DriverStatus, error) {
+	status := models.DriverUnavailable
+	if online {
+		loads, err := d.db.GetDriverLoads(ctx, []string{driverID})
+		if err != nil {
+			return "", err
+		}
+
+		status = models.DriverAvailable
+		if loads[driverID] > 0 {
+			status = models.DriverBusy
+		}
+	}
+
+	// user-service is updated first since it rejects users that are not drivers
+	err := d.users.SetDriverStatus(ctx, driverID, status)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.drivers.SetStatus(ctx, driverID, status)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// changeDriverStatus changes the status of the driver if the current status is from
+// and sends the new status to user-service.
+func (d *App) changeDriverStatus(ctx context.Context, driverID string, from, to models.DriverStatus) {
+	changed, err := d.drivers.ChangeStatus(ctx, driverID, from, to)
+	if err != nil {
+		zap.L().Error("Failed to update driver status", zap.String("driverId", driverID), zap.Error(err))
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	err = d.users.SetDriverStatus(ctx, driverID, to)
+	if err != nil {
+		zap.L().Error("Failed to send driver status update", zap.String("driverId", driverID), zap.Error(err))
+	}
+}
+
+// driverFreed marks a busy driver as available if they do not have any other active deliveries.
+func (d *App) driverFreed(ctx context.Context, driverID string) {
+	loads, err := d.db.GetDriverLoads(ctx, []string{driverID})
+	if err != nil {
+		zap.L().Error("Failed to get driver deliveries", zap.String("driverId", driverID), zap.Error(err))
+	} else if loads[driverID] == 0 {
+		d.changeDriverStatus(ctx, driverID, models.DriverBusy, models.DriverAvailable)
+	}
+}
+
+// requireOnline returns [repo.ErrDriverOffline] if the driver is not online.
+func (d *App) requireOnline(ctx context.Context, driverID string) error {
+	status, err := d.drivers.GetStatus(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	if !status.Online() {
+		return repo.ErrDriverOffline
+	}
+
+	return nil
+}
+
+func (d *App) UpdateDriverLocation(ctx context.Context, driverID string, lat, lng float64) error {
+	return d.drivers.UpdateLocation(ctx, driverID, models.Point{Type: "point", Coordinates: [2]float64{lng, lat}})
+}
+
+func (d *App) GetUserDeliveries(ctx context.Context, userID string, p pagination.Params) ([]*models.Delivery, string, error) {
+	return d.db.GetByDeliveryDriver(ctx, userID, p)
 }
 
 func (d *App) GetNearbyDeliveries(ctx context.Context, userID string) ([]*models.Delivery, error) {
+	if err := d.requireOnline(ctx, userID); err != nil {
+		return nil, err
+	}
+
 	deliveries, err := d.db.GetNearbyDeliveries(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -85,15 +286,24 @@ func (d *App) GetDeliveryByOrderId(ctx context.Context, orderID string) (*models
 }
 
 func (d *App) ClaimDelivery(ctx context.Context, driverID string, deliveryID bson.ObjectID) (*models.Delivery, error) {
+	if err := d.requireOnline(ctx, driverID); err != nil {
+		return nil, err
+	}
+
 	order, err := d.db.ClaimDelivery(ctx, deliveryID, driverID)
 	if err != nil {
 		return nil, err
 	}
+	deliveriesClaimed.WithLabelValues("single").Inc()
+
+	d.changeDriverStatus(ctx, driverID, models.DriverAvailable, models.DriverBusy)
+	d.updateEstimate(ctx, order)
 
 	err = d.orders.SetDeliveryDriver(ctx, order.OrderId, driverID)
 	if err != nil {
 		zap.L().Error("Failed to send order state update", zap.Error(err))
 	}
+	d.sendEstimate(ctx, order)
 
 	return order, nil
 }
@@ -104,7 +314,9 @@ func (d *App) PickupOrder(ctx context.Context, driverID string, deliveryID bson.
 		return nil, err
 	}
 
-	err = d.orders.SetOrderPickUp(ctx, order.OrderId)
+	d.updateEstimate(ctx, order)
+
+	err = d.orders.SetOrderPickUp(ctx, order.OrderId, order.EstimatedDeliveryAt)
 	if err != nil {
 		zap.L().Error("Failed to send order state update", zap.Error(err))
 	}
@@ -112,12 +324,38 @@ func (d *App) PickupOrder(ctx context.Context, driverID string, deliveryID bson.
 	return order, nil
 }
 
-func (d *App) CompleteOrder(ctx context.Context, driverID string, deliveryID bson.ObjectID) (*models.Delivery, error) {
-	order, err := d.db.DeliveryComplete(ctx, deliveryID, driverID)
+func (d *App) CompleteOrder(ctx context.Context, driverID string, deliveryID bson.ObjectID, proof *Proof) (*models.Delivery, error) {
+	delivery, err := d.db.GetAssigned(ctx, deliveryID, driverID)
 	if err != nil {
 		return nil, err
 	}
 
+	var lastAttempt bool
+	if proof.Pin != "" && delivery.Pin != "" {
+		lastAttempt, err = d.usePinAttempt(ctx, driverID, delivery)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deliveryProof, err := d.checkProof(ctx, driverID, delivery, proof)
+	if err != nil {
+		if err == ErrIncorrectPin && lastAttempt {
+			zap.L().Warn("Delivery PIN locked after too many incorrect attempts",
+				zap.String("deliveryId", deliveryID.Hex()), zap.String("driverId", driverID))
+		}
+		return nil, err
+	}
+
+	order, err := d.db.DeliveryComplete(ctx, deliveryID, driverID, deliveryProof)
+	if err != nil {
+		return nil, err
+	}
+	deliveriesCompleted.Inc()
+
+	d.driverFreed(ctx, driverID)
+	d.creditDelivery(ctx, order)
+
 	err = d.orders.SetOrderDelivered(ctx, order.OrderId)
 	if err != nil {
 		zap.L().Error("Failed to send order state update", zap.Error(err))
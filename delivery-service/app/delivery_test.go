@@ -0,0 +1,311 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakeDeliveries stores deliveries in memory.
+// Only the methods used by the tests are implemented, the other methods panic.
+type fakeDeliveries struct {
+	repo.DeliveryRepo
+	deliveries []*models.Delivery
+	// offers contains the offers set by the dispatcher.
+	offers map[bson.ObjectID]*models.Offer
+	// err is returned by GetDriverLoads if it is set.
+	err error
+}
+
+func (f *fakeDeliveries) get(deliveryId bson.ObjectID) *models.Delivery {
+	for _, delivery := range f.deliveries {
+		if delivery.Id == deliveryId {
+			return delivery
+		}
+	}
+	return nil
+}
+
+func (f *fakeDeliveries) GetDriverLoads(_ context.Context, driverIds []string) (map[string]int, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	loads := map[string]int{}
+	for _, delivery := range f.deliveries {
+		if delivery.DriverId == nil || !slices.Contains(driverIds, *delivery.DriverId) {
+			continue
+		}
+		switch delivery.State {
+		case models.DeliveryStateWaiting, models.DeliveryStateDelivering, models.DeliveryStateReturning:
+			loads[*delivery.DriverId]++
+		}
+	}
+	return loads, nil
+}
+
+func (f *fakeDeliveries) SetEstimates(_ context.Context, deliveryId bson.ObjectID, readyAt *time.Time, deliveryAt *time.Time) error {
+	delivery := f.get(deliveryId)
+	if delivery == nil {
+		return repo.ErrNoDelivery
+	}
+	delivery.EstimatedReadyAt, delivery.EstimatedDeliveryAt = readyAt, deliveryAt
+	return nil
+}
+
+func (f *fakeDeliveries) ReleaseDelivery(_ context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
+	delivery := f.get(deliveryId)
+	if delivery == nil {
+		return nil, repo.ErrNoDelivery
+	}
+	if delivery.DriverId == nil || *delivery.DriverId != driverId {
+		return nil, repo.ErrNotAssigned
+	}
+	if delivery.State != models.DeliveryStateWaiting {
+		return nil, &repo.InvalidStateError{State: delivery.State, Next: models.DeliveryStateUnclaimed}
+	}
+
+	delivery.State, delivery.DriverId, delivery.BatchId = models.DeliveryStateUnclaimed, nil, nil
+	result := *delivery
+	return &result, nil
+}
+
+func (f *fakeDeliveries) ReassignDelivery(_ context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, *string, error) {
+	delivery := f.get(deliveryId)
+	if delivery == nil {
+		return nil, nil, repo.ErrNoDelivery
+	}
+	if delivery.State != models.DeliveryStateUnclaimed && delivery.State != models.DeliveryStateWaiting {
+		return nil, nil, &repo.InvalidStateError{State: delivery.State, Next: models.DeliveryStateWaiting}
+	}
+
+	prevDriver := delivery.DriverId
+	delivery.State, delivery.DriverId, delivery.BatchId, delivery.Offer = models.DeliveryStateWaiting, &driverId, nil, nil
+	result := *delivery
+	return &result, prevDriver, nil
+}
+
+func (f *fakeDeliveries) GetStaleClaims(_ context.Context, before time.Time) ([]*models.Delivery, error) {
+	stale := []*models.Delivery{}
+	for _, delivery := range f.deliveries {
+		claimedAt := delivery.Timestamps.ClaimedAt
+		if delivery.State == models.DeliveryStateWaiting && claimedAt != nil && claimedAt.Before(before) {
+			result := *delivery
+			stale = append(stale, &result)
+		}
+	}
+	return stale, nil
+}
+
+func (f *fakeDeliveries) SetOffer(_ context.Context, deliveryId bson.ObjectID, _ int, offer *models.Offer) (bool, error) {
+	f.offers[deliveryId] = offer
+	return true, nil
+}
+
+// fakeDrivers stores the driver statuses and locations in memory.
+type fakeDrivers struct {
+	repo.DriverRepo
+	statuses  map[string]models.DriverStatus
+	locations []models.DriverLocation
+}
+
+func (f *fakeDrivers) GetStatus(_ context.Context, driverId string) (models.DriverStatus, error) {
+	if status, ok := f.statuses[driverId]; ok {
+		return status, nil
+	}
+	return models.DriverUnavailable, nil
+}
+
+func (f *fakeDrivers) SetStatus(_ context.Context, driverId string, status models.DriverStatus) error {
+	f.statuses[driverId] = status
+	return nil
+}
+
+func (f *fakeDrivers) ChangeStatus(ctx context.Context, driverId string, from models.DriverStatus, to models.DriverStatus) (bool, error) {
+	if status, _ := f.GetStatus(ctx, driverId); status != from {
+		return false, nil
+	}
+	f.statuses[driverId] = to
+	return true, nil
+}
+
+func (f *fakeDrivers) GetLocation(_ context.Context, driverId string) (*models.DriverLocation, error) {
+	return nil, nil
+}
+
+func (f *fakeDrivers) GetActiveDrivers(_ context.Context, _ time.Time) ([]models.DriverLocation, error) {
+	return f.locations, nil
+}
+
+// fakeUsers stores the driver statuses sent to user-service.
+type fakeUsers struct {
+	statuses map[string]models.DriverStatus
+	// err is returned by SetDriverStatus if it is set.
+	err error
+}
+
+func (f *fakeUsers) SetDriverStatus(_ context.Context, driverId string, status models.DriverStatus) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.statuses[driverId] = status
+	return nil
+}
+
+// fakeOrders stores the drivers sent to order-service.
+type fakeOrders struct {
+	drivers map[string]string
+}
+
+func (f *fakeOrders) SetOrderDelivered(context.Context, string) error               { return nil }
+func (f *fakeOrders) SetOrderFailed(context.Context, string, string) error          { return nil }
+func (f *fakeOrders) SetOrderReturned(context.Context, string) error                { return nil }
+func (f *fakeOrders) SetOrderPickUp(context.Context, string, *time.Time) error      { return nil }
+func (f *fakeOrders) SetEstimatedDelivery(context.Context, string, time.Time) error { return nil }
+
+func (f *fakeOrders) SetDeliveryDriver(_ context.Context, orderId string, driverId string) error {
+	f.drivers[orderId] = driverId
+	return nil
+}
+
+// newTestApp creates an app using fakes with the given deliveries and driver statuses.
+func newTestApp(deliveries []*models.Delivery, statuses map[string]models.DriverStatus) (*App, *fakeDeliveries, *fakeDrivers, *fakeUsers, *fakeOrders) {
+	db := &fakeDeliveries{deliveries: deliveries, offers: map[bson.ObjectID]*models.Offer{}}
+	drivers := &fakeDrivers{statuses: statuses}
+	users := &fakeUsers{statuses: map[string]models.DriverStatus{}}
+	orders := &fakeOrders{drivers: map[string]string{}}
+
+	return &App{db: db, drivers: drivers, users: users, orders: orders}, db, drivers, users, orders
+}
+
+// assignedDelivery creates a delivery in the given state assigned to the driver.
+func assignedDelivery(state models.DeliveryState, driverId string) *models.Delivery {
+	delivery := &models.Delivery{Id: bson.NewObjectID(), OrderId: bson.NewObjectID().Hex(), State: state}
+	if driverId != "" {
+		delivery.DriverId = &driverId
+	}
+	return delivery
+}
+
+func TestSetDriverOnline(t *testing.T) {
+	usersErr := errors.New("user service unavailable")
+
+	tests := []struct {
+		name       string
+		deliveries []*models.Delivery
+		current    models.DriverStatus
+		online     bool
+		usersErr   error
+		loadsErr   error
+		status     models.DriverStatus
+		err        error
+	}{
+		{name: "online", current: models.DriverUnavailable, online: true, status: models.DriverAvailable},
+		{
+			name: "online with active delivery", current: models.DriverUnavailable, online: true, status: models.DriverBusy,
+			deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateDelivering, "driver1")},
+		},
+		{
+			name: "online with finished delivery", current: models.DriverUnavailable, online: true, status: models.DriverAvailable,
+			deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateDone, "driver1"), assignedDelivery(models.DeliveryStateWaiting, "driver2")},
+		},
+		{
+			name: "offline with active delivery", current: models.DriverBusy, online: false, status: models.DriverUnavailable,
+			deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateWaiting, "driver1")},
+		},
+		{name: "not a driver", current: models.DriverUnavailable, online: true, usersErr: grpc.ErrNotDriver, err: grpc.ErrNotDriver},
+		{name: "user service error", current: models.DriverAvailable, online: false, usersErr: usersErr, err: usersErr},
+		{name: "deliveries error", current: models.DriverUnavailable, online: true, loadsErr: usersErr, err: usersErr},
+	}
+
+	for _, test := range tests {
+		app, db, drivers, users, _ := newTestApp(test.deliveries, map[string]models.DriverStatus{"driver1": test.current})
+		users.err, db.err = test.usersErr, test.loadsErr
+
+		status, err := app.SetDriverOnline(context.Background(), "driver1", test.online)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			continue
+		}
+
+		if err != nil {
+			// the local status is only changed after user-service accepts the change
+			if drivers.statuses["driver1"] != test.current {
+				t.Errorf("%s: expected status to stay %s got %s", test.name, test.current, drivers.statuses["driver1"])
+			}
+			continue
+		}
+
+		if status != test.status {
+			t.Errorf("%s: expected status %s got %s", test.name, test.status, status)
+		}
+		if drivers.statuses["driver1"] != test.status || users.statuses["driver1"] != test.status {
+			t.Errorf("%s: expected status %s to be stored got %s and %s in user-service",
+				test.name, test.status, drivers.statuses["driver1"], users.statuses["driver1"])
+		}
+	}
+}
+
+func TestChangeDriverStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  models.DriverStatus
+		from, to models.DriverStatus
+		status   models.DriverStatus
+		sent     bool
+	}{
+		{"claim", models.DriverAvailable, models.DriverAvailable, models.DriverBusy, models.DriverBusy, true},
+		{"claim while busy", models.DriverBusy, models.DriverAvailable, models.DriverBusy, models.DriverBusy, false},
+		{"freed while offline", models.DriverUnavailable, models.DriverBusy, models.DriverAvailable, models.DriverUnavailable, false},
+	}
+
+	for _, test := range tests {
+		app, _, drivers, users, _ := newTestApp(nil, map[string]models.DriverStatus{"driver1": test.current})
+
+		app.changeDriverStatus(context.Background(), "driver1", test.from, test.to)
+		if drivers.statuses["driver1"] != test.status {
+			t.Errorf("%s: expected status %s got %s", test.name, test.status, drivers.statuses["driver1"])
+		}
+		if _, sent := users.statuses["driver1"]; sent != test.sent {
+			t.Errorf("%s: expected sent to user-service to be %v got %v", test.name, test.sent, sent)
+		}
+	}
+}
+
+func TestDriverFreed(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    models.DriverStatus
+		deliveries []*models.Delivery
+		status     models.DriverStatus
+	}{
+		{"last delivery", models.DriverBusy, []*models.Delivery{assignedDelivery(models.DeliveryStateDone, "driver1")}, models.DriverAvailable},
+		{"another active delivery", models.DriverBusy, []*models.Delivery{assignedDelivery(models.DeliveryStateDelivering, "driver1")}, models.DriverBusy},
+		{"returning delivery", models.DriverBusy, []*models.Delivery{assignedDelivery(models.DeliveryStateReturning, "driver1")}, models.DriverBusy},
+		{"offline", models.DriverUnavailable, nil, models.DriverUnavailable},
+	}
+
+	for _, test := range tests {
+		app, _, drivers, _, _ := newTestApp(test.deliveries, map[string]models.DriverStatus{"driver1": test.current})
+
+		app.driverFreed(context.Background(), "driver1")
+		if drivers.statuses["driver1"] != test.status {
+			t.Errorf("%s: expected status %s got %s", test.name, test.status, drivers.statuses["driver1"])
+		}
+	}
+
+	// the status is not changed if the deliveries cannot be loaded
+	app, db, drivers, _, _ := newTestApp(nil, map[string]models.DriverStatus{"driver1": models.DriverBusy})
+	db.err = errors.New("failed")
+	app.driverFreed(context.Background(), "driver1")
+	if drivers.statuses["driver1"] != models.DriverBusy {
+		t.Errorf("expected the driver to stay busy got %s", drivers.statuses["driver1"])
+	}
+}
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+	"go.uber.org/zap"
+)
+
+// DispatchConfig contains the settings for the automatic dispatcher.
+type DispatchConfig struct {
+	// Enabled enables the dispatcher. If disabled, all deliveries are added to the open pool.
+	Enabled bool
+	// Rounds is the maximum number of offer rounds before the delivery is added to the open pool.
+	Rounds int
+	// DriversPerRound is the number of drivers the delivery is offered to in each round.
+	DriversPerRound int
+	// OfferTimeout is the time drivers have to accept an offer.
+	OfferTimeout time.Duration
+	// Interval is how often expired offers are checked.
+	Interval time.Duration
+	// MaxDistanceKm is the maximum distance between the driver and the restaurant.
+	MaxDistanceKm float64
+	// MaxLoad is the maximum number of active deliveries a driver can have to receive offers.
+	MaxLoad int
+	// LoadPenaltyKm is the distance added to the driver score for each active delivery.
+	LoadPenaltyKm float64
+	// LocationMaxAge is the maximum age of a driver location for the driver to be considered available.
+	LocationMaxAge time.Duration
+}
+
+// validate checks that the dispatcher can run with the config.
+func (c DispatchConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval <= 0 {
+		return errors.New("dispatch interval must be greater than 0")
+	}
+	return nil
+}
+
+// Dispatcher offers new deliveries to the best available drivers.
+type Dispatcher struct {
+	cfg        DispatchConfig
+	deliveries repo.DeliveryRepo
+	drivers    repo.DriverRepo
+}
+
+// NewDispatcher creates a new dispatcher.
+func NewDispatcher(cfg DispatchConfig, deliveries repo.DeliveryRepo, drivers repo.DriverRepo) *Dispatcher {
+	return &Dispatcher{cfg: cfg, deliveries: deliveries, drivers: drivers}
+}
+
+// Dispatch starts the next offer round for the delivery.
+// The delivery is added to the open pool if there are no rounds left or if there are no available drivers.
+// Drivers in exclude are never offered the delivery, in addition to the drivers it was already offered to.
+func (d *Dispatcher) Dispatch(ctx context.Context, delivery *models.Delivery, exclude ...string) error {
+	prevRound := 0
+	var offered []string
+	if delivery.Offer != nil {
+		prevRound = delivery.Offer.Round
+		offered = delivery.Offer.Offered
+	}
+	for _, driverId := range exclude {
+		if !slices.Contains(offered, driverId) {
+			offered = append(slices.Clone(offered), driverId)
+		}
+	}
+
+	var drivers []string
+	if prevRound < d.cfg.Rounds {
+		var err error
+		drivers, err = d.selectDrivers(ctx, delivery, offered)
+		if err != nil {
+			return err
+		}
+	}
+
+	log := zap.L().With(zap.String("deliveryId", delivery.Id.Hex()), zap.Int("round", prevRound+1))
+
+	// release the delivery to the open pool
+	if len(drivers) == 0 {
+		_, err := d.deliveries.SetOffer(ctx, delivery.Id, prevRound, nil)
+		if err == nil {
+			log.Info("No drivers accepted the delivery, adding to open pool")
+		}
+		return err
+	}
+
+	offer := &models.Offer{
+		Round:     prevRound + 1,
+		Drivers:   drivers,
+		Offered:   append(slices.Clone(offered), drivers...),
+		ExpiresAt: time.Now().Add(d.cfg.OfferTimeout),
+	}
+
+	ok, err := d.deliveries.SetOffer(ctx, delivery.Id, prevRound, offer)
+	if err == nil && ok {
+		log.Info("Offered delivery to drivers", zap.Strings("drivers", drivers))
+	}
+	return err
+}
+
+// selectDrivers returns the best drivers for the delivery ordered by distance and current load.
+// Drivers in exclude are not selected.
+func (d *Dispatcher) selectDrivers(ctx context.Context, delivery *models.Delivery, exclude []string) ([]string, error) {
+	drivers, err := d.drivers.GetActiveDrivers(ctx, time.Now().Add(-d.cfg.LocationMaxAge))
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := d.filterDrivers(drivers, delivery.Pickup.Location, exclude)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.driverId)
+	}
+
+	loads, err := d.deliveries.GetDriverLoads(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.rankDrivers(candidates, loads), nil
+}
+
+// candidate is a driver that can receive an offer.
+type candidate struct {
+	driverId string
+	distance float64
+	score    float64
+}
+
+// filterDrivers returns the drivers that are within the max distance of the pickup location.
+// Drivers in exclude are not included.
+func (d *Dispatcher) filterDrivers(drivers []models.DriverLocation, pickup models.Point, exclude []string) []candidate {
+	candidates := make([]candidate, 0, len(drivers))
+	for _, driver := range drivers {
+		if slices.Contains(exclude, driver.DriverId) {
+			continue
+		}
+
+		pos := driver.Position.Coordinates
+		distance := location.DistanceKm(pos[1], pos[0], pickup.Coordinates[1], pickup.Coordinates[0])
+		if distance > d.cfg.MaxDistanceKm {
+			continue
+		}
+
+		candidates = append(candidates, candidate{driverId: driver.DriverId, distance: distance})
+	}
+	return candidates
+}
+
+// rankDrivers returns the ids of the best candidates ordered by distance and current load.
+// Drivers that are at the max load are not selected.
+func (d *Dispatcher) rankDrivers(candidates []candidate, loads map[string]int) []string {
+	candidates = slices.DeleteFunc(candidates, func(c candidate) bool { return loads[c.driverId] >= d.cfg.MaxLoad })
+	for i := range candidates {
+		candidates[i].score = candidates[i].distance + float64(loads[candidates[i].driverId])*d.cfg.LoadPenaltyKm
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score < candidates[j].score })
+
+	selected := make([]string, 0, d.cfg.DriversPerRound)
+	for _, c := range candidates[:min(len(candidates), d.cfg.DriversPerRound)] {
+		selected = append(selected, c.driverId)
+	}
+
+	return selected
+}
+
+// Run starts new offer rounds for deliveries with expired offers until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deliveries, err := d.deliveries.GetExpiredOffers(ctx, time.Now())
+		if err != nil {
+			zap.L().Error("Failed to get expired offers", zap.Error(err))
+			continue
+		}
+
+		for _, delivery := range deliveries {
+			if err := d.Dispatch(ctx, delivery); err != nil {
+				zap.L().Error("Failed to dispatch delivery", zap.String("deliveryId", delivery.Id.Hex()), zap.Error(err))
+			}
+		}
+	}
+}
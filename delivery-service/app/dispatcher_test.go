@@ -0,0 +1,91 @@
+package app
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+)
+
+func TestRankDrivers(t *testing.T) {
+	dispatcher := &Dispatcher{cfg: DispatchConfig{DriversPerRound: 2, MaxLoad: 2, LoadPenaltyKm: 2}}
+
+	tests := []struct {
+		name       string
+		candidates []candidate
+		loads      map[string]int
+		expected   []string
+	}{
+		{
+			name:       "closest first",
+			candidates: []candidate{{driverId: "a", distance: 3}, {driverId: "b", distance: 1}, {driverId: "c", distance: 2}},
+			expected:   []string{"b", "c"},
+		},
+		{
+			name:       "load penalty",
+			candidates: []candidate{{driverId: "a", distance: 1}, {driverId: "b", distance: 2}},
+			loads:      map[string]int{"a": 1},
+			expected:   []string{"b", "a"},
+		},
+		{
+			name:       "max load",
+			candidates: []candidate{{driverId: "a", distance: 1}, {driverId: "b", distance: 5}},
+			loads:      map[string]int{"a": 2},
+			expected:   []string{"b"},
+		},
+		{
+			name:       "equal scores keep order",
+			candidates: []candidate{{driverId: "a", distance: 3}, {driverId: "b", distance: 1}},
+			loads:      map[string]int{"b": 1},
+			expected:   []string{"a", "b"},
+		},
+		{
+			name:       "no candidates",
+			candidates: []candidate{{driverId: "a", distance: 1}},
+			loads:      map[string]int{"a": 3},
+			expected:   []string{},
+		},
+	}
+
+	for _, test := range tests {
+		if got := dispatcher.rankDrivers(test.candidates, test.loads); !slices.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestDispatchConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg   DispatchConfig
+		valid bool
+	}{
+		{DispatchConfig{Enabled: true, Interval: time.Second}, true},
+		{DispatchConfig{Enabled: true}, false},
+		{DispatchConfig{Enabled: true, Interval: -time.Second}, false},
+		{DispatchConfig{Enabled: false}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.cfg.validate(); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid to be %v got %v", test.cfg, test.valid, err)
+		}
+	}
+}
+
+func TestFilterDrivers(t *testing.T) {
+	dispatcher := &Dispatcher{cfg: DispatchConfig{MaxDistanceKm: 5}}
+
+	// about 1km and 11km from the pickup location
+	pickup := models.Point{Coordinates: [2]float64{79.8612, 6.9271}}
+	drivers := []models.DriverLocation{
+		{DriverId: "near", Position: models.Point{Coordinates: [2]float64{79.8612, 6.9361}}},
+		{DriverId: "far", Position: models.Point{Coordinates: [2]float64{79.8612, 7.0271}}},
+		{DriverId: "released", Position: models.Point{Coordinates: [2]float64{79.8612, 6.9271}}},
+	}
+
+	candidates := dispatcher.filterDrivers(drivers, pickup, []string{"released"})
+	if len(candidates) != 1 || candidates[0].driverId != "near" {
+		t.Errorf("expected only the near driver got %v", candidates)
+	}
+}
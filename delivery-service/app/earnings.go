@@ -0,0 +1,235 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidPeriod indicates that the earnings period is not supported or the range is invalid.
+var ErrInvalidPeriod = errors.New("invalid earnings period")
+
+// EarningsConfig contains the settings for driver earnings.
+type EarningsConfig struct {
+	// DeliveryFeeShare is the percentage of the delivery fee credited to the driver.
+	DeliveryFeeShare float64
+}
+
+// EarningsPeriod is the length of the periods earnings are grouped by.
+// Periods are in UTC and weeks start on Monday.
+type EarningsPeriod string
+
+const (
+	PeriodDay  EarningsPeriod = "day"
+	PeriodWeek EarningsPeriod = "week"
+)
+
+// Start returns the start of the period containing t.
+func (p EarningsPeriod) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if p == PeriodWeek {
+		// time.Sunday is 0
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// Next returns the start of the period after the period starting at start.
+func (p EarningsPeriod) Next(start time.Time) time.Time {
+	if p == PeriodWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// creditDelivery credits the driver share of the delivery fee and the tips added at checkout to the driver of a completed delivery.
+// Errors are logged since the delivery was already completed.
+func (d *App) creditDelivery(ctx context.Context, delivery *models.Delivery) {
+	if delivery.DriverId == nil {
+		return
+	}
+
+	// deliveries created before fees were added are credited a zero fee so that they are still counted
+	fee := &models.Earning{Id: "fee:" + delivery.Id.Hex(), Type: models.EarningDeliveryFee}
+	if delivery.DeliveryFee != nil {
+		fee.Amount = delivery.DeliveryFee.Percent(d.earnings.DeliveryFeeShare)
+	} else {
+		currency := money.DefaultCurrency
+		if delivery.Tip != nil {
+			currency = delivery.Tip.Currency
+		}
+		fee.Amount = money.Zero(currency)
+		fee.MissingFee = true
+		zap.L().Warn("Completed delivery does not have a delivery fee", zap.String("deliveryId", delivery.Id.Hex()))
+	}
+
+	earnings := []*models.Earning{fee}
+	if delivery.Tip != nil && !delivery.Tip.IsZero() {
+		earnings = append(earnings, &models.Earning{
+			Id:     "tip:" + delivery.Id.Hex(),
+			Type:   models.EarningTip,
+			Amount: *delivery.Tip,
+		})
+	}
+
+	for _, earning := range earnings {
+		earning.DriverId = *delivery.DriverId
+		earning.DeliveryId = delivery.Id
+		earning.OrderId = delivery.OrderId
+		earning.CreatedAt = time.Now()
+
+		err := d.earningsDb.Credit(ctx, earning)
+		if err != nil {
+			zap.L().Error("Failed to credit driver earnings", zap.String("deliveryId", delivery.Id.Hex()), zap.Error(err))
+		}
+	}
+}
+
+// AddTip credits a tip that was paid after the order was delivered to the driver of the delivery.
+func (d *App) AddTip(ctx context.Context, orderID string, tipID string, amount money.Money) error {
+	delivery, err := d.db.GetByOrderId(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if delivery.DriverId == nil {
+		return repo.ErrNotAssigned
+	}
+
+	return d.earningsDb.Credit(ctx, &models.Earning{
+		Id:         "tip:" + tipID,
+		DriverId:   *delivery.DriverId,
+		DeliveryId: delivery.Id,
+		OrderId:    delivery.OrderId,
+		Type:       models.EarningTip,
+		Amount:     amount,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// GetEarnings returns the earnings of the driver grouped by period for the periods overlapping [from, to).
+// Periods without earnings are not included.
+func (d *App) GetEarnings(ctx context.Context, driverID string, period EarningsPeriod, from time.Time, to time.Time) ([]*models.EarningsSummary, error) {
+	if (period != PeriodDay && period != PeriodWeek) || !from.Before(to) {
+		return nil, ErrInvalidPeriod
+	}
+
+	from = period.Start(from)
+	earnings, err := d.earningsDb.GetEarnings(ctx, driverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []*models.EarningsSummary{}
+	var current *models.EarningsSummary
+	for _, earning := range earnings {
+		if current == nil || !earning.CreatedAt.Before(current.PeriodEnd) {
+			start := period.Start(earning.CreatedAt)
+			current = newSummary(start, period.Next(start), earning.Amount.Currency)
+			summaries = append(summaries, current)
+		}
+
+		err = addEarning(current, earning)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return summaries, nil
+}
+
+// GetStatement returns the payout statement of the driver for the week containing week.
+func (d *App) GetStatement(ctx context.Context, driverID string, week time.Time) (*models.EarningsStatement, error) {
+	start := PeriodWeek.Start(week)
+	end := PeriodWeek.Next(start)
+
+	earnings, err := d.earningsDb.GetEarnings(ctx, driverID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := money.DefaultCurrency
+	if len(earnings) > 0 {
+		currency = earnings[0].Amount.Currency
+	}
+
+	statement := &models.EarningsStatement{DriverId: driverID, EarningsSummary: *newSummary(start, end, currency), Entries: earnings}
+	for _, earning := range earnings {
+		err = addEarning(&statement.EarningsSummary, earning)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return statement, nil
+}
+
+// ExportEarnings writes the earnings created in [from, to) as CSV.
+// Earnings of all drivers are exported if driverID is empty.
+func (d *App) ExportEarnings(ctx context.Context, w io.Writer, driverID string, from time.Time, to time.Time) error {
+	if !from.Before(to) {
+		return ErrInvalidPeriod
+	}
+
+	earnings, err := d.earningsDb.GetEarnings(ctx, driverID, from, to)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"id", "driver_id", "delivery_id", "order_id", "type", "amount", "currency", "created_at", "missing_fee"})
+	for _, earning := range earnings {
+		_ = writer.Write([]string{
+			earning.Id,
+			earning.DriverId,
+			earning.DeliveryId.Hex(),
+			earning.OrderId,
+			string(earning.Type),
+			earning.Amount.String(),
+			string(earning.Amount.Currency),
+			earning.CreatedAt.UTC().Format(time.RFC3339),
+			strconv.FormatBool(earning.MissingFee),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// newSummary creates an empty summary for the period.
+func newSummary(start time.Time, end time.Time, currency money.Currency) *models.EarningsSummary {
+	return &models.EarningsSummary{
+		PeriodStart:  start,
+		PeriodEnd:    end,
+		DeliveryFees: money.Zero(currency),
+		Tips:         money.Zero(currency),
+		Total:        money.Zero(currency),
+	}
+}
+
+// addEarning adds the earning to the totals of the summary.
+func addEarning(summary *models.EarningsSummary, earning models.Earning) error {
+	var err error
+	switch earning.Type {
+	case models.EarningDeliveryFee:
+		summary.Deliveries++
+		summary.DeliveryFees, err = summary.DeliveryFees.Add(earning.Amount)
+	case models.EarningTip:
+		summary.Tips, err = summary.Tips.Add(earning.Amount)
+	}
+	if err != nil {
+		return err
+	}
+
+	summary.Total, err = summary.Total.Add(earning.Amount)
+	return err
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// fakeEarnings stores the earnings ledger in memory.
+type fakeEarnings struct {
+	earnings []models.Earning
+}
+
+func (f *fakeEarnings) Credit(_ context.Context, earning *models.Earning) error {
+	if !slices.ContainsFunc(f.earnings, func(e models.Earning) bool { return e.Id == earning.Id }) {
+		f.earnings = append(f.earnings, *earning)
+	}
+	return nil
+}
+
+func (f *fakeEarnings) GetEarnings(_ context.Context, driverId string, from time.Time, to time.Time) ([]models.Earning, error) {
+	result := []models.Earning{}
+	for _, earning := range f.earnings {
+		if (driverId == "" || earning.DriverId == driverId) && !earning.CreatedAt.Before(from) && earning.CreatedAt.Before(to) {
+			result = append(result, earning)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b models.Earning) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return result, nil
+}
+
+func date(day int, hour int) time.Time {
+	// 2025-03-03 is a Monday
+	return time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestEarningsPeriod(t *testing.T) {
+	colombo := time.FixedZone("Asia/Colombo", 5*60*60+30*60)
+
+	tests := []struct {
+		name   string
+		period EarningsPeriod
+		time   time.Time
+		start  time.Time
+		next   time.Time
+	}{
+		{"day", PeriodDay, date(5, 13), date(5, 0), date(6, 0)},
+		{"day start", PeriodDay, date(5, 0), date(5, 0), date(6, 0)},
+		{"day end of month", PeriodDay, time.Date(2025, time.February, 28, 23, 0, 0, 0, time.UTC), time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), date(1, 0)},
+		{"day in utc", PeriodDay, time.Date(2025, time.March, 6, 2, 0, 0, 0, colombo), date(5, 0), date(6, 0)},
+		{"week monday", PeriodWeek, date(3, 0), date(3, 0), date(10, 0)},
+		{"week wednesday", PeriodWeek, date(5, 13), date(3, 0), date(10, 0)},
+		{"week sunday", PeriodWeek, time.Date(2025, time.March, 9, 23, 59, 59, 0, time.UTC), date(3, 0), date(10, 0)},
+		// monday in colombo is still sunday in utc
+		{"week in utc", PeriodWeek, time.Date(2025, time.March, 10, 2, 0, 0, 0, colombo), date(3, 0), date(10, 0)},
+		{"week across months", PeriodWeek, date(1, 12), time.Date(2025, time.February, 24, 0, 0, 0, 0, time.UTC), date(3, 0)},
+	}
+
+	for _, test := range tests {
+		start := test.period.Start(test.time)
+		if !start.Equal(test.start) {
+			t.Errorf("%s: expected start %s got %s", test.name, test.start, start)
+		}
+		if next := test.period.Next(start); !next.Equal(test.next) {
+			t.Errorf("%s: expected next %s got %s", test.name, test.next, next)
+		}
+	}
+}
+
+func TestCreditDelivery(t *testing.T) {
+	driverId := "driver1"
+	fee := money.New(50000, money.LKR)
+	tip := money.New(20000, money.LKR)
+
+	tests := []struct {
+		name     string
+		delivery *models.Delivery
+		expected []models.Earning
+	}{
+		{
+			name:     "fee and tip",
+			delivery: &models.Delivery{DriverId: &driverId, DeliveryFee: &fee, Tip: &tip},
+			expected: []models.Earning{
+				{Type: models.EarningDeliveryFee, Amount: money.New(40000, money.LKR)},
+				{Type: models.EarningTip, Amount: tip},
+			},
+		},
+		{
+			name:     "zero tip",
+			delivery: &models.Delivery{DriverId: &driverId, DeliveryFee: &fee, Tip: &money.Money{}},
+			expected: []models.Earning{{Type: models.EarningDeliveryFee, Amount: money.New(40000, money.LKR)}},
+		},
+		{
+			name:     "missing fee",
+			delivery: &models.Delivery{DriverId: &driverId, Tip: &tip},
+			expected: []models.Earning{
+				{Type: models.EarningDeliveryFee, Amount: money.Zero(money.LKR), MissingFee: true},
+				{Type: models.EarningTip, Amount: tip},
+			},
+		},
+		{
+			name:     "no driver",
+			delivery: &models.Delivery{DeliveryFee: &fee},
+			expected: []models.Earning{},
+		},
+	}
+
+	for _, test := range tests {
+		ledger := &fakeEarnings{}
+		d := &App{earningsDb: ledger, earnings: EarningsConfig{DeliveryFeeShare: 80}}
+		test.delivery.Id = bson.NewObjectID()
+
+		d.creditDelivery(context.Background(), test.delivery)
+		// crediting the same delivery again should not add earnings
+		d.creditDelivery(context.Background(), test.delivery)
+
+		if len(ledger.earnings) != len(test.expected) {
+			t.Errorf("%s: expected %d earnings got %d", test.name, len(test.expected), len(ledger.earnings))
+			continue
+		}
+		for i, expected := range test.expected {
+			got := ledger.earnings[i]
+			if got.Type != expected.Type || !got.Amount.Equal(expected.Amount) || got.MissingFee != expected.MissingFee {
+				t.Errorf("%s: expected earning %+v got %+v", test.name, expected, got)
+			}
+			if got.DriverId != driverId || got.DeliveryId != test.delivery.Id {
+				t.Errorf("%s: earning is not linked to the delivery %+v", test.name, got)
+			}
+		}
+	}
+}
+
+func testEarnings() *fakeEarnings {
+	earning := func(id string, driverId string, kind models.EarningType, amount int64, createdAt time.Time) models.Earning {
+		return models.Earning{Id: id, DriverId: driverId, OrderId: "order-" + id, Type: kind, Amount: money.New(amount, money.LKR), CreatedAt: createdAt}
+	}
+
+	return &fakeEarnings{earnings: []models.Earning{
+		earning("1", "driver1", models.EarningDeliveryFee, 40000, date(3, 10)),
+		earning("2", "driver1", models.EarningTip, 10000, date(3, 10)),
+		earning("3", "driver1", models.EarningDeliveryFee, 30000, date(5, 18)),
+		earning("4", "driver2", models.EarningDeliveryFee, 99900, date(5, 18)),
+		// a zero fee for a delivery without a delivery fee
+		earning("5", "driver1", models.EarningDeliveryFee, 0, date(11, 9)),
+		earning("6", "driver1", models.EarningTip, 5000, date(11, 9)),
+	}}
+}
+
+func TestGetEarnings(t *testing.T) {
+	d := &App{earningsDb: testEarnings()}
+
+	type summary struct {
+		start      time.Time
+		deliveries int
+		fees       int64
+		tips       int64
+		total      int64
+	}
+
+	tests := []struct {
+		name     string
+		period   EarningsPeriod
+		from, to time.Time
+		expected []summary
+	}{
+		{
+			name: "weeks", period: PeriodWeek, from: date(4, 0), to: date(17, 0),
+			expected: []summary{{date(3, 0), 2, 70000, 10000, 80000}, {date(10, 0), 1, 0, 5000, 5000}},
+		},
+		{
+			name: "days", period: PeriodDay, from: date(3, 0), to: date(10, 0),
+			expected: []summary{{date(3, 0), 1, 40000, 10000, 50000}, {date(5, 0), 1, 30000, 0, 30000}},
+		},
+		{name: "no earnings", period: PeriodDay, from: date(20, 0), to: date(21, 0), expected: []summary{}},
+	}
+
+	for _, test := range tests {
+		summaries, err := d.GetEarnings(context.Background(), "driver1", test.period, test.from, test.to)
+		if err != nil {
+			t.Errorf("%s: failed to get earnings: %v", test.name, err)
+			continue
+		}
+
+		got := []summary{}
+		for _, s := range summaries {
+			if !s.PeriodEnd.Equal(test.period.Next(s.PeriodStart)) {
+				t.Errorf("%s: period %s ends at %s", test.name, s.PeriodStart, s.PeriodEnd)
+			}
+			got = append(got, summary{s.PeriodStart, s.Deliveries, s.DeliveryFees.Amount, s.Tips.Amount, s.Total.Amount})
+		}
+		if !slices.Equal(got, test.expected) {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+	}
+
+	invalid := []struct {
+		period   EarningsPeriod
+		from, to time.Time
+	}{
+		{"month", date(1, 0), date(20, 0)},
+		{PeriodDay, date(5, 0), date(5, 0)},
+		{PeriodWeek, date(10, 0), date(3, 0)},
+	}
+	for _, test := range invalid {
+		if _, err := d.GetEarnings(context.Background(), "driver1", test.period, test.from, test.to); !errors.Is(err, ErrInvalidPeriod) {
+			t.Errorf("%s %s-%s: expected ErrInvalidPeriod got %v", test.period, test.from, test.to, err)
+		}
+	}
+}
+
+func TestGetStatement(t *testing.T) {
+	d := &App{earningsDb: testEarnings()}
+
+	statement, err := d.GetStatement(context.Background(), "driver1", date(7, 12))
+	if err != nil {
+		t.Fatalf("failed to get statement: %v", err)
+	}
+
+	if !statement.PeriodStart.Equal(date(3, 0)) || !statement.PeriodEnd.Equal(date(10, 0)) {
+		t.Errorf("expected the week of 2025-03-03 got %s - %s", statement.PeriodStart, statement.PeriodEnd)
+	}
+	if len(statement.Entries) != 3 || statement.Deliveries != 2 || statement.Total.Amount != 80000 {
+		t.Errorf("unexpected statement %+v", statement)
+	}
+
+	empty, err := d.GetStatement(context.Background(), "driver3", date(7, 12))
+	if err != nil {
+		t.Fatalf("failed to get statement: %v", err)
+	}
+	if len(empty.Entries) != 0 || !empty.Total.Equal(money.Zero(money.DefaultCurrency)) {
+		t.Errorf("expected an empty statement got %+v", empty)
+	}
+}
+
+func TestExportEarnings(t *testing.T) {
+	d := &App{earningsDb: testEarnings()}
+
+	var buf bytes.Buffer
+	err := d.ExportEarnings(context.Background(), &buf, "", date(5, 0), date(6, 0))
+	if err != nil {
+		t.Fatalf("failed to export earnings: %v", err)
+	}
+
+	nilId := bson.NilObjectID.Hex()
+	expected := "id,driver_id,delivery_id,order_id,type,amount,currency,created_at,missing_fee\n" +
+		"3,driver1," + nilId + ",order-3,delivery_fee,300.00,LKR,2025-03-05T18:00:00Z,false\n" +
+		"4,driver2," + nilId + ",order-4,delivery_fee,999.00,LKR,2025-03-05T18:00:00Z,false\n"
+	if buf.String() != expected {
+		t.Errorf("expected csv:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	if err := d.ExportEarnings(context.Background(), &buf, "", date(6, 0), date(5, 0)); !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("expected ErrInvalidPeriod got %v", err)
+	}
+}
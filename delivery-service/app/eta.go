@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.uber.org/zap"
+)
+
+// estimate returns the estimated ready and delivery times of the delivery based on its current state.
+// driver is the last location of the assigned driver or nil if it is not known.
+// The existing estimates are returned for deliveries that are finished.
+func (d *App) estimate(delivery *models.Delivery, driver *models.Point, now time.Time) (readyAt, deliveryAt *time.Time) {
+	readyAt = delivery.EstimatedReadyAt
+	if readyAt == nil {
+		readyAt = &now
+	}
+
+	pickup, destination := delivery.Pickup.Location, delivery.Destination.Position
+
+	var pickupAt time.Time
+	switch delivery.State {
+	case models.DeliveryStateUnclaimed:
+		pickupAt = later(*readyAt, now)
+	case models.DeliveryStateWaiting:
+		// the driver has to reach the restaurant before the order can be picked up
+		arrival := now
+		if driver != nil {
+			arrival = now.Add(d.travelTime(*driver, pickup))
+		}
+		pickupAt = later(*readyAt, arrival)
+	case models.DeliveryStateDelivering:
+		from := pickup
+		if driver != nil {
+			from = *driver
+		}
+		delivered := now.Add(d.travelTime(from, destination))
+		return readyAt, &delivered
+	default:
+		return delivery.EstimatedReadyAt, delivery.EstimatedDeliveryAt
+	}
+
+	delivered := pickupAt.Add(d.travelTime(pickup, destination))
+	return readyAt, &delivered
+}
+
+// updateEstimate recalculates the estimated times of the delivery after its state changed
+// using the last location of the assigned driver.
+// The delivery is updated in place. Errors are logged since estimates are not required to complete the change.
+func (d *App) updateEstimate(ctx context.Context, delivery *models.Delivery) {
+	log := zap.L().With(zap.String("deliveryId", delivery.Id.Hex()))
+
+	var driver *models.Point
+	if delivery.DriverId != nil {
+		location, err := d.drivers.GetLocation(ctx, *delivery.DriverId)
+		if err != nil {
+			log.Error("Failed to get driver location", zap.Error(err))
+		} else if location != nil && time.Since(location.UpdatedAt) <= d.locationMaxAge {
+			driver = &location.Position
+		}
+	}
+
+	delivery.EstimatedReadyAt, delivery.EstimatedDeliveryAt = d.estimate(delivery, driver, time.Now())
+
+	err := d.db.SetEstimates(ctx, delivery.Id, delivery.EstimatedReadyAt, delivery.EstimatedDeliveryAt)
+	if err != nil {
+		log.Error("Failed to update delivery estimate", zap.Error(err))
+	}
+}
+
+// sendEstimate sends the estimated delivery time of a claimed delivery to order-service.
+func (d *App) sendEstimate(ctx context.Context, delivery *models.Delivery) {
+	if delivery.EstimatedDeliveryAt == nil {
+		return
+	}
+
+	err := d.orders.SetEstimatedDelivery(ctx, delivery.OrderId, *delivery.EstimatedDeliveryAt)
+	if err != nil {
+		zap.L().Error("Failed to send delivery estimate", zap.String("deliveryId", delivery.Id.Hex()), zap.Error(err))
+	}
+}
+
+// travelTime returns the time needed to travel between two points.
+func (d *App) travelTime(from, to models.Point) time.Duration {
+	return d.eta.TravelTime(from.Coordinates[1], from.Coordinates[0], to.Coordinates[1], to.Coordinates[0])
+}
+
+// later returns the later of the two times.
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,61 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+)
+
+// FailDelivery marks a delivery that could not be completed as failed.
+// If the order was already picked up and can be returned, the driver must return it to the restaurant
+// and complete the return using [App.ReturnDelivery].
+func (d *App) FailDelivery(ctx context.Context, driverID string, deliveryID bson.ObjectID, reason models.FailureReason, note string) (*models.Delivery, error) {
+	delivery, err := d.db.GetAssigned(ctx, deliveryID, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := models.DeliveryStateFailed
+	if delivery.State == models.DeliveryStateDelivering && reason.ReturnToRestaurant() {
+		next = models.DeliveryStateReturning
+	}
+
+	failure := &models.DeliveryFailure{Reason: reason, Note: note, FailedAt: time.Now()}
+	delivery, err = d.db.FailDelivery(ctx, deliveryID, driverID, next, failure)
+	if err != nil {
+		return nil, err
+	}
+	deliveriesFailed.WithLabelValues(string(reason)).Inc()
+
+	err = d.orders.SetOrderFailed(ctx, delivery.OrderId, string(reason))
+	if err != nil {
+		zap.L().Error("Failed to send order state update", zap.Error(err))
+	}
+
+	// drivers returning an order stay busy until the order is returned
+	if next == models.DeliveryStateFailed {
+		d.driverFreed(ctx, driverID)
+	}
+
+	return delivery, nil
+}
+
+// ReturnDelivery marks a failed delivery as returned to the restaurant.
+func (d *App) ReturnDelivery(ctx context.Context, driverID string, deliveryID bson.ObjectID) (*models.Delivery, error) {
+	delivery, err := d.db.DeliveryReturned(ctx, deliveryID, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.orders.SetOrderReturned(ctx, delivery.OrderId)
+	if err != nil {
+		zap.L().Error("Failed to send order state update", zap.Error(err))
+	}
+
+	d.driverFreed(ctx, driverID)
+
+	return delivery, nil
+}
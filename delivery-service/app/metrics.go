@@ -0,0 +1,28 @@
+package app
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	deliveriesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "deliveries_created_total",
+		Help: "Number of deliveries created for orders.",
+	})
+
+	deliveriesClaimed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "deliveries_claimed_total",
+		Help: "Number of deliveries claimed by drivers, by whether the delivery was claimed alone or in a batch.",
+	}, []string{"source"})
+
+	deliveriesCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "deliveries_completed_total",
+		Help: "Number of deliveries completed by drivers.",
+	})
+
+	deliveriesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "deliveries_failed_total",
+		Help: "Number of deliveries that failed by reason.",
+	}, []string{"reason"})
+)
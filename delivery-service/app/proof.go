@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+)
+
+// ErrProofRequired indicates that the driver did not submit a PIN or a photo with their location.
+var ErrProofRequired = errors.New("proof of delivery is required")
+
+// ErrIncorrectPin indicates that the delivery PIN entered by the driver is incorrect.
+var ErrIncorrectPin = errors.New("incorrect delivery pin")
+
+// ErrNoPin indicates that the delivery was created without a PIN and must be completed with a photo.
+var ErrNoPin = errors.New("delivery does not have a pin")
+
+// ErrPinLocked indicates that the driver entered an incorrect PIN too many times.
+// The delivery can still be completed with a photo.
+var ErrPinLocked = errors.New("too many incorrect pin attempts")
+
+// ErrInvalidPhoto indicates that the photo was not uploaded by the driver.
+var ErrInvalidPhoto = errors.New("photo was not uploaded by the driver")
+
+// ErrTooFar indicates that the driver is too far from the destination to complete the delivery with a photo.
+var ErrTooFar = errors.New("driver is too far from the destination")
+
+// pinDigits is the number of digits in a delivery PIN.
+const pinDigits = 4
+
+// ProofConfig contains the settings for proof of delivery.
+type ProofConfig struct {
+	// MaxDistanceKm is the maximum distance from the destination for completing a delivery with a photo.
+	MaxDistanceKm float64
+	// MaxPinAttempts is the number of times the driver can enter the PIN of a delivery.
+	MaxPinAttempts int
+}
+
+func (c ProofConfig) validate() error {
+	if c.MaxPinAttempts <= 0 {
+		return errors.New("proof max pin attempts must be greater than 0")
+	}
+	return nil
+}
+
+// Proof is the proof of delivery submitted by the driver.
+// Either the PIN or a photo with the location of the driver is required.
+type Proof struct {
+	Pin   string
+	Photo string
+	// Authorization is the Authorization header of the driver. It is used to check that the photo exists.
+	Authorization string
+	// Position is the location of the driver as [longitude, latitude]. This is nil if the location was not sent.
+	Position *[2]float64
+}
+
+// GetCustomerDelivery gets the delivery of an order placed by the user along with the delivery PIN.
+// The PIN is empty once the delivery is completed.
+// Deliveries created before PINs were added are given a PIN the first time they are viewed.
+func (d *App) GetCustomerDelivery(ctx context.Context, userID string, orderID string) (*models.Delivery, string, error) {
+	delivery, err := d.db.GetByOrderId(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if delivery.UserId != userID {
+		return nil, "", repo.ErrNoDelivery
+	}
+
+	if delivery.State == models.DeliveryStateDone {
+		return delivery, "", nil
+	}
+
+	if delivery.Pin == "" {
+		pin, err := newPin()
+		if err != nil {
+			return nil, "", err
+		}
+
+		delivery.Pin, err = d.db.EnsurePin(ctx, delivery.Id, pin)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	return delivery, delivery.Pin, nil
+}
+
+// usePinAttempt records a PIN attempt by the driver.
+// It returns [ErrPinLocked] if the driver has no attempts left and true if this is the last attempt.
+func (d *App) usePinAttempt(ctx context.Context, driverID string, delivery *models.Delivery) (bool, error) {
+	attempts, err := d.db.UsePinAttempt(ctx, delivery.Id, driverID, d.proof.MaxPinAttempts)
+	if err != nil {
+		if errors.Is(err, repo.ErrPinLocked) {
+			return false, ErrPinLocked
+		}
+		return false, err
+	}
+
+	return attempts >= d.proof.MaxPinAttempts, nil
+}
+
+// checkProof checks the proof submitted by the driver and returns the proof to store on the delivery.
+// Attempts are not counted here, see [App.usePinAttempt].
+func (d *App) checkProof(ctx context.Context, driverID string, delivery *models.Delivery, proof *Proof) (*models.DeliveryProof, error) {
+	result := &models.DeliveryProof{SubmittedAt: time.Now()}
+
+	if proof.Position != nil {
+		dest := delivery.Destination.Position.Coordinates
+		distance := location.DistanceKm(proof.Position[1], proof.Position[0], dest[1], dest[0])
+
+		result.Position = &models.Point{Type: "point", Coordinates: *proof.Position}
+		result.DistanceKm = &distance
+	}
+
+	switch {
+	case proof.Pin != "":
+		if delivery.Pin == "" {
+			return nil, ErrNoPin
+		}
+		if subtle.ConstantTimeCompare([]byte(proof.Pin), []byte(delivery.Pin)) != 1 {
+			return nil, ErrIncorrectPin
+		}
+		result.Method = models.ProofPin
+
+	case proof.Photo != "" && proof.Position != nil:
+		if *result.DistanceKm > d.proof.MaxDistanceKm {
+			return nil, ErrTooFar
+		}
+		if err := d.checkPhoto(ctx, driverID, proof); err != nil {
+			return nil, err
+		}
+		result.Method = models.ProofPhoto
+		result.Photo = proof.Photo
+
+	default:
+		return nil, ErrProofRequired
+	}
+
+	return result, nil
+}
+
+// checkPhoto checks that the photo was uploaded to the user files of the driver.
+func (d *App) checkPhoto(ctx context.Context, driverID string, proof *Proof) error {
+	// photos are stored at <prefix>/uploads/user/<user id>/<file id>
+	dir, file := path.Split(proof.Photo)
+	if file == "" || !strings.HasSuffix(dir, "uploads/user/"+driverID+"/") {
+		return ErrInvalidPhoto
+	}
+
+	exists, err := d.uploads.Exists(ctx, driverID, file, proof.Authorization)
+	if err != nil {
+		return fmt.Errorf("failed to check photo: %w", err)
+	}
+	if !exists {
+		return ErrInvalidPhoto
+	}
+
+	return nil
+}
+
+// newPin generates a random delivery PIN.
+func newPin() (string, error) {
+	pin := make([]byte, pinDigits)
+	for i := range pin {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		pin[i] = byte('0' + n.Int64())
+	}
+	return string(pin), nil
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+)
+
+// fakeUploads contains the uploaded files as user id/file id.
+type fakeUploads map[string]bool
+
+func (f fakeUploads) Exists(_ context.Context, userID string, fileID string, authorization string) (bool, error) {
+	if authorization != "Bearer token" {
+		return false, errors.New("missing authorization")
+	}
+	return f[userID+"/"+fileID], nil
+}
+
+func TestCheckProof(t *testing.T) {
+	d := &App{
+		proof:   ProofConfig{MaxDistanceKm: 0.2, MaxPinAttempts: 3},
+		uploads: fakeUploads{"driver1/photo1": true, "driver2/photo2": true},
+	}
+
+	// the destination is at (79.8, 6.9). 0.001 degrees of latitude is about 0.11km.
+	delivery := &models.Delivery{Pin: "1234", Destination: models.Address{Position: models.Point{Coordinates: [2]float64{79.8, 6.9}}}}
+	legacy := &models.Delivery{Destination: delivery.Destination}
+	near := &[2]float64{79.8, 6.901}
+	far := &[2]float64{79.8, 6.91}
+
+	tests := []struct {
+		name     string
+		delivery *models.Delivery
+		proof    Proof
+		method   models.ProofMethod
+		err      error
+	}{
+		{name: "correct pin", delivery: delivery, proof: Proof{Pin: "1234"}, method: models.ProofPin},
+		{name: "correct pin with position", delivery: delivery, proof: Proof{Pin: "1234", Position: far}, method: models.ProofPin},
+		{name: "incorrect pin", delivery: delivery, proof: Proof{Pin: "4321"}, err: ErrIncorrectPin},
+		{name: "legacy delivery pin", delivery: legacy, proof: Proof{Pin: "1234"}, err: ErrNoPin},
+		{
+			name: "photo", delivery: delivery, method: models.ProofPhoto,
+			proof: Proof{Photo: "/api/v1/uploads/user/driver1/photo1", Authorization: "Bearer token", Position: near},
+		},
+		{
+			name: "legacy delivery photo", delivery: legacy, method: models.ProofPhoto,
+			proof: Proof{Photo: "uploads/user/driver1/photo1", Authorization: "Bearer token", Position: near},
+		},
+		{
+			name: "photo too far", delivery: delivery, err: ErrTooFar,
+			proof: Proof{Photo: "/api/v1/uploads/user/driver1/photo1", Authorization: "Bearer token", Position: far},
+		},
+		{
+			name: "photo of another driver", delivery: delivery, err: ErrInvalidPhoto,
+			proof: Proof{Photo: "/api/v1/uploads/user/driver2/photo2", Authorization: "Bearer token", Position: near},
+		},
+		{
+			name: "photo not uploaded", delivery: delivery, err: ErrInvalidPhoto,
+			proof: Proof{Photo: "/api/v1/uploads/user/driver1/photo2", Authorization: "Bearer token", Position: near},
+		},
+		{
+			name: "public photo", delivery: delivery, err: ErrInvalidPhoto,
+			proof: Proof{Photo: "/api/v1/uploads/public/driver1/photo1", Authorization: "Bearer token", Position: near},
+		},
+		{
+			name: "photo directory", delivery: delivery, err: ErrInvalidPhoto,
+			proof: Proof{Photo: "/api/v1/uploads/user/driver1/", Authorization: "Bearer token", Position: near},
+		},
+		{name: "photo without position", delivery: delivery, proof: Proof{Photo: "/api/v1/uploads/user/driver1/photo1"}, err: ErrProofRequired},
+		{name: "no proof", delivery: delivery, proof: Proof{Position: near}, err: ErrProofRequired},
+	}
+
+	for _, test := range tests {
+		result, err := d.checkProof(context.Background(), "driver1", test.delivery, &test.proof)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if result.Method != test.method {
+			t.Errorf("%s: expected method %s got %s", test.name, test.method, result.Method)
+		}
+		if test.method == models.ProofPhoto && result.Photo != test.proof.Photo {
+			t.Errorf("%s: expected photo %s got %s", test.name, test.proof.Photo, result.Photo)
+		}
+		if test.proof.Position != nil && (result.Position == nil || result.DistanceKm == nil) {
+			t.Errorf("%s: expected the position to be stored", test.name)
+		}
+	}
+}
+
+func TestCheckProofUploadError(t *testing.T) {
+	d := &App{proof: ProofConfig{MaxDistanceKm: 0.2}, uploads: fakeUploads{"driver1/photo1": true}}
+	delivery := &models.Delivery{Destination: models.Address{Position: models.Point{Coordinates: [2]float64{79.8, 6.9}}}}
+
+	_, err := d.checkProof(context.Background(), "driver1", delivery, &Proof{Photo: "/uploads/user/driver1/photo1", Position: &[2]float64{79.8, 6.9}})
+	if err == nil || errors.Is(err, ErrInvalidPhoto) {
+		t.Errorf("expected the upload error to be returned got %v", err)
+	}
+}
+
+func TestProofConfigValidate(t *testing.T) {
+	if err := (ProofConfig{MaxPinAttempts: 5}).validate(); err != nil {
+		t.Errorf("expected config to be valid got %v", err)
+	}
+	if err := (ProofConfig{}).validate(); err == nil {
+		t.Error("expected an error when maxPinAttempts is 0")
+	}
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.uber.org/zap"
+)
+
+// ReleaseConfig contains the settings for releasing deliveries that were claimed but not picked up.
+type ReleaseConfig struct {
+	// ClaimTimeout is the time a driver has to pick up a claimed delivery before it is released.
+	// Deliveries are never released automatically if this is 0.
+	ClaimTimeout time.Duration
+	// Interval is how often claimed deliveries are checked.
+	Interval time.Duration
+}
+
+// validate checks that the releaser can run with the config.
+func (c ReleaseConfig) validate() error {
+	if c.ClaimTimeout > 0 && c.Interval <= 0 {
+		return errors.New("release interval must be greater than 0")
+	}
+	return nil
+}
+
+// ReleaseDelivery removes the driver from a delivery that has not been picked up.
+func (d *App) ReleaseDelivery(ctx context.Context, driverID string, deliveryID bson.ObjectID) (*models.Delivery, error) {
+	delivery, err := d.db.ReleaseDelivery(ctx, deliveryID, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	d.afterRelease(ctx, delivery, driverID)
+	return delivery, nil
+}
+
+// ReassignDelivery assigns a delivery that has not been picked up to another driver.
+func (d *App) ReassignDelivery(ctx context.Context, deliveryID bson.ObjectID, driverID string) (*models.Delivery, error) {
+	if err := d.requireOnline(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	delivery, prevDriver, err := d.db.ReassignDelivery(ctx, deliveryID, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if prevDriver != nil && *prevDriver != driverID {
+		d.driverFreed(ctx, *prevDriver)
+	}
+	d.changeDriverStatus(ctx, driverID, models.DriverAvailable, models.DriverBusy)
+	d.updateEstimate(ctx, delivery)
+
+	err = d.orders.SetDeliveryDriver(ctx, delivery.OrderId, driverID)
+	if err != nil {
+		zap.L().Error("Failed to send order state update", zap.Error(err))
+	}
+	d.sendEstimate(ctx, delivery)
+
+	return delivery, nil
+}
+
+// RunReleaser releases deliveries that were not picked up within the claim timeout until ctx is cancelled.
+// This does nothing if the claim timeout is not set.
+func (d *App) RunReleaser(ctx context.Context) {
+	if d.release.ClaimTimeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(d.release.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		d.releaseStale(ctx)
+	}
+}
+
+// releaseStale releases the deliveries that were not picked up within the claim timeout.
+func (d *App) releaseStale(ctx context.Context) {
+	deliveries, err := d.db.GetStaleClaims(ctx, time.Now().Add(-d.release.ClaimTimeout))
+	if err != nil {
+		zap.L().Error("Failed to get claimed deliveries", zap.Error(err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.DriverId == nil {
+			continue
+		}
+		driverID := *delivery.DriverId
+		log := zap.L().With(zap.String("deliveryId", delivery.Id.Hex()), zap.String("driverId", driverID))
+
+		released, err := d.db.ReleaseDelivery(ctx, delivery.Id, driverID)
+		if err != nil {
+			// the delivery was picked up or released after it was loaded
+			var stateErr *repo.InvalidStateError
+			if !errors.As(err, &stateErr) && !errors.Is(err, repo.ErrNotAssigned) {
+				log.Error("Failed to release delivery", zap.Error(err))
+			}
+			continue
+		}
+
+		log.Info("Released delivery that was not picked up")
+		d.afterRelease(ctx, released, driverID)
+	}
+}
+
+// afterRelease removes the driver from the order and offers the delivery to other drivers.
+func (d *App) afterRelease(ctx context.Context, delivery *models.Delivery, driverID string) {
+	d.updateEstimate(ctx, delivery)
+
+	err := d.orders.SetDeliveryDriver(ctx, delivery.OrderId, "")
+	if err != nil {
+		zap.L().Error("Failed to send order state update", zap.Error(err))
+	}
+
+	d.driverFreed(ctx, driverID)
+
+	// the driver released the delivery, so it is not offered to them again
+	if d.dispatcher != nil {
+		err = d.dispatcher.Dispatch(ctx, delivery, driverID)
+		if err != nil {
+			zap.L().Error("Failed to dispatch delivery", zap.String("deliveryId", delivery.Id.Hex()), zap.Error(err))
+		}
+	}
+}
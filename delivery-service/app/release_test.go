@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestReleaseConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg   ReleaseConfig
+		valid bool
+	}{
+		{ReleaseConfig{ClaimTimeout: time.Minute, Interval: time.Second}, true},
+		{ReleaseConfig{ClaimTimeout: time.Minute}, false},
+		{ReleaseConfig{ClaimTimeout: time.Minute, Interval: -time.Second}, false},
+		// deliveries are not released, so the interval is not used
+		{ReleaseConfig{}, true},
+	}
+
+	for _, test := range tests {
+		if err := test.cfg.validate(); (err == nil) != test.valid {
+			t.Errorf("%+v: expected valid to be %v got %v", test.cfg, test.valid, err)
+		}
+	}
+}
+
+// claimedDelivery creates a delivery claimed by the driver at the given time.
+func claimedDelivery(driverId string, claimedAt time.Time) *models.Delivery {
+	delivery := assignedDelivery(models.DeliveryStateWaiting, driverId)
+	delivery.Timestamps.ClaimedAt = &claimedAt
+	return delivery
+}
+
+func TestReleaseDelivery(t *testing.T) {
+	tests := []struct {
+		name string
+		// deliveries[0] is released by driver1
+		deliveries []*models.Delivery
+		err        error
+		status     models.DriverStatus
+	}{
+		{name: "released", deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateWaiting, "driver1")}, status: models.DriverAvailable},
+		{
+			name:   "another active delivery",
+			status: models.DriverBusy,
+			deliveries: []*models.Delivery{
+				assignedDelivery(models.DeliveryStateWaiting, "driver1"), assignedDelivery(models.DeliveryStateDelivering, "driver1"),
+			},
+		},
+		{name: "other driver", deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateWaiting, "driver2")}, err: repo.ErrNotAssigned, status: models.DriverBusy},
+		{name: "picked up", deliveries: []*models.Delivery{assignedDelivery(models.DeliveryStateDelivering, "driver1")}, err: &repo.InvalidStateError{}, status: models.DriverBusy},
+	}
+
+	for _, test := range tests {
+		app, _, drivers, _, orders := newTestApp(test.deliveries, map[string]models.DriverStatus{"driver1": models.DriverBusy})
+		delivery := test.deliveries[0]
+
+		released, err := app.ReleaseDelivery(context.Background(), "driver1", delivery.Id)
+		if test.err != nil {
+			var stateErr *repo.InvalidStateError
+			if !errors.Is(err, test.err) && !(errors.As(test.err, &stateErr) && errors.As(err, &stateErr)) {
+				t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: expected no error got %v", test.name, err)
+		} else if released.State != models.DeliveryStateUnclaimed || released.DriverId != nil {
+			t.Errorf("%s: expected the delivery to be unclaimed got %s %v", test.name, released.State, released.DriverId)
+		}
+
+		if driver, sent := orders.drivers[delivery.OrderId]; sent != (test.err == nil) || driver != "" {
+			t.Errorf("%s: expected the driver to be removed from the order only if released got %q %v", test.name, driver, sent)
+		}
+		if drivers.statuses["driver1"] != test.status {
+			t.Errorf("%s: expected driver status %s got %s", test.name, test.status, drivers.statuses["driver1"])
+		}
+	}
+}
+
+func TestReassignDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		delivery *models.Delivery
+		// other contains other deliveries of the previous driver
+		other     []*models.Delivery
+		driver    string
+		err       error
+		statuses  map[string]models.DriverStatus
+		prevFreed bool
+	}{
+		{name: "unclaimed", delivery: assignedDelivery(models.DeliveryStateUnclaimed, ""), driver: "driver2"},
+		{name: "claimed", delivery: assignedDelivery(models.DeliveryStateWaiting, "driver1"), driver: "driver2", prevFreed: true},
+		{
+			name: "previous driver has another delivery", delivery: assignedDelivery(models.DeliveryStateWaiting, "driver1"), driver: "driver2",
+			other: []*models.Delivery{assignedDelivery(models.DeliveryStateDelivering, "driver1")},
+		},
+		{name: "same driver", delivery: assignedDelivery(models.DeliveryStateWaiting, "driver1"), driver: "driver1"},
+		{name: "offline driver", delivery: assignedDelivery(models.DeliveryStateWaiting, "driver1"), driver: "driver3", err: repo.ErrDriverOffline},
+	}
+
+	for _, test := range tests {
+		statuses := map[string]models.DriverStatus{"driver1": models.DriverBusy, "driver2": models.DriverAvailable}
+		batchId := bson.NewObjectID()
+		test.delivery.BatchId = &batchId
+		app, _, drivers, users, orders := newTestApp(append([]*models.Delivery{test.delivery}, test.other...), statuses)
+
+		delivery, err := app.ReassignDelivery(context.Background(), test.delivery.Id, test.driver)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %v got %v", test.name, test.err, err)
+			continue
+		}
+		if err != nil {
+			if drivers.statuses["driver1"] != models.DriverBusy || test.delivery.DriverId == nil || *test.delivery.DriverId != "driver1" {
+				t.Errorf("%s: expected the delivery and driver to be unchanged", test.name)
+			}
+			continue
+		}
+
+		if delivery.DriverId == nil || *delivery.DriverId != test.driver || delivery.BatchId != nil {
+			t.Errorf("%s: expected the delivery to be assigned to %s without a batch got %v %v", test.name, test.driver, delivery.DriverId, delivery.BatchId)
+		}
+		if orders.drivers[delivery.OrderId] != test.driver {
+			t.Errorf("%s: expected the order driver to be %s got %s", test.name, test.driver, orders.drivers[delivery.OrderId])
+		}
+		if drivers.statuses[test.driver] != models.DriverBusy {
+			t.Errorf("%s: expected the new driver to be busy got %s", test.name, drivers.statuses[test.driver])
+		}
+
+		if test.driver != "driver1" {
+			prevStatus := models.DriverBusy
+			if test.prevFreed {
+				prevStatus = models.DriverAvailable
+			}
+			if drivers.statuses["driver1"] != prevStatus {
+				t.Errorf("%s: expected the previous driver to be %s got %s", test.name, prevStatus, drivers.statuses["driver1"])
+			}
+			if _, sent := users.statuses["driver1"]; sent != test.prevFreed {
+				t.Errorf("%s: expected the previous driver status to be sent to user-service only if freed", test.name)
+			}
+		}
+	}
+}
+
+func TestReleaseStale(t *testing.T) {
+	now := time.Now()
+	stale := claimedDelivery("driver1", now.Add(-time.Hour))
+	recent := claimedDelivery("driver2", now)
+
+	app, db, drivers, _, orders := newTestApp(
+		[]*models.Delivery{stale, recent},
+		map[string]models.DriverStatus{"driver1": models.DriverBusy, "driver2": models.DriverBusy},
+	)
+	app.release = ReleaseConfig{ClaimTimeout: time.Minute, Interval: time.Second}
+
+	// the deliveries change after they are loaded
+	pickedUp := claimedDelivery("driver3", now.Add(-time.Hour))
+	reassigned := claimedDelivery("driver4", now.Add(-time.Hour))
+	db.deliveries = append(db.deliveries, pickedUp, reassigned)
+	loaded, _ := db.GetStaleClaims(context.Background(), now.Add(-time.Minute))
+	pickedUp.State = models.DeliveryStateDelivering
+	newDriver := "driver5"
+	reassigned.DriverId = &newDriver
+
+	app.db = &loadedDeliveries{fakeDeliveries: db, loaded: loaded}
+	app.releaseStale(context.Background())
+
+	if stale.State != models.DeliveryStateUnclaimed || stale.DriverId != nil {
+		t.Errorf("expected the stale delivery to be released got %s", stale.State)
+	}
+	if driver, ok := orders.drivers[stale.OrderId]; !ok || driver != "" {
+		t.Errorf("expected the driver to be removed from the order got %q %v", driver, ok)
+	}
+	if drivers.statuses["driver1"] != models.DriverAvailable {
+		t.Errorf("expected the driver of the stale delivery to be available got %s", drivers.statuses["driver1"])
+	}
+
+	if recent.State != models.DeliveryStateWaiting || drivers.statuses["driver2"] != models.DriverBusy {
+		t.Errorf("expected the recent delivery to stay claimed got %s", recent.State)
+	}
+	if pickedUp.State != models.DeliveryStateDelivering || reassigned.DriverId != &newDriver {
+		t.Error("expected deliveries that changed after they were loaded to be skipped")
+	}
+	if len(orders.drivers) != 1 {
+		t.Errorf("expected only the stale delivery to be released got %v", orders.drivers)
+	}
+}
+
+// loadedDeliveries returns deliveries that were loaded before they were changed from GetStaleClaims.
+type loadedDeliveries struct {
+	*fakeDeliveries
+	loaded []*models.Delivery
+}
+
+func (l *loadedDeliveries) GetStaleClaims(context.Context, time.Time) ([]*models.Delivery, error) {
+	return l.loaded, nil
+}
+
+func TestAfterReleaseDispatch(t *testing.T) {
+	// the restaurant and drivers are at about the same position
+	pickup := [2]float64{79.8612, 6.9271}
+	delivery := assignedDelivery(models.DeliveryStateWaiting, "driver1")
+	delivery.Pickup.Location.Coordinates = pickup
+
+	app, db, drivers, _, _ := newTestApp([]*models.Delivery{delivery}, map[string]models.DriverStatus{"driver1": models.DriverBusy})
+	drivers.locations = []models.DriverLocation{
+		{DriverId: "driver1", Position: models.Point{Coordinates: pickup}},
+		{DriverId: "driver2", Position: models.Point{Coordinates: pickup}},
+	}
+	app.dispatcher = NewDispatcher(DispatchConfig{Enabled: true, Rounds: 3, DriversPerRound: 2, MaxLoad: 2, MaxDistanceKm: 5}, db, drivers)
+
+	if _, err := app.ReleaseDelivery(context.Background(), "driver1", delivery.Id); err != nil {
+		t.Fatalf("failed to release delivery: %s", err)
+	}
+
+	offer := db.offers[delivery.Id]
+	if offer == nil {
+		t.Fatal("expected the delivery to be offered to other drivers")
+	}
+	if !slices.Equal(offer.Drivers, []string{"driver2"}) {
+		t.Errorf("expected the delivery to be offered to driver2 got %v", offer.Drivers)
+	}
+	if !slices.Contains(offer.Offered, "driver1") {
+		t.Errorf("expected the released driver to be excluded from later rounds got %v", offer.Offered)
+	}
+}
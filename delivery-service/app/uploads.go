@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Uploads checks the files uploaded to upload-service.
+type Uploads interface {
+	// Exists checks if the user uploaded the file.
+	// authorization is the Authorization header of the user, since user files can only be read by their owner.
+	Exists(ctx context.Context, userID string, fileID string, authorization string) (bool, error)
+}
+
+// uploadClient checks files using the http api of upload-service.
+type uploadClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewUploadClient creates a client for the upload-service at baseURL.
+func NewUploadClient(baseURL string, timeout time.Duration) Uploads {
+	return &uploadClient{baseURL: strings.TrimSuffix(baseURL, "/"), client: &http.Client{Timeout: timeout}}
+}
+
+// Exists implements Uploads.
+func (u *uploadClient) Exists(ctx context.Context, userID string, fileID string, authorization string) (bool, error) {
+	target := u.baseURL + "/uploads/user/" + url.PathEscape(userID) + "/" + url.PathEscape(fileID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", authorization)
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound, http.StatusBadRequest:
+		return false, nil
+	default:
+		return false, fmt.Errorf("upload service returned status %d", res.StatusCode)
+	}
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"context"
 	_ "embed"
 
 	service "github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/config"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"go.uber.org/zap"
 )
 
@@ -18,6 +22,24 @@ func main() {
 
 	serverCtx := shared.AppContext()
 
+	shutdownTracing, err := tracing.Setup(serverCtx, "delivery-service", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Failed to setup tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background()) //nolint: errcheck
+
+	serviceKey, err := config.LoadServiceKey()
+	if err != nil {
+		zap.L().Fatal("Failed to load service key", zap.Error(err))
+	}
+	serviceKeys, err := config.LoadServicePublicKeys()
+	if err != nil {
+		zap.L().Fatal("Failed to load service public keys", zap.Error(err))
+	}
+	if err := grpcauth.Setup("delivery-service", serviceKey, serviceKeys); err != nil {
+		zap.L().Fatal("Failed to setup service authentication", zap.Error(err))
+	}
+
 	con, err := database.ConnectMongo(serverCtx, cfg.Database)
 	if err != nil {
 		zap.L().Panic("Failed to connect to the database", zap.Error(err))
@@ -26,6 +48,14 @@ func main() {
 
 	defer con.Disconnect(serverCtx)
 
+	exit, err := migrate.Startup(serverCtx, con.Database("delivery-service"), service.Migrations, cfg.Database.Migrate)
+	if err != nil {
+		zap.L().Fatal("Failed to apply migrations", zap.Error(err))
+	}
+	if exit {
+		return
+	}
+
 	s, err := service.New(cfg, con)
 	if err != nil {
 		zap.L().Fatal("Failed to setup server", zap.Error(err))
@@ -2,10 +2,11 @@ package grpc
 
 import (
 	"context"
+	"time"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc/proto"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type OrderClient struct {
@@ -21,10 +22,45 @@ func (o *OrderClient) SetOrderDelivered(ctx context.Context, orderId string) err
 	return err
 }
 
-func (o *OrderClient) SetOrderPickUp(ctx context.Context, orderId string) error {
+func (o *OrderClient) SetOrderFailed(ctx context.Context, orderId string, reason string) error {
+	_, err := o.client.SetDeliveryStatus(ctx, &proto.DeliveryProgress{
+		OrderId:       orderId,
+		Status:        proto.DeliveryStatus_Failed,
+		FailureReason: reason,
+	})
+
+	return err
+}
+
+func (o *OrderClient) SetOrderReturned(ctx context.Context, orderId string) error {
 	_, err := o.client.SetDeliveryStatus(ctx, &proto.DeliveryProgress{
+		OrderId: orderId,
+		Status:  proto.DeliveryStatus_Returned,
+	})
+
+	return err
+}
+
+// SetOrderPickUp marks the order as picked up and sends the estimated delivery time if it is not nil.
+func (o *OrderClient) SetOrderPickUp(ctx context.Context, orderId string, deliveryAt *time.Time) error {
+	progress := &proto.DeliveryProgress{
 		OrderId: orderId,
 		Status:  proto.DeliveryStatus_Delivering,
+	}
+	if deliveryAt != nil {
+		progress.EstimatedDeliveryAt = timestamppb.New(*deliveryAt)
+	}
+
+	_, err := o.client.SetDeliveryStatus(ctx, progress)
+	return err
+}
+
+// SetEstimatedDelivery sends the estimated delivery time of an order that is waiting for pickup.
+func (o *OrderClient) SetEstimatedDelivery(ctx context.Context, orderId string, deliveryAt time.Time) error {
+	_, err := o.client.SetDeliveryStatus(ctx, &proto.DeliveryProgress{
+		OrderId:             orderId,
+		Status:              proto.DeliveryStatus_PickUp,
+		EstimatedDeliveryAt: timestamppb.New(deliveryAt),
 	})
 
 	return err
@@ -38,8 +74,9 @@ func (o *OrderClient) SetDeliveryDriver(ctx context.Context, orderId string, dri
 	return err
 }
 
-func NewOrderClient(addr string) (*OrderClient, error) {
-	con, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewOrderClient creates a client for the order service at addr.
+func NewOrderClient(addr string, cfg grpcclient.Config) (*OrderClient, error) {
+	con, err := grpcclient.Dial("order-service", addr, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +28,10 @@ type OrderServiceClient interface {
 	SetDeliveryDriver(ctx context.Context, in *OrderDriver, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// Set the deliver status
 	SetDeliveryStatus(ctx context.Context, in *DeliveryProgress, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Gets the refunds that have not been sent to the payment provider
+	GetPendingRefunds(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PendingRefunds, error)
+	// Sets the result of a refund
+	SetRefundStatus(ctx context.Context, in *RefundStatus, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type orderServiceClient struct {
@@ -83,6 +87,24 @@ func (c *orderServiceClient) SetDeliveryStatus(ctx context.Context, in *Delivery
 	return out, nil
 }
 
+func (c *orderServiceClient) GetPendingRefunds(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*PendingRefunds, error) {
+	out := new(PendingRefunds)
+	err := c.cc.Invoke(ctx, "/OrderService/GetPendingRefunds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) SetRefundStatus(ctx context.Context, in *RefundStatus, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/OrderService/SetRefundStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility
@@ -97,6 +119,10 @@ type OrderServiceServer interface {
 	SetDeliveryDriver(context.Context, *OrderDriver) (*emptypb.Empty, error)
 	// Set the deliver status
 	SetDeliveryStatus(context.Context, *DeliveryProgress) (*emptypb.Empty, error)
+	// Gets the refunds that have not been sent to the payment provider
+	GetPendingRefunds(context.Context, *emptypb.Empty) (*PendingRefunds, error)
+	// Sets the result of a refund
+	SetRefundStatus(context.Context, *RefundStatus) (*emptypb.Empty, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -119,6 +145,12 @@ func (UnimplementedOrderServiceServer) SetDeliveryDriver(context.Context, *Order
 func (UnimplementedOrderServiceServer) SetDeliveryStatus(context.Context, *DeliveryProgress) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDeliveryStatus not implemented")
 }
+func (UnimplementedOrderServiceServer) GetPendingRefunds(context.Context, *emptypb.Empty) (*PendingRefunds, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPendingRefunds not implemented")
+}
+func (UnimplementedOrderServiceServer) SetRefundStatus(context.Context, *RefundStatus) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRefundStatus not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 
 // UnsafeOrderServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -222,6 +254,42 @@ func _OrderService_SetDeliveryStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_GetPendingRefunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetPendingRefunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OrderService/GetPendingRefunds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetPendingRefunds(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_SetRefundStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundStatus)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).SetRefundStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/OrderService/SetRefundStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).SetRefundStatus(ctx, req.(*RefundStatus))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _OrderService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "OrderService",
 	HandlerType: (*OrderServiceServer)(nil),
@@ -246,6 +314,14 @@ var _OrderService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SetDeliveryStatus",
 			Handler:    _OrderService_SetDeliveryStatus_Handler,
 		},
+		{
+			MethodName: "GetPendingRefunds",
+			Handler:    _OrderService_GetPendingRefunds_Handler,
+		},
+		{
+			MethodName: "SetRefundStatus",
+			Handler:    _OrderService_SetRefundStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "order-service.proto",
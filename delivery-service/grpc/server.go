@@ -7,14 +7,17 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 type deliveryApp interface {
 	CreateDelivery(ctx context.Context, data *models.Delivery) (string, error)
 	GetDeliveryByOrderId(ctx context.Context, orderID string) (*models.Delivery, error)
+	AddTip(ctx context.Context, orderID string, tipID string, amount money.Money) error
 }
 
 type deliveryServiceServer struct {
@@ -23,7 +26,7 @@ type deliveryServiceServer struct {
 }
 
 func (d *deliveryServiceServer) AddDelivery(ctx context.Context, details *proto.DeliveryDetails) (*proto.DeliverId, error) {
-	deliveryId, err := d.delivery.CreateDelivery(ctx, &models.Delivery{
+	delivery := &models.Delivery{
 		OrderId: details.OrderId,
 		UserId:  details.UserId,
 		Pickup: models.Restaurant{
@@ -45,7 +48,21 @@ func (d *deliveryServiceServer) AddDelivery(ctx context.Context, details *proto.
 				Coordinates: [2]float64{details.Destination.Position.Longitude, details.Destination.Position.Latitude},
 			},
 		},
-	})
+	}
+	if details.EstimatedReadyAt != nil {
+		readyAt := details.EstimatedReadyAt.AsTime()
+		delivery.EstimatedReadyAt = &readyAt
+	}
+	if details.DeliveryFee != nil {
+		fee := fromProtoMoney(details.DeliveryFee)
+		delivery.DeliveryFee = &fee
+	}
+	if details.Tip != nil {
+		tip := fromProtoMoney(details.Tip)
+		delivery.Tip = &tip
+	}
+
+	deliveryId, err := d.delivery.CreateDelivery(ctx, delivery)
 
 	zap.L().Info("Received new delivery", zap.String("orderId", details.OrderId))
 
@@ -65,6 +82,30 @@ func (d *deliveryServiceServer) GetDeliveryByOrderId(ctx context.Context, detail
 	return &proto.Delivery{DeliveryId: delivery.Id.Hex(), DiverId: *delivery.DriverId, UserId: delivery.UserId}, nil
 }
 
+// AddTip credits a tip that was paid after delivery to the driver of the order.
+func (d *deliveryServiceServer) AddTip(ctx context.Context, tip *proto.DeliveryTip) (*emptypb.Empty, error) {
+	if tip.Amount == nil || tip.Amount.Amount <= 0 || tip.TipId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid tip")
+	}
+
+	err := d.delivery.AddTip(ctx, tip.OrderId, tip.TipId, fromProtoMoney(tip.Amount))
+	if err != nil {
+		if errors.Is(err, repo.ErrNoDelivery) {
+			return nil, status.Errorf(codes.NotFound, "Delivery not found")
+		} else if errors.Is(err, repo.ErrNotAssigned) {
+			return nil, status.Errorf(codes.FailedPrecondition, "Delivery does not have a driver")
+		}
+		zap.L().Error("Failed to credit tip", zap.String("orderId", tip.OrderId), zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "Internal error in delivery service")
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func fromProtoMoney(m *proto.Money) money.Money {
+	return money.New(m.Amount, money.Currency(m.Currency))
+}
+
 func NewServer(app deliveryApp) proto.DeliveryServiceServer {
 	return &deliveryServiceServer{delivery: app}
 }
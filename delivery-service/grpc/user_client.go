@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc/proto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNotDriver indicates that the user is not a registered driver.
+var ErrNotDriver = errors.New("user is not a driver")
+
+var driverStatuses = map[models.DriverStatus]proto.DriverStatus{
+	models.DriverUnavailable: proto.DriverStatus_DriverNotAvailable,
+	models.DriverAvailable:   proto.DriverStatus_DriverAvailable,
+	models.DriverBusy:        proto.DriverStatus_DriverBusy,
+}
+
+type UserClient struct {
+	client proto.UserServiceClient
+}
+
+// SetDriverStatus updates the driver status stored in user-service.
+// If the user is not a driver, [ErrNotDriver] is returned.
+func (u *UserClient) SetDriverStatus(ctx context.Context, driverId string, driverStatus models.DriverStatus) error {
+	_, err := u.client.SetDriverStatus(ctx, &proto.DriverStatusUpdate{
+		UserId: driverId,
+		Status: driverStatuses[driverStatus],
+	})
+
+	if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument {
+		return ErrNotDriver
+	}
+
+	return err
+}
+
+// NewUserClient creates a client for the user service at addr.
+func NewUserClient(addr string, cfg grpcclient.Config) (*UserClient, error) {
+	con, err := grpcclient.Dial("user-service", addr, cfg, "/UserService/SetDriverStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	client := proto.NewUserServiceClient(con)
+
+	return &UserClient{client}, nil
+}
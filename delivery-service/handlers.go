@@ -4,6 +4,8 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/handlers"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
 )
 
@@ -17,14 +19,31 @@ func (s *Server) RegisterRoutes() error {
 
 		group.Get("/new", handler.GetNearbyDeliveries)
 		group.Get("/my", handler.GetMyDeliveries)
+		group.Get("/offers", handler.GetOffers)
+		group.Get("/batches", handler.GetBatches)
+		group.Post("/batches/:batchId/claim", handler.ClaimBatch)
+		group.Get("/order/:orderId", handler.GetOrderDelivery)
+		group.Get("/driver/status", handler.GetDriverStatus)
+		group.Post("/driver/online", handler.GoOnline)
+		group.Post("/driver/offline", handler.GoOffline)
+		group.Post("/driver/location", handler.UpdateLocation)
+		group.Get("/earnings", handler.GetEarnings)
+		group.Get("/earnings/statement", handler.GetEarningsStatement)
+		group.Get("/earnings/export", handler.ExportEarnings, middleware.RequireRole("user_admin"))
 		group.Get("/:deliveryId", handler.GetDelivery)
 		group.Post("/:deliveryId/claim", handler.ClaimDelivery)
+		group.Post("/:deliveryId/decline", handler.DeclineOffer)
+		group.Post("/:deliveryId/release", handler.ReleaseDelivery)
+		group.Post("/:deliveryId/reassign", handler.ReassignDelivery, middleware.RequireRole("user_admin"))
 		group.Post("/:deliveryId/pickup", handler.PickupOrder)
 		group.Post("/:deliveryId/complete", handler.CompleteOrder)
+		group.Post("/:deliveryId/fail", handler.FailDelivery)
+		group.Post("/:deliveryId/returned", handler.ReturnDelivery)
 	}
 
 	{
 		proto.RegisterDeliveryServiceServer(s.grpc, grpc.NewServer(s.app))
+		health.RegisterServer(s.grpc)
 	}
 	return nil
 }
@@ -4,10 +4,15 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/app"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
+// deliveryPages contains the sort fields supported by the delivery list.
+// Deliveries are sorted by creation time using the id.
+var deliveryPages = pagination.Options{Sort: map[string]string{"created_at": "_id"}}
+
 type Delivery struct {
 	app *app.App
 }
@@ -17,18 +22,23 @@ func NewDelivery(app *app.App) *Delivery {
 }
 
 func (d *Delivery) GetMyDeliveries(c fiber.Ctx) error {
+	page, err := pagination.FromQuery(c, deliveryPages)
+	if err != nil {
+		return sendError(c, err)
+	}
+
 	driverId := middleware.GetUser(c).UserId
-	deliveries, err := d.app.GetUserDeliveries(c.RequestCtx(), driverId)
+	deliveries, next, err := d.app.GetUserDeliveries(c.Context(), driverId, page)
 	if err != nil {
 		return sendError(c, err)
 	}
 
-	return c.Status(200).JSON(dto.Response{Ok: true, Data: deliveries})
+	return c.Status(200).JSON(dto.Paged(deliveries, next))
 }
 
 func (d *Delivery) GetNearbyDeliveries(c fiber.Ctx) error {
 	driverId := middleware.GetUser(c).UserId
-	deliveries, err := d.app.GetNearbyDeliveries(c.RequestCtx(), driverId)
+	deliveries, err := d.app.GetNearbyDeliveries(c.Context(), driverId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -42,7 +52,7 @@ func (d *Delivery) GetDelivery(c fiber.Ctx) error {
 		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
 	}
 
-	order, err := d.app.GetDelivery(c.RequestCtx(), deliveryId)
+	order, err := d.app.GetDelivery(c.Context(), deliveryId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -57,7 +67,7 @@ func (d *Delivery) ClaimDelivery(c fiber.Ctx) error {
 		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
 	}
 
-	order, err := d.app.ClaimDelivery(c.RequestCtx(), driverId, deliveryId)
+	order, err := d.app.ClaimDelivery(c.Context(), driverId, deliveryId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -72,7 +82,7 @@ func (d *Delivery) PickupOrder(c fiber.Ctx) error {
 		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
 	}
 
-	order, err := d.app.PickupOrder(c.RequestCtx(), driverId, deliveryId)
+	order, err := d.app.PickupOrder(c.Context(), driverId, deliveryId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -87,10 +97,197 @@ func (d *Delivery) CompleteOrder(c fiber.Ctx) error {
 		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
 	}
 
-	order, err := d.app.CompleteOrder(c.RequestCtx(), driverId, deliveryId)
+	var req deliveryProof
+	err = c.Bind().Body(&req)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	proof := &app.Proof{Pin: req.Pin, Photo: req.Photo, Authorization: c.Get(fiber.HeaderAuthorization)}
+	if req.Latitude != nil && req.Longitude != nil {
+		proof.Position = &[2]float64{*req.Longitude, *req.Latitude}
+	}
+
+	order, err := d.app.CompleteOrder(c.Context(), driverId, deliveryId, proof)
 	if err != nil {
 		return sendError(c, err)
 	}
 
 	return c.Status(200).JSON(dto.Response{Ok: true, Data: order})
 }
+
+func (d *Delivery) GetOffers(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	deliveries, err := d.app.GetOffers(c.Context(), driverId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: deliveries})
+}
+
+func (d *Delivery) DeclineOffer(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	deliveryId, err := bson.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
+	}
+
+	err = d.app.DeclineOffer(c.Context(), driverId, deliveryId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: "Offer declined"})
+}
+
+func (d *Delivery) UpdateLocation(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+
+	var req locationUpdate
+	err := c.Bind().Body(&req)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	err = d.app.UpdateDriverLocation(c.Context(), driverId, req.Latitude, req.Longitude)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: "Location updated"})
+}
+
+func (d *Delivery) GetDriverStatus(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	status, err := d.app.GetDriverStatus(c.Context(), driverId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: fiber.Map{"status": status}})
+}
+
+func (d *Delivery) GoOnline(c fiber.Ctx) error {
+	return d.setOnline(c, true)
+}
+
+func (d *Delivery) GoOffline(c fiber.Ctx) error {
+	return d.setOnline(c, false)
+}
+
+func (d *Delivery) setOnline(c fiber.Ctx, online bool) error {
+	driverId := middleware.GetUser(c).UserId
+	status, err := d.app.SetDriverOnline(c.Context(), driverId, online)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: fiber.Map{"status": status}})
+}
+
+func (d *Delivery) ReleaseDelivery(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	deliveryId, err := bson.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
+	}
+
+	delivery, err := d.app.ReleaseDelivery(c.Context(), driverId, deliveryId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: delivery})
+}
+
+func (d *Delivery) ReassignDelivery(c fiber.Ctx) error {
+	deliveryId, err := bson.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
+	}
+
+	var req reassignRequest
+	err = c.Bind().Body(&req)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	delivery, err := d.app.ReassignDelivery(c.Context(), deliveryId, req.DriverId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: delivery})
+}
+
+func (d *Delivery) GetOrderDelivery(c fiber.Ctx) error {
+	userId := middleware.GetUser(c).UserId
+	delivery, pin, err := d.app.GetCustomerDelivery(c.Context(), userId, c.Params("orderId"))
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: customerDelivery{Delivery: delivery, Pin: pin}})
+}
+
+func (d *Delivery) FailDelivery(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	deliveryId, err := bson.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
+	}
+
+	var req deliveryFailure
+	err = c.Bind().Body(&req)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	delivery, err := d.app.FailDelivery(c.Context(), driverId, deliveryId, req.Reason, req.Note)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: delivery})
+}
+
+func (d *Delivery) ReturnDelivery(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	deliveryId, err := bson.ObjectIDFromHex(c.Params("deliveryId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing delivery id"})
+	}
+
+	delivery, err := d.app.ReturnDelivery(c.Context(), driverId, deliveryId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: delivery})
+}
+
+func (d *Delivery) GetBatches(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	batches, err := d.app.GetBatches(c.Context(), driverId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: batches})
+}
+
+func (d *Delivery) ClaimBatch(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	batchId, err := bson.ObjectIDFromHex(c.Params("batchId"))
+	if err != nil {
+		return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Missing batch id"})
+	}
+
+	batch, err := d.app.ClaimBatch(c.Context(), driverId, batchId)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: batch})
+}
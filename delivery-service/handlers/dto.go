@@ -0,0 +1,30 @@
+package handlers
+
+import "github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+
+type locationUpdate struct {
+	Latitude  float64 `json:"lat" validate:"min=-90,max=90"`
+	Longitude float64 `json:"lng" validate:"min=-180,max=180"`
+}
+
+type reassignRequest struct {
+	DriverId string `json:"driver_id" validate:"required"`
+}
+
+type deliveryProof struct {
+	Pin   string `json:"pin" validate:"omitempty,numeric,len=4"`
+	Photo string `json:"photo" validate:"omitempty,filepath"`
+	// Latitude and Longitude are the location of the driver. These are required when completing a delivery with a photo.
+	Latitude  *float64 `json:"lat" validate:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"lng" validate:"omitempty,min=-180,max=180"`
+}
+
+type customerDelivery struct {
+	*models.Delivery
+	Pin string `json:"pin,omitempty"`
+}
+
+type deliveryFailure struct {
+	Reason models.FailureReason `json:"reason" validate:"required,oneof=customer_unreachable wrong_address damaged unsafe_location other"`
+	Note   string               `json:"note" validate:"max=500"`
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/app"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
+	"github.com/gofiber/fiber/v3"
+)
+
+// dateFormat is the format of the dates in earnings queries.
+const dateFormat = "2006-01-02"
+
+func (d *Delivery) GetEarnings(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+	period := app.EarningsPeriod(c.Query("period", string(app.PeriodWeek)))
+
+	days := 28
+	if period == app.PeriodDay {
+		days = 7
+	}
+
+	from, to, err := dateRange(c, days)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	summaries, err := d.app.GetEarnings(c.Context(), driverId, period, from, to)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: summaries})
+}
+
+func (d *Delivery) GetEarningsStatement(c fiber.Ctx) error {
+	driverId := middleware.GetUser(c).UserId
+
+	week := time.Now()
+	if value := c.Query("week"); value != "" {
+		var err error
+		week, err = time.Parse(dateFormat, value)
+		if err != nil {
+			return c.Status(400).JSON(dto.ErrorResponse{Ok: false, Error: "Invalid week date"})
+		}
+	}
+
+	statement, err := d.app.GetStatement(c.Context(), driverId, week)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(200).JSON(dto.Response{Ok: true, Data: statement})
+}
+
+func (d *Delivery) ExportEarnings(c fiber.Ctx) error {
+	from, to, err := dateRange(c, 7)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	var buf bytes.Buffer
+	err = d.app.ExportEarnings(c.Context(), &buf, c.Query("driver_id"), from, to)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="earnings-%s-%s.csv"`, from.Format(dateFormat), to.AddDate(0, 0, -1).Format(dateFormat)))
+	return c.Status(200).Send(buf.Bytes())
+}
+
+// dateRange parses the from and to query dates. Both dates are inclusive.
+// The range defaults to the last days days ending today.
+func dateRange(c fiber.Ctx, days int) (from time.Time, to time.Time, err error) {
+	to = time.Now().UTC().Truncate(24 * time.Hour)
+	if value := c.Query("to"); value != "" {
+		to, err = time.Parse(dateFormat, value)
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "Invalid to date")
+		}
+	}
+
+	from = to.AddDate(0, 0, 1-days)
+	if value := c.Query("from"); value != "" {
+		from, err = time.Parse(dateFormat, value)
+		if err != nil {
+			return from, to, fiber.NewError(fiber.StatusBadRequest, "Invalid from date")
+		}
+	}
+
+	// include the whole end date
+	return from, to.AddDate(0, 0, 1), nil
+}
@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"fmt"
 	"io"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/app"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/grpc"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/repo"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
@@ -14,18 +18,46 @@ func sendError(ctx fiber.Ctx, err error) error {
 	switch err {
 	case repo.ErrAlreadyClaimed:
 		return ctx.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery has already been claimed"})
+	case repo.ErrNoOffer:
+		return ctx.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery is not offered to the driver"})
+	case repo.ErrDriverOffline:
+		return ctx.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Ok: false, Error: "Driver must be online to receive deliveries"})
+	case grpc.ErrNotDriver:
+		return ctx.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Ok: false, Error: "User is not a registered driver"})
+	case repo.ErrNotAssigned:
+		return ctx.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery is not assigned to the driver"})
+	case app.ErrProofRequired:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery PIN or a photo with the driver location is required"})
+	case app.ErrIncorrectPin:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Incorrect delivery PIN"})
+	case app.ErrNoPin:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery does not have a PIN, a photo with the driver location is required"})
+	case app.ErrPinLocked:
+		return ctx.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{Ok: false, Error: "Too many incorrect PIN attempts, a photo with the driver location is required"})
+	case app.ErrInvalidPhoto:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Photo was not uploaded by the driver"})
+	case app.ErrTooFar:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Driver is too far from the delivery address"})
+	case repo.ErrBatchTaken:
+		return ctx.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{Ok: false, Error: "All deliveries in the batch have already been claimed"})
+	case repo.ErrNoBatch:
+		return ctx.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Ok: false, Error: "Batch was not found or has already been claimed"})
+	case app.ErrInvalidPeriod:
+		return ctx.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{Ok: false, Error: "Earnings period must be day or week and from must be before to"})
 	case repo.ErrNoDelivery:
 		return ctx.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{Ok: false, Error: "Delivery was not found"})
 	case fiber.ErrUnprocessableEntity, io.EOF, io.ErrUnexpectedEOF:
 		return ctx.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse{Ok: false, Error: "Request body is missing or truncated"})
 	}
 
-	if verr, ok := err.(*validate.ValidationErrors); ok {
+	if serr, ok := err.(*repo.InvalidStateError); ok {
+		return ctx.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{Ok: false, Error: fmt.Sprintf("Delivery is %s and cannot be marked as %s", serr.State, serr.Next)})
+	} else if verr, ok := err.(*validate.ValidationErrors); ok {
 		return ctx.Status(400).JSON(fiber.Map{"ok": false, "error": verr.Error(), "reason": verr.ValidationErrors()})
 	} else if fiberErr, ok := err.(*fiber.Error); ok {
 		return ctx.Status(fiberErr.Code).JSON(fiber.Map{"ok": false, "error": fiberErr.Message})
 	} else {
-		zap.L().Error("Request failed due to error", zap.Error(err))
+		requestlog.L(ctx.Context()).Error("Request failed due to error", zap.Error(err))
 		return ctx.Status(500).JSON(dto.ErrorResponse{Ok: false, Error: "Internal server error"})
 	}
 }
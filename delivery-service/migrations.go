@@ -0,0 +1,24 @@
+package orderservice
+
+import "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+
+// Migrations contains the database migrations of the service.
+// New migrations must be added at the end with a higher version.
+var Migrations = []migrate.Migration{
+	{
+		Version:     1,
+		Description: "Add delivery, batch, driver and earning indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("deliveries",
+				migrate.Index("order_id"),
+				migrate.Index("driver_id", "state"),
+				migrate.Index("state"),
+				migrate.Index("batch_id"),
+				migrate.Index("offer.drivers"),
+			),
+			migrate.CreateIndexes("batches", migrate.Index("driver_id"), migrate.Index("state")),
+			migrate.CreateIndexes("drivers", migrate.Index("status")),
+			migrate.CreateIndexes("earnings", migrate.Index("driver_id", "created_at")),
+		),
+	},
+}
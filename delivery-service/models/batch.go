@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Batch is a group of deliveries from nearby restaurants heading in a similar direction
+// that are claimed together by a single driver.
+type Batch struct {
+	Id bson.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Route contains the deliveries in the order they should be dropped off.
+	Route    []bson.ObjectID `bson:"route" json:"route"`
+	DriverId *string         `bson:"driver_id" json:"driver_id,omitempty"`
+
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	// ExpiresAt is the time the batch is split up if it is not claimed.
+	ExpiresAt time.Time  `bson:"expires_at" json:"expires_at"`
+	ClaimedAt *time.Time `bson:"claimed_at,omitempty" json:"claimed_at,omitempty"`
+
+	// Deliveries contains the deliveries in the batch in the route order.
+	Deliveries []*Delivery `bson:"-" json:"deliveries,omitempty"`
+}
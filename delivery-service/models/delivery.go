@@ -1,14 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"time"
 
-type DeliveryState string
-
-const (
-	DeliveryStateUnclaimed  = "unclaimed"
-	DeliveryStateWaiting    = "waiting"
-	DeliveryStateDelivering = "delivering"
-	DeliveryStateDone       = "done"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
 type Address struct {
@@ -41,7 +37,35 @@ type Delivery struct {
 	Destination Address       `bson:"destination" json:"destination"`
 
 	State DeliveryState `bson:"state" json:"state"`
+	// Timestamps contains the time the delivery entered each state.
+	Timestamps DeliveryTimestamps `bson:"timestamps" json:"timestamps"`
+
+	// EstimatedReadyAt is the time the order is expected to be ready for pickup.
+	EstimatedReadyAt *time.Time `bson:"estimated_ready_at,omitempty" json:"estimated_ready_at,omitempty"`
+	// EstimatedDeliveryAt is the time the order is expected to be delivered. It is updated on each state change.
+	EstimatedDeliveryAt *time.Time `bson:"estimated_delivery_at,omitempty" json:"estimated_delivery_at,omitempty"`
 
 	DriverId *string `bson:"driver_id" json:"driver_id,omitempty"`
 	Position *Point  `bson:"-" json:"position,omitempty"`
+
+	// BatchId is set if the delivery is part of a batch.
+	// Batched deliveries are not shown in the open pool and can only be claimed with the batch.
+	BatchId *bson.ObjectID `bson:"batch_id,omitempty" json:"batch_id,omitempty"`
+
+	// Offer is set while the dispatcher is offering the delivery to drivers.
+	Offer *Offer `bson:"offer,omitempty" json:"offer,omitempty"`
+
+	// Pin is shown to the customer and entered by the driver to complete the delivery.
+	Pin string `bson:"pin" json:"-"`
+	// PinAttempts is the number of times the driver entered the PIN.
+	PinAttempts int `bson:"pin_attempts,omitempty" json:"-"`
+	// Proof is the proof of delivery submitted when the delivery was completed.
+	Proof *DeliveryProof `bson:"proof,omitempty" json:"proof,omitempty"`
+	// Failure is set if the delivery failed.
+	Failure *DeliveryFailure `bson:"failure,omitempty" json:"failure,omitempty"`
+
+	// DeliveryFee is the delivery fee paid by the customer. The driver is credited a share of it when the delivery is completed.
+	DeliveryFee *money.Money `bson:"delivery_fee,omitempty" json:"-"`
+	// Tip is the total of the tips added at checkout.
+	Tip *money.Money `bson:"tip,omitempty" json:"-"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Offer contains the drivers that a delivery is currently offered to by the dispatcher.
+// Deliveries with an offer are not shown in the open pool until all offer rounds fail.
+type Offer struct {
+	// Round is the current offer round starting from 1.
+	Round int `bson:"round" json:"round"`
+	// Drivers contains the drivers the delivery is offered to in the current round.
+	Drivers []string `bson:"drivers" json:"-"`
+	// Offered contains all drivers the delivery was offered to in any round.
+	Offered []string `bson:"offered" json:"-"`
+	// ExpiresAt is the time the current round ends.
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+}
+
+// DriverStatus is the availability of a driver.
+// The values are the same as the driver status in user-service.
+type DriverStatus string
+
+const (
+	DriverUnavailable DriverStatus = "not_available"
+	DriverAvailable   DriverStatus = "available"
+	DriverBusy        DriverStatus = "busy"
+)
+
+// Online returns true if the driver can receive new deliveries.
+func (s DriverStatus) Online() bool {
+	return s == DriverAvailable || s == DriverBusy
+}
+
+// DriverLocation is the last location and the availability reported by a driver.
+type DriverLocation struct {
+	DriverId  string       `bson:"_id" json:"driver_id"`
+	Status    DriverStatus `bson:"status" json:"status"`
+	Position  Point        `bson:"position" json:"position"`
+	UpdatedAt time.Time    `bson:"updated_at" json:"updated_at"`
+}
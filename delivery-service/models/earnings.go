@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// EarningType is the source of an earning.
+type EarningType string
+
+const (
+	// EarningDeliveryFee is the driver share of the delivery fee.
+	EarningDeliveryFee EarningType = "delivery_fee"
+	// EarningTip is a tip from the customer.
+	EarningTip EarningType = "tip"
+)
+
+// Earning is an entry in the earnings ledger of a driver.
+type Earning struct {
+	// Id identifies the source of the earning so that the same earning is never credited twice.
+	Id         string        `bson:"_id" json:"id"`
+	DriverId   string        `bson:"driver_id" json:"driver_id"`
+	DeliveryId bson.ObjectID `bson:"delivery_id" json:"delivery_id"`
+	OrderId    string        `bson:"order_id" json:"order_id"`
+	Type       EarningType   `bson:"type" json:"type"`
+	Amount     money.Money   `bson:"amount" json:"amount"`
+	CreatedAt  time.Time     `bson:"created_at" json:"created_at"`
+	// MissingFee is set on the zero fee credited for deliveries that do not have a delivery fee.
+	MissingFee bool `bson:"missing_fee,omitempty" json:"missing_fee,omitempty"`
+}
+
+// EarningsSummary contains the earnings of a driver in a period.
+type EarningsSummary struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	// Deliveries is the number of deliveries completed in the period.
+	Deliveries   int         `json:"deliveries"`
+	DeliveryFees money.Money `json:"delivery_fees"`
+	Tips         money.Money `json:"tips"`
+	Total        money.Money `json:"total"`
+}
+
+// EarningsStatement is the payout statement of a driver for a week.
+type EarningsStatement struct {
+	DriverId string `json:"driver_id"`
+	EarningsSummary
+	Entries []Earning `json:"entries"`
+}
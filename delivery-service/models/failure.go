@@ -0,0 +1,34 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// FailureReason is the reason a delivery could not be completed.
+type FailureReason string
+
+const (
+	FailureCustomerUnreachable FailureReason = "customer_unreachable"
+	FailureWrongAddress        FailureReason = "wrong_address"
+	FailureDamaged             FailureReason = "damaged"
+	FailureUnsafeLocation      FailureReason = "unsafe_location"
+	FailureOther               FailureReason = "other"
+)
+
+// returnedFailures contains the failure reasons where the order is returned to the restaurant if it was picked up.
+// Damaged orders are not returned.
+var returnedFailures = []FailureReason{FailureCustomerUnreachable, FailureWrongAddress, FailureUnsafeLocation, FailureOther}
+
+// ReturnToRestaurant returns true if a picked up order that failed for the reason must be returned to the restaurant.
+func (r FailureReason) ReturnToRestaurant() bool {
+	return slices.Contains(returnedFailures, r)
+}
+
+// DeliveryFailure contains the details of a failed delivery.
+type DeliveryFailure struct {
+	Reason FailureReason `bson:"reason" json:"reason"`
+	Note   string        `bson:"note,omitempty" json:"note,omitempty"`
+	// FailedAt is the time the driver reported the failure.
+	FailedAt time.Time `bson:"failed_at" json:"failed_at"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ProofMethod is the way the driver proved that the delivery was handed over.
+type ProofMethod string
+
+const (
+	// ProofPin is used when the driver entered the PIN shown to the customer.
+	ProofPin ProofMethod = "pin"
+	// ProofPhoto is used when the driver uploaded a photo at the destination.
+	ProofPhoto ProofMethod = "photo"
+)
+
+// DeliveryProof is the proof of delivery submitted by the driver.
+// It is kept on the delivery for handling disputes.
+type DeliveryProof struct {
+	Method ProofMethod `bson:"method" json:"method"`
+	// Photo is the url of the photo uploaded to upload-service.
+	Photo string `bson:"photo,omitempty" json:"photo,omitempty"`
+	// Position is the location of the driver when the delivery was completed.
+	Position *Point `bson:"position,omitempty" json:"position,omitempty"`
+	// DistanceKm is the distance between Position and the destination.
+	DistanceKm  *float64  `bson:"distance_km,omitempty" json:"distance_km,omitempty"`
+	SubmittedAt time.Time `bson:"submitted_at" json:"submitted_at"`
+}
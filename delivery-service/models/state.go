@@ -0,0 +1,97 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// DeliveryState is the state of a delivery.
+//
+// Deliveries move through the states in the following order:
+//
+//	unclaimed -> waiting -> delivering -> done
+//
+// A delivery that is waiting for pickup can be released back to unclaimed.
+// A delivery that is waiting for pickup or being delivered can fail.
+// A delivery that fails after pickup is returned to the restaurant:
+//
+//	delivering -> returning -> returned
+type DeliveryState string
+
+const (
+	// DeliveryStateUnclaimed is a delivery that does not have a driver.
+	DeliveryStateUnclaimed DeliveryState = "unclaimed"
+	// DeliveryStateWaiting is a delivery that was claimed by a driver and is waiting for pickup.
+	DeliveryStateWaiting DeliveryState = "waiting"
+	// DeliveryStateDelivering is a delivery that was picked up by the driver.
+	DeliveryStateDelivering DeliveryState = "delivering"
+	// DeliveryStateDone is a delivery that was delivered to the customer.
+	DeliveryStateDone DeliveryState = "done"
+	// DeliveryStateFailed is a delivery that could not be completed.
+	DeliveryStateFailed DeliveryState = "failed"
+	// DeliveryStateReturning is a delivery that failed after pickup and is being returned to the restaurant.
+	DeliveryStateReturning DeliveryState = "returning"
+	// DeliveryStateReturned is a delivery that was returned to the restaurant.
+	DeliveryStateReturned DeliveryState = "returned"
+)
+
+// deliveryTransitions contains the states each state can move to.
+var deliveryTransitions = map[DeliveryState][]DeliveryState{
+	DeliveryStateUnclaimed:  {DeliveryStateWaiting},
+	DeliveryStateWaiting:    {DeliveryStateDelivering, DeliveryStateUnclaimed, DeliveryStateFailed},
+	DeliveryStateDelivering: {DeliveryStateDone, DeliveryStateFailed, DeliveryStateReturning},
+	DeliveryStateReturning:  {DeliveryStateReturned},
+}
+
+// CanTransition returns true if a delivery in the state s can move to the state next.
+func (s DeliveryState) CanTransition(next DeliveryState) bool {
+	return slices.Contains(deliveryTransitions[s], next)
+}
+
+// PreviousStates returns the states that can move to the state s.
+func (s DeliveryState) PreviousStates() []DeliveryState {
+	var states []DeliveryState
+	for from, next := range deliveryTransitions {
+		if slices.Contains(next, s) {
+			states = append(states, from)
+		}
+	}
+	slices.Sort(states)
+	return states
+}
+
+// DeliveryTimestamps contains the time a delivery entered each state.
+// A timestamp is nil if the delivery has not entered the state.
+type DeliveryTimestamps struct {
+	CreatedAt *time.Time `bson:"created_at,omitempty" json:"created_at,omitempty"`
+	ClaimedAt *time.Time `bson:"claimed_at,omitempty" json:"claimed_at,omitempty"`
+	// ReleasedAt is the last time the delivery was released by a driver.
+	ReleasedAt  *time.Time `bson:"released_at,omitempty" json:"released_at,omitempty"`
+	PickedUpAt  *time.Time `bson:"picked_up_at,omitempty" json:"picked_up_at,omitempty"`
+	DeliveredAt *time.Time `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	FailedAt    *time.Time `bson:"failed_at,omitempty" json:"failed_at,omitempty"`
+	ReturningAt *time.Time `bson:"returning_at,omitempty" json:"returning_at,omitempty"`
+	ReturnedAt  *time.Time `bson:"returned_at,omitempty" json:"returned_at,omitempty"`
+}
+
+// TimestampField returns the bson field in [DeliveryTimestamps] that stores the time the delivery entered the state.
+func (s DeliveryState) TimestampField() string {
+	switch s {
+	case DeliveryStateUnclaimed:
+		// deliveries only move to unclaimed when they are released
+		return "timestamps.released_at"
+	case DeliveryStateWaiting:
+		return "timestamps.claimed_at"
+	case DeliveryStateDelivering:
+		return "timestamps.picked_up_at"
+	case DeliveryStateDone:
+		return "timestamps.delivered_at"
+	case DeliveryStateFailed:
+		return "timestamps.failed_at"
+	case DeliveryStateReturning:
+		return "timestamps.returning_at"
+	case DeliveryStateReturned:
+		return "timestamps.returned_at"
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"slices"
+	"testing"
+)
+
+var allStates = []DeliveryState{
+	DeliveryStateUnclaimed, DeliveryStateWaiting, DeliveryStateDelivering, DeliveryStateDone,
+	DeliveryStateFailed, DeliveryStateReturning, DeliveryStateReturned,
+}
+
+func TestCanTransition(t *testing.T) {
+	allowed := map[DeliveryState][]DeliveryState{
+		DeliveryStateUnclaimed:  {DeliveryStateWaiting},
+		DeliveryStateWaiting:    {DeliveryStateDelivering, DeliveryStateUnclaimed, DeliveryStateFailed},
+		DeliveryStateDelivering: {DeliveryStateDone, DeliveryStateFailed, DeliveryStateReturning},
+		DeliveryStateReturning:  {DeliveryStateReturned},
+	}
+
+	for _, from := range allStates {
+		for _, to := range allStates {
+			expected := slices.Contains(allowed[from], to)
+			if got := from.CanTransition(to); got != expected {
+				t.Errorf("%s -> %s: expected %v got %v", from, to, expected, got)
+			}
+		}
+	}
+}
+
+func TestPreviousStates(t *testing.T) {
+	tests := map[DeliveryState][]DeliveryState{
+		DeliveryStateUnclaimed:  {DeliveryStateWaiting},
+		DeliveryStateWaiting:    {DeliveryStateUnclaimed},
+		DeliveryStateDelivering: {DeliveryStateWaiting},
+		DeliveryStateFailed:     {DeliveryStateDelivering, DeliveryStateWaiting},
+		DeliveryStateReturned:   {DeliveryStateReturning},
+	}
+
+	for state, expected := range tests {
+		if got := state.PreviousStates(); !slices.Equal(got, expected) {
+			t.Errorf("%s: expected %v got %v", state, expected, got)
+		}
+	}
+}
+
+func TestTimestampField(t *testing.T) {
+	seen := map[string]bool{}
+	for _, state := range allStates {
+		field := state.TimestampField()
+		if field == "" {
+			t.Errorf("%s does not have a timestamp field", state)
+		}
+		if seen[field] {
+			t.Errorf("%s uses the timestamp field %s of another state", state, field)
+		}
+		seen[field] = true
+	}
+
+	if field := DeliveryState("unknown").TimestampField(); field != "" {
+		t.Errorf("expected no timestamp field for an unknown state got %s", field)
+	}
+}
@@ -0,0 +1,246 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrNoBatch indicates that the batch does not exist, has expired or was claimed by another driver.
+var ErrNoBatch = errors.New("batch not found")
+
+// ErrBatchTaken indicates that all deliveries in the batch were claimed by other drivers before the batch was claimed.
+var ErrBatchTaken = errors.New("batch deliveries were already claimed")
+
+// BatchRepo stores groups of deliveries that are claimed together.
+type BatchRepo interface {
+	// GetBatchable returns the deliveries in the open pool that are not in a batch.
+	GetBatchable(ctx context.Context) ([]*models.Delivery, error)
+	// CreateBatch creates a batch with the deliveries in batch.Route.
+	// Deliveries that were claimed, offered or batched since they were loaded are not added.
+	// It returns false if less than two deliveries could be added, in which case the batch is not created.
+	CreateBatch(ctx context.Context, batch *models.Batch) (bool, error)
+	// GetOpenBatches returns the batches that have not been claimed or expired.
+	GetOpenBatches(ctx context.Context) ([]*models.Batch, error)
+	// ClaimBatch assigns all deliveries in the batch to the driver.
+	// It returns [ErrNoBatch] if the batch does not exist, has expired or was already claimed
+	// and [ErrBatchTaken] if none of its deliveries could be assigned to the driver.
+	ClaimBatch(ctx context.Context, batchId bson.ObjectID, driverId string) (*models.Batch, error)
+	// ExpireBatches removes expired batches that were not claimed and returns their deliveries to the open pool.
+	ExpireBatches(ctx context.Context, now time.Time) error
+}
+
+type batchRepo struct {
+	batches    *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+// GetBatchable implements BatchRepo.
+func (b *batchRepo) GetBatchable(ctx context.Context) ([]*models.Delivery, error) {
+	result, err := b.deliveries.Find(ctx, batchableFilter(nil), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*models.Delivery{}
+	err = result.All(ctx, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// CreateBatch implements BatchRepo.
+func (b *batchRepo) CreateBatch(ctx context.Context, batch *models.Batch) (bool, error) {
+	batch.Id = bson.NewObjectID()
+	batch.DriverId = nil
+
+	// the deliveries are added before the batch is inserted so that the batch cannot be claimed
+	// before all deliveries are added.
+	res, err := b.deliveries.UpdateMany(ctx,
+		batchableFilter(batch.Route),
+		bson.D{{Key: "$set", Value: bson.D{{Key: "batch_id", Value: batch.Id}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if res.ModifiedCount < 2 {
+		// not enough deliveries were left to form a batch
+		_, err = b.deliveries.UpdateMany(ctx, bson.D{{Key: "batch_id", Value: batch.Id}}, bson.D{{Key: "$unset", Value: bson.D{{Key: "batch_id", Value: ""}}}})
+		return false, err
+	}
+
+	_, err = b.batches.InsertOne(ctx, batch)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetOpenBatches implements BatchRepo.
+func (b *batchRepo) GetOpenBatches(ctx context.Context) ([]*models.Batch, error) {
+	result, err := b.batches.Find(ctx, bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	batches := []*models.Batch{}
+	err = result.All(ctx, &batches)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, batch := range batches {
+		batch.Deliveries, err = b.getDeliveries(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return batches, nil
+}
+
+// ClaimBatch implements BatchRepo.
+func (b *batchRepo) ClaimBatch(ctx context.Context, batchId bson.ObjectID, driverId string) (*models.Batch, error) {
+	now := time.Now()
+
+	// claiming the batch document first ensures that only one driver can claim the deliveries
+	var batch models.Batch
+	err := b.batches.FindOneAndUpdate(ctx,
+		bson.D{
+			{Key: "_id", Value: batchId},
+			{Key: "driver_id", Value: nil},
+			{Key: "expires_at", Value: bson.D{{Key: "$gt", Value: now}}},
+		},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "driver_id", Value: driverId}, {Key: "claimed_at", Value: now}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&batch)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNoBatch
+		}
+		return nil, err
+	}
+
+	res, err := b.deliveries.UpdateMany(ctx,
+		bson.D{
+			{Key: "batch_id", Value: batchId},
+			{Key: "driver_id", Value: nil},
+			{Key: "state", Value: models.DeliveryStateUnclaimed},
+		},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "driver_id", Value: driverId},
+			{Key: "state", Value: models.DeliveryStateWaiting},
+			{Key: models.DeliveryStateWaiting.TimestampField(), Value: now},
+		}}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.ModifiedCount == 0 {
+		// the deliveries were claimed individually or released from the batch after it was loaded
+		_, err = b.batches.DeleteOne(ctx, bson.D{{Key: "_id", Value: batchId}})
+		if err != nil {
+			return nil, err
+		}
+		return nil, ErrBatchTaken
+	}
+
+	batch.Deliveries, err = b.getDeliveries(ctx, &batch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
+// ExpireBatches implements BatchRepo.
+func (b *batchRepo) ExpireBatches(ctx context.Context, now time.Time) error {
+	result, err := b.batches.Find(ctx, bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	var expired []models.Batch
+	err = result.All(ctx, &expired)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range expired {
+		// the batch may have been claimed after it was loaded
+		res, err := b.batches.DeleteOne(ctx, bson.D{{Key: "_id", Value: batch.Id}, {Key: "driver_id", Value: nil}})
+		if err != nil {
+			return err
+		}
+		if res.DeletedCount == 0 {
+			continue
+		}
+
+		_, err = b.deliveries.UpdateMany(ctx,
+			bson.D{{Key: "batch_id", Value: batch.Id}, {Key: "state", Value: models.DeliveryStateUnclaimed}},
+			bson.D{{Key: "$unset", Value: bson.D{{Key: "batch_id", Value: ""}}}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDeliveries returns the deliveries in the batch in the route order.
+// Deliveries that are no longer assigned to the batch are skipped.
+func (b *batchRepo) getDeliveries(ctx context.Context, batch *models.Batch) ([]*models.Delivery, error) {
+	result, err := b.deliveries.Find(ctx, bson.D{{Key: "batch_id", Value: batch.Id}})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*models.Delivery{}
+	err = result.All(ctx, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(deliveries, func(a, b *models.Delivery) int {
+		return slices.Index(batch.Route, a.Id) - slices.Index(batch.Route, b.Id)
+	})
+
+	return deliveries, nil
+}
+
+// batchableFilter returns a filter for deliveries in the open pool that are not batched.
+// If ids is not nil, only the deliveries with the given ids are matched.
+func batchableFilter(ids []bson.ObjectID) bson.D {
+	filter := bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "state", Value: models.DeliveryStateUnclaimed},
+		{Key: "offer", Value: nil},
+		{Key: "batch_id", Value: nil},
+	}
+	if ids != nil {
+		filter = append(filter, bson.E{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}})
+	}
+	return filter
+}
+
+func NewBatchRepo(db *mongo.Database) (BatchRepo, error) {
+	return &batchRepo{batches: db.Collection("batches"), deliveries: db.Collection("deliveries")}, nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -13,16 +15,73 @@ import (
 
 var ErrAlreadyClaimed = errors.New("already claimed")
 var ErrNoDelivery = errors.New("delivery not found")
+var ErrNoOffer = errors.New("delivery is not offered to the driver")
+var ErrNotAssigned = errors.New("delivery is not assigned to the driver")
+var ErrPinLocked = errors.New("no pin attempts left")
+
+// InvalidStateError is returned when a delivery cannot move to the requested state from its current state.
+type InvalidStateError struct {
+	State models.DeliveryState
+	Next  models.DeliveryState
+}
+
+func (e *InvalidStateError) Error() string {
+	return fmt.Sprintf("delivery in state %s cannot move to %s", e.State, e.Next)
+}
 
 type DeliveryRepo interface {
 	AddDelivery(ctx context.Context, data *models.Delivery) (string, error)
-	GetByDeliveryDriver(ctx context.Context, driverId string) ([]*models.Delivery, error)
+	GetByDeliveryDriver(ctx context.Context, driverId string, p pagination.Params) ([]*models.Delivery, string, error)
 	GetNearbyDeliveries(ctx context.Context, driverId string) ([]*models.Delivery, error)
 	GetById(ctx context.Context, deliveryId bson.ObjectID) (*models.Delivery, error)
 	ClaimDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
+	// DeliveryPickup marks a delivery that is waiting for pickup as picked up.
+	// It returns [ErrNotAssigned] if the delivery belongs to another driver and
+	// [InvalidStateError] if the delivery is not waiting for pickup.
 	DeliveryPickup(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
-	DeliveryComplete(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
+	// DeliveryComplete marks a delivery that was picked up as delivered and stores the proof of delivery.
+	// It returns [ErrNotAssigned] if the delivery belongs to another driver and
+	// [InvalidStateError] if the delivery was not picked up.
+	DeliveryComplete(ctx context.Context, deliveryId bson.ObjectID, driverId string, proof *models.DeliveryProof) (*models.Delivery, error)
+	// FailDelivery marks the delivery as failed or returning and stores the failure details.
+	// next must be [models.DeliveryStateFailed] or [models.DeliveryStateReturning].
+	FailDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string, next models.DeliveryState, failure *models.DeliveryFailure) (*models.Delivery, error)
+	// DeliveryReturned marks a delivery that is being returned as returned to the restaurant.
+	DeliveryReturned(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
+	// GetAssigned gets a delivery that is assigned to the driver.
+	// It returns [ErrNotAssigned] if the delivery belongs to another driver.
+	GetAssigned(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
+	// ReleaseDelivery removes the driver from a delivery that has not been picked up and returns it to unclaimed.
+	// It returns [ErrNotAssigned] if the delivery belongs to another driver and
+	// [InvalidStateError] if the delivery is not waiting for pickup.
+	ReleaseDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error)
+	// ReassignDelivery assigns a delivery that has not been picked up to the given driver.
+	// It returns the updated delivery and the previous driver or nil if the delivery was unclaimed.
+	ReassignDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, *string, error)
+	// GetStaleClaims returns the deliveries that were claimed before the given time and have not been picked up.
+	GetStaleClaims(ctx context.Context, before time.Time) ([]*models.Delivery, error)
 	GetByOrderId(ctx context.Context, orderId string) (*models.Delivery, error)
+	// SetOffer replaces the dispatcher offer of an unclaimed delivery. The offer is removed if offer is nil.
+	// prevRound is the round of the current offer or 0 if the delivery does not have an offer.
+	// It returns false if the delivery was claimed or the offer was changed by another dispatcher.
+	SetOffer(ctx context.Context, deliveryId bson.ObjectID, prevRound int, offer *models.Offer) (bool, error)
+	// GetExpiredOffers returns the unclaimed deliveries with offers that expired before the given time.
+	GetExpiredOffers(ctx context.Context, before time.Time) ([]*models.Delivery, error)
+	// GetOffers returns the deliveries that are currently offered to the driver.
+	GetOffers(ctx context.Context, driverId string) ([]*models.Delivery, error)
+	// DeclineOffer removes the driver from the current offer round.
+	// The round is ended if all drivers have declined.
+	DeclineOffer(ctx context.Context, deliveryId bson.ObjectID, driverId string) error
+	// GetDriverLoads returns the number of active deliveries assigned to each of the given drivers.
+	GetDriverLoads(ctx context.Context, driverIds []string) (map[string]int, error)
+	// EnsurePin sets the PIN of a delivery that does not have one and returns the PIN of the delivery.
+	// If another request already set the PIN, that PIN is returned instead.
+	EnsurePin(ctx context.Context, deliveryId bson.ObjectID, pin string) (string, error)
+	// UsePinAttempt records a PIN attempt by the driver and returns the number of attempts made.
+	// It returns [ErrPinLocked] if max attempts were already made and [ErrNotAssigned] if the delivery belongs to another driver.
+	UsePinAttempt(ctx context.Context, deliveryId bson.ObjectID, driverId string, maxAttempts int) (int, error)
+	// SetEstimates sets the estimated ready and delivery times of the delivery.
+	SetEstimates(ctx context.Context, deliveryId bson.ObjectID, readyAt *time.Time, deliveryAt *time.Time) error
 }
 
 type deliveryRepo struct {
@@ -47,6 +106,8 @@ func (d *deliveryRepo) AddDelivery(ctx context.Context, data *models.Delivery) (
 	data.Id = bson.NilObjectID
 	data.DriverId = nil
 	data.State = models.DeliveryStateUnclaimed
+	now := time.Now()
+	data.Timestamps = models.DeliveryTimestamps{CreatedAt: &now}
 
 	result, err := d.db.InsertOne(ctx, data)
 
@@ -61,27 +122,18 @@ func (d *deliveryRepo) AddDelivery(ctx context.Context, data *models.Delivery) (
 	return "", fmt.Errorf("mongo InsertOne result InsertedId is not a ObjectID got %v", result.InsertedID)
 }
 
-func (d *deliveryRepo) GetByDeliveryDriver(ctx context.Context, driverId string) ([]*models.Delivery, error) {
-	result, err := d.db.Find(ctx, bson.D{{Key: "driver_id", Value: driverId}})
-	if err != nil {
-		return nil, err
-	}
-
-	var deliveries []*models.Delivery
-	err = result.All(ctx, &deliveries)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(deliveries) == 0 {
-		deliveries = []*models.Delivery{}
-	}
-
-	return deliveries, nil
+func (d *deliveryRepo) GetByDeliveryDriver(ctx context.Context, driverId string, p pagination.Params) ([]*models.Delivery, string, error) {
+	return pagination.Find[*models.Delivery](ctx, d.db, bson.D{{Key: "driver_id", Value: driverId}}, p)
 }
 
 func (d *deliveryRepo) GetNearbyDeliveries(ctx context.Context, driverId string) ([]*models.Delivery, error) {
-	result, err := d.db.Find(ctx, bson.D{{Key: "driver_id", Value: nil}, {Key: "state", Value: models.DeliveryStateUnclaimed}})
+	// deliveries that are being offered by the dispatcher or are batched are not in the open pool.
+	result, err := d.db.Find(ctx, bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "state", Value: models.DeliveryStateUnclaimed},
+		{Key: "offer", Value: nil},
+		{Key: "batch_id", Value: nil},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -115,8 +167,26 @@ func (d *deliveryRepo) GetById(ctx context.Context, deliveryId bson.ObjectID) (*
 func (d *deliveryRepo) ClaimDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
 	var delivery models.Delivery
 	err := d.db.FindOneAndUpdate(ctx,
-		bson.D{{Key: "_id", Value: deliveryId}, {Key: "driver_id", Value: nil}, {Key: "state", Value: models.DeliveryStateUnclaimed}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "driver_id", Value: driverId}, {Key: "state", Value: models.DeliveryStateWaiting}}}},
+		bson.D{
+			{Key: "_id", Value: deliveryId},
+			{Key: "driver_id", Value: nil},
+			{Key: "state", Value: models.DeliveryStateUnclaimed},
+			// batched deliveries can only be claimed with the batch.
+			{Key: "batch_id", Value: nil},
+			// deliveries with an offer can only be claimed by the drivers in the current round.
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "offer", Value: nil}},
+				bson.D{{Key: "offer.drivers", Value: driverId}, {Key: "offer.expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}}},
+			}},
+		},
+		bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "driver_id", Value: driverId},
+				{Key: "state", Value: models.DeliveryStateWaiting},
+				{Key: models.DeliveryStateWaiting.TimestampField(), Value: time.Now()},
+			}},
+			{Key: "$unset", Value: bson.D{{Key: "offer", Value: ""}}},
+		},
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	).Decode(&delivery)
 	if err != nil {
@@ -129,13 +199,34 @@ func (d *deliveryRepo) ClaimDelivery(ctx context.Context, deliveryId bson.Object
 	return &delivery, nil
 }
 
+// DeliveryPickup implements DeliveryRepo.
 func (d *deliveryRepo) DeliveryPickup(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
+	return d.transition(ctx, deliveryId, driverId, models.DeliveryStateDelivering, nil)
+}
+
+// DeliveryComplete implements DeliveryRepo.
+func (d *deliveryRepo) DeliveryComplete(ctx context.Context, deliveryId bson.ObjectID, driverId string, proof *models.DeliveryProof) (*models.Delivery, error) {
+	return d.transition(ctx, deliveryId, driverId, models.DeliveryStateDone, bson.D{{Key: "proof", Value: proof}})
+}
+
+// FailDelivery implements DeliveryRepo.
+func (d *deliveryRepo) FailDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string, next models.DeliveryState, failure *models.DeliveryFailure) (*models.Delivery, error) {
+	if next != models.DeliveryStateFailed && next != models.DeliveryStateReturning {
+		return nil, fmt.Errorf("invalid failure state %s", next)
+	}
+
+	return d.transition(ctx, deliveryId, driverId, next, bson.D{{Key: "failure", Value: failure}})
+}
+
+// DeliveryReturned implements DeliveryRepo.
+func (d *deliveryRepo) DeliveryReturned(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
+	return d.transition(ctx, deliveryId, driverId, models.DeliveryStateReturned, nil)
+}
+
+// GetAssigned implements DeliveryRepo.
+func (d *deliveryRepo) GetAssigned(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
 	var delivery models.Delivery
-	err := d.db.FindOneAndUpdate(ctx,
-		bson.D{{Key: "_id", Value: deliveryId}, {Key: "driver_id", Value: driverId}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "state", Value: models.DeliveryStateDelivering}}}},
-		options.FindOneAndUpdate().SetReturnDocument(options.After),
-	).Decode(&delivery)
+	err := d.db.FindOne(ctx, bson.D{{Key: "_id", Value: deliveryId}}).Decode(&delivery)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return nil, ErrNoDelivery
@@ -143,19 +234,102 @@ func (d *deliveryRepo) DeliveryPickup(ctx context.Context, deliveryId bson.Objec
 		return nil, err
 	}
 
+	if delivery.DriverId == nil || *delivery.DriverId != driverId {
+		return nil, ErrNotAssigned
+	}
+
 	return &delivery, nil
 }
 
-func (d *deliveryRepo) DeliveryComplete(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
+// ReleaseDelivery implements DeliveryRepo.
+func (d *deliveryRepo) ReleaseDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, error) {
+	// released deliveries are removed from their batch so that they can be claimed by other drivers.
+	return d.transition(ctx, deliveryId, driverId, models.DeliveryStateUnclaimed, bson.D{{Key: "driver_id", Value: nil}, {Key: "batch_id", Value: nil}})
+}
+
+// ReassignDelivery implements DeliveryRepo.
+func (d *deliveryRepo) ReassignDelivery(ctx context.Context, deliveryId bson.ObjectID, driverId string) (*models.Delivery, *string, error) {
+	now := time.Now()
+
+	var delivery models.Delivery
+	err := d.db.FindOneAndUpdate(ctx,
+		bson.D{
+			{Key: "_id", Value: deliveryId},
+			{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{models.DeliveryStateUnclaimed, models.DeliveryStateWaiting}}}},
+		},
+		bson.D{
+			{Key: "$set", Value: bson.D{
+				{Key: "driver_id", Value: driverId},
+				{Key: "state", Value: models.DeliveryStateWaiting},
+				{Key: models.DeliveryStateWaiting.TimestampField(), Value: now},
+				{Key: "batch_id", Value: nil},
+			}},
+			{Key: "$unset", Value: bson.D{{Key: "offer", Value: ""}}},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, d.transitionError(ctx, deliveryId, "", models.DeliveryStateWaiting)
+		}
+		return nil, nil, err
+	}
+
+	prevDriver := delivery.DriverId
+	delivery.DriverId = &driverId
+	delivery.State = models.DeliveryStateWaiting
+	delivery.Offer = nil
+	delivery.BatchId = nil
+	delivery.Timestamps.ClaimedAt = &now
+
+	return &delivery, prevDriver, nil
+}
+
+// GetStaleClaims implements DeliveryRepo.
+func (d *deliveryRepo) GetStaleClaims(ctx context.Context, before time.Time) ([]*models.Delivery, error) {
+	return d.find(ctx, bson.D{
+		{Key: "state", Value: models.DeliveryStateWaiting},
+		{Key: models.DeliveryStateWaiting.TimestampField(), Value: bson.D{{Key: "$lt", Value: before}}},
+	})
+}
+
+// SetEstimates implements DeliveryRepo.
+func (d *deliveryRepo) SetEstimates(ctx context.Context, deliveryId bson.ObjectID, readyAt *time.Time, deliveryAt *time.Time) error {
+	result, err := d.db.UpdateByID(ctx, deliveryId, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "estimated_ready_at", Value: readyAt},
+		{Key: "estimated_delivery_at", Value: deliveryAt},
+	}}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNoDelivery
+	}
+
+	return nil
+}
+
+// transition moves a delivery assigned to the driver to the next state and records the time of the change.
+// The update only succeeds if the current state of the delivery can move to the next state.
+// Fields in set are updated along with the state.
+func (d *deliveryRepo) transition(ctx context.Context, deliveryId bson.ObjectID, driverId string, next models.DeliveryState, set bson.D) (*models.Delivery, error) {
 	var delivery models.Delivery
 	err := d.db.FindOneAndUpdate(ctx,
-		bson.D{{Key: "_id", Value: deliveryId}, {Key: "driver_id", Value: driverId}},
-		bson.D{{Key: "$set", Value: bson.D{{Key: "state", Value: models.DeliveryStateDone}}}},
+		bson.D{
+			{Key: "_id", Value: deliveryId},
+			{Key: "driver_id", Value: driverId},
+			{Key: "state", Value: bson.D{{Key: "$in", Value: next.PreviousStates()}}},
+		},
+		bson.D{{Key: "$set", Value: append(bson.D{
+			{Key: "state", Value: next},
+			{Key: next.TimestampField(), Value: time.Now()},
+		}, set...)}},
 		options.FindOneAndUpdate().SetReturnDocument(options.After),
 	).Decode(&delivery)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, ErrNoDelivery
+			return nil, d.transitionError(ctx, deliveryId, driverId, next)
 		}
 		return nil, err
 	}
@@ -163,6 +337,77 @@ func (d *deliveryRepo) DeliveryComplete(ctx context.Context, deliveryId bson.Obj
 	return &delivery, nil
 }
 
+// transitionError finds the reason a state transition of the delivery failed.
+// The driver of the delivery is not checked if driverId is empty.
+func (d *deliveryRepo) transitionError(ctx context.Context, deliveryId bson.ObjectID, driverId string, next models.DeliveryState) error {
+	var delivery models.Delivery
+	err := d.db.FindOne(ctx, bson.D{{Key: "_id", Value: deliveryId}}).Decode(&delivery)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return ErrNoDelivery
+		}
+		return err
+	}
+
+	if driverId != "" && (delivery.DriverId == nil || *delivery.DriverId != driverId) {
+		return ErrNotAssigned
+	}
+
+	return &InvalidStateError{State: delivery.State, Next: next}
+}
+
 func NewDeliveryRepo(db *mongo.Database) (DeliveryRepo, error) {
 	return &deliveryRepo{db: db.Collection("deliveries")}, nil
 }
+
+// EnsurePin implements DeliveryRepo.
+func (d *deliveryRepo) EnsurePin(ctx context.Context, deliveryId bson.ObjectID, pin string) (string, error) {
+	var delivery models.Delivery
+	err := d.db.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: deliveryId}, {Key: "pin", Value: bson.D{{Key: "$in", Value: bson.A{"", nil}}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "pin", Value: pin}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		// the PIN was already set. the delivery can be in any state, so only the id is matched.
+		var existing models.Delivery
+		err := d.db.FindOne(ctx, bson.D{{Key: "_id", Value: deliveryId}}).Decode(&existing)
+		if err == mongo.ErrNoDocuments {
+			return "", ErrNoDelivery
+		} else if err != nil {
+			return "", err
+		}
+		return existing.Pin, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return delivery.Pin, nil
+}
+
+// UsePinAttempt implements DeliveryRepo.
+func (d *deliveryRepo) UsePinAttempt(ctx context.Context, deliveryId bson.ObjectID, driverId string, maxAttempts int) (int, error) {
+	// the attempt is counted before the PIN is checked so that concurrent requests cannot exceed the limit
+	var delivery models.Delivery
+	err := d.db.FindOneAndUpdate(ctx,
+		bson.D{
+			{Key: "_id", Value: deliveryId},
+			{Key: "driver_id", Value: driverId},
+			{Key: "pin_attempts", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$gte", Value: maxAttempts}}}}},
+		},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "pin_attempts", Value: 1}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&delivery)
+	if err == mongo.ErrNoDocuments {
+		if _, err := d.GetAssigned(ctx, deliveryId, driverId); err != nil {
+			return 0, err
+		}
+		return 0, ErrPinLocked
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return delivery.PinAttempts, nil
+}
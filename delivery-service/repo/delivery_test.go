@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestEnsurePinClaimed(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	deliveries, err := NewDeliveryRepo(db)
+	if err != nil {
+		t.Fatalf("failed to create repo: %s", err)
+	}
+
+	// a delivery created before PINs were added
+	id, err := deliveries.AddDelivery(context.TODO(), &models.Delivery{OrderId: bson.NewObjectID().Hex()})
+	if err != nil {
+		t.Fatalf("failed to add delivery: %s", err)
+	}
+	deliveryId, _ := bson.ObjectIDFromHex(id)
+
+	if _, err := deliveries.ClaimDelivery(context.TODO(), deliveryId, "driver1"); err != nil {
+		t.Fatalf("failed to claim delivery: %s", err)
+	}
+
+	pin, err := deliveries.EnsurePin(context.TODO(), deliveryId, "1234")
+	if err != nil || pin != "1234" {
+		t.Fatalf("expected the pin to be set got %q %v", pin, err)
+	}
+
+	// another request already set the PIN
+	pin, err = deliveries.EnsurePin(context.TODO(), deliveryId, "4321")
+	if err != nil || pin != "1234" {
+		t.Errorf("expected the existing pin got %q %v", pin, err)
+	}
+
+	if _, err := deliveries.EnsurePin(context.TODO(), bson.NewObjectID(), "1234"); err != ErrNoDelivery {
+		t.Errorf("expected error %v got %v", ErrNoDelivery, err)
+	}
+}
+
+func TestReassignClearsBatch(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	deliveries, err := NewDeliveryRepo(db)
+	if err != nil {
+		t.Fatalf("failed to create repo: %s", err)
+	}
+
+	add := func() bson.ObjectID {
+		id, err := deliveries.AddDelivery(context.TODO(), &models.Delivery{OrderId: bson.NewObjectID().Hex()})
+		if err != nil {
+			t.Fatalf("failed to add delivery: %s", err)
+		}
+		deliveryId, _ := bson.ObjectIDFromHex(id)
+
+		// the delivery was claimed by driver1 as part of a batch
+		_, err = db.Collection("deliveries").UpdateByID(context.TODO(), deliveryId, bson.D{{Key: "$set", Value: bson.D{
+			{Key: "driver_id", Value: "driver1"},
+			{Key: "state", Value: models.DeliveryStateWaiting},
+			{Key: "batch_id", Value: bson.NewObjectID()},
+		}}})
+		if err != nil {
+			t.Fatalf("failed to claim delivery: %s", err)
+		}
+		return deliveryId
+	}
+
+	reassigned := add()
+	delivery, prevDriver, err := deliveries.ReassignDelivery(context.TODO(), reassigned, "driver2")
+	if err != nil {
+		t.Fatalf("failed to reassign delivery: %s", err)
+	}
+	if prevDriver == nil || *prevDriver != "driver1" {
+		t.Errorf("expected the previous driver to be driver1 got %v", prevDriver)
+	}
+	if delivery.BatchId != nil || delivery.DriverId == nil || *delivery.DriverId != "driver2" {
+		t.Errorf("expected the delivery to be assigned to driver2 without a batch got %v %v", delivery.DriverId, delivery.BatchId)
+	}
+
+	released := add()
+	delivery, err = deliveries.ReleaseDelivery(context.TODO(), released, "driver1")
+	if err != nil {
+		t.Fatalf("failed to release delivery: %s", err)
+	}
+	if delivery.BatchId != nil || delivery.DriverId != nil || delivery.State != models.DeliveryStateUnclaimed {
+		t.Errorf("expected the delivery to be unclaimed without a batch got %s %v %v", delivery.State, delivery.DriverId, delivery.BatchId)
+	}
+
+	// the stored delivery is also updated
+	var stored models.Delivery
+	if err := db.Collection("deliveries").FindOne(context.TODO(), bson.D{{Key: "_id", Value: reassigned}}).Decode(&stored); err != nil {
+		t.Fatalf("failed to get delivery: %s", err)
+	}
+	if stored.BatchId != nil {
+		t.Errorf("expected the stored batch to be removed got %v", stored.BatchId)
+	}
+}
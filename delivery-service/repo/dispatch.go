@@ -0,0 +1,132 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SetOffer implements DeliveryRepo.
+func (d *deliveryRepo) SetOffer(ctx context.Context, deliveryId bson.ObjectID, prevRound int, offer *models.Offer) (bool, error) {
+	filter := bson.D{
+		{Key: "_id", Value: deliveryId},
+		{Key: "driver_id", Value: nil},
+		{Key: "state", Value: models.DeliveryStateUnclaimed},
+	}
+	if prevRound == 0 {
+		filter = append(filter, bson.E{Key: "offer", Value: nil})
+	} else {
+		filter = append(filter, bson.E{Key: "offer.round", Value: prevRound})
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "offer", Value: offer}}}}
+	if offer == nil {
+		update = bson.D{{Key: "$unset", Value: bson.D{{Key: "offer", Value: ""}}}}
+	}
+
+	res, err := d.db.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return res.ModifiedCount > 0, nil
+}
+
+// GetExpiredOffers implements DeliveryRepo.
+func (d *deliveryRepo) GetExpiredOffers(ctx context.Context, before time.Time) ([]*models.Delivery, error) {
+	return d.find(ctx, bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "state", Value: models.DeliveryStateUnclaimed},
+		{Key: "offer.expires_at", Value: bson.D{{Key: "$lte", Value: before}}},
+	})
+}
+
+// GetOffers implements DeliveryRepo.
+func (d *deliveryRepo) GetOffers(ctx context.Context, driverId string) ([]*models.Delivery, error) {
+	return d.find(ctx, bson.D{
+		{Key: "driver_id", Value: nil},
+		{Key: "state", Value: models.DeliveryStateUnclaimed},
+		{Key: "offer.drivers", Value: driverId},
+		{Key: "offer.expires_at", Value: bson.D{{Key: "$gt", Value: time.Now()}}},
+	})
+}
+
+// DeclineOffer implements DeliveryRepo.
+func (d *deliveryRepo) DeclineOffer(ctx context.Context, deliveryId bson.ObjectID, driverId string) error {
+	res, err := d.db.UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: deliveryId},
+			{Key: "driver_id", Value: nil},
+			{Key: "state", Value: models.DeliveryStateUnclaimed},
+			{Key: "offer.drivers", Value: driverId},
+		},
+		bson.A{bson.D{{Key: "$set", Value: bson.D{
+			{Key: "offer.drivers", Value: bson.D{{Key: "$setDifference", Value: bson.A{"$offer.drivers", bson.A{driverId}}}}},
+			// end the round if this was the last driver in the round
+			{Key: "offer.expires_at", Value: bson.D{{Key: "$cond", Value: bson.D{
+				{Key: "if", Value: bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$size", Value: "$offer.drivers"}}, 1}}}},
+				{Key: "then", Value: "$$NOW"},
+				{Key: "else", Value: "$offer.expires_at"},
+			}}}},
+		}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		return ErrNoOffer
+	}
+
+	return nil
+}
+
+// GetDriverLoads implements DeliveryRepo.
+func (d *deliveryRepo) GetDriverLoads(ctx context.Context, driverIds []string) (map[string]int, error) {
+	cursor, err := d.db.Aggregate(ctx, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "driver_id", Value: bson.D{{Key: "$in", Value: driverIds}}},
+			{Key: "state", Value: bson.D{{Key: "$in", Value: bson.A{models.DeliveryStateWaiting, models.DeliveryStateDelivering, models.DeliveryStateReturning}}}},
+		}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$driver_id"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		DriverId string `bson:"_id"`
+		Count    int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	loads := make(map[string]int, len(results))
+	for _, result := range results {
+		loads[result.DriverId] = result.Count
+	}
+
+	return loads, nil
+}
+
+// find returns all deliveries matching the filter.
+func (d *deliveryRepo) find(ctx context.Context, filter bson.D) ([]*models.Delivery, error) {
+	result, err := d.db.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []*models.Delivery{}
+	err = result.All(ctx, &deliveries)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ErrDriverOffline indicates that the driver is not online.
+var ErrDriverOffline = errors.New("driver is not online")
+
+// DriverRepo stores the locations and availability reported by drivers.
+type DriverRepo interface {
+	// UpdateLocation sets the current location of the driver.
+	UpdateLocation(ctx context.Context, driverId string, position models.Point) error
+	// GetLocation returns the last location reported by the driver or nil if the driver never reported a location.
+	GetLocation(ctx context.Context, driverId string) (*models.DriverLocation, error)
+	// GetActiveDrivers returns the online drivers that reported their location after the given time.
+	GetActiveDrivers(ctx context.Context, since time.Time) ([]models.DriverLocation, error)
+	// GetStatus returns the status of the driver.
+	// Drivers that have never gone online are [models.DriverUnavailable].
+	GetStatus(ctx context.Context, driverId string) (models.DriverStatus, error)
+	// SetStatus sets the status of the driver.
+	SetStatus(ctx context.Context, driverId string, status models.DriverStatus) error
+	// ChangeStatus changes the status of the driver only if the current status is from.
+	// It returns false if the status was not changed.
+	ChangeStatus(ctx context.Context, driverId string, from models.DriverStatus, to models.DriverStatus) (bool, error)
+}
+
+type driverRepo struct {
+	db *mongo.Collection
+}
+
+// UpdateLocation implements DriverRepo.
+func (d *driverRepo) UpdateLocation(ctx context.Context, driverId string, position models.Point) error {
+	_, err := d.db.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: driverId}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "position", Value: position}, {Key: "updated_at", Value: time.Now()}}}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+// GetLocation implements DriverRepo.
+func (d *driverRepo) GetLocation(ctx context.Context, driverId string) (*models.DriverLocation, error) {
+	var driver models.DriverLocation
+	err := d.db.FindOne(ctx, bson.D{{Key: "_id", Value: driverId}}).Decode(&driver)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if driver.UpdatedAt.IsZero() {
+		// the driver went online without sending a location
+		return nil, nil
+	}
+
+	return &driver, nil
+}
+
+// GetActiveDrivers implements DriverRepo.
+func (d *driverRepo) GetActiveDrivers(ctx context.Context, since time.Time) ([]models.DriverLocation, error) {
+	result, err := d.db.Find(ctx, bson.D{
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{models.DriverAvailable, models.DriverBusy}}}},
+		{Key: "updated_at", Value: bson.D{{Key: "$gte", Value: since}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	drivers := []models.DriverLocation{}
+	err = result.All(ctx, &drivers)
+	if err != nil {
+		return nil, err
+	}
+
+	return drivers, nil
+}
+
+// GetStatus implements DriverRepo.
+func (d *driverRepo) GetStatus(ctx context.Context, driverId string) (models.DriverStatus, error) {
+	var driver models.DriverLocation
+	err := d.db.FindOne(ctx, bson.D{{Key: "_id", Value: driverId}}).Decode(&driver)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return models.DriverUnavailable, nil
+		}
+		return "", err
+	}
+
+	if driver.Status == "" {
+		return models.DriverUnavailable, nil
+	}
+
+	return driver.Status, nil
+}
+
+// SetStatus implements DriverRepo.
+func (d *driverRepo) SetStatus(ctx context.Context, driverId string, status models.DriverStatus) error {
+	_, err := d.db.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: driverId}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: status}}}},
+		options.UpdateOne().SetUpsert(true),
+	)
+	return err
+}
+
+// ChangeStatus implements DriverRepo.
+func (d *driverRepo) ChangeStatus(ctx context.Context, driverId string, from models.DriverStatus, to models.DriverStatus) (bool, error) {
+	result, err := d.db.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: driverId}, {Key: "status", Value: from}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: to}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func NewDriverRepo(db *mongo.Database) (DriverRepo, error) {
+	return &driverRepo{db: db.Collection("drivers")}, nil
+}
@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EarningsRepo stores the earnings ledger of drivers.
+type EarningsRepo interface {
+	// Credit adds an earning to the ledger.
+	// Earnings that were already credited are ignored.
+	Credit(ctx context.Context, earning *models.Earning) error
+	// GetEarnings returns the earnings created in [from, to) ordered by time.
+	// Earnings of all drivers are returned if driverId is empty.
+	GetEarnings(ctx context.Context, driverId string, from time.Time, to time.Time) ([]models.Earning, error)
+}
+
+type earningsRepo struct {
+	db *mongo.Collection
+}
+
+// Credit implements EarningsRepo.
+func (e *earningsRepo) Credit(ctx context.Context, earning *models.Earning) error {
+	_, err := e.db.InsertOne(ctx, earning)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+// GetEarnings implements EarningsRepo.
+func (e *earningsRepo) GetEarnings(ctx context.Context, driverId string, from time.Time, to time.Time) ([]models.Earning, error) {
+	filter := bson.D{{Key: "created_at", Value: bson.D{{Key: "$gte", Value: from}, {Key: "$lt", Value: to}}}}
+	if driverId != "" {
+		filter = append(filter, bson.E{Key: "driver_id", Value: driverId})
+	}
+
+	cursor, err := e.db.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := []models.Earning{}
+	err = cursor.All(ctx, &earnings)
+	if err != nil {
+		return nil, err
+	}
+
+	return earnings, nil
+}
+
+func NewEarningsRepo(db *mongo.Database) (EarningsRepo, error) {
+	return &earningsRepo{db: db.Collection("earnings")}, nil
+}
@@ -9,7 +9,13 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/delivery-service/app"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/ratelimit"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.uber.org/zap"
@@ -22,6 +28,8 @@ import (
 
 //go:generate protoc --go_out=./grpc/proto --go_opt=paths=source_relative  --go-grpc_out=./grpc/proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/order-service.proto
 
+//go:generate protoc --go_out=./grpc/proto --go_opt=paths=source_relative  --go-grpc_out=./grpc/proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/money.proto
+
 type Config struct {
 	Server struct {
 		Port int
@@ -33,6 +41,22 @@ type Config struct {
 	Database database.MongoConfig
 	Logger   logger.Config
 	Services app.ServiceConfig
+	Dispatch app.DispatchConfig
+	Release  app.ReleaseConfig
+	Proof    app.ProofConfig
+	Batch    app.BatchConfig
+	ETA      eta.Config
+	Earnings app.EarningsConfig
+	Tracing  tracing.Config
+
+	RateLimit ratelimit.Config
+}
+
+// grpcRules contains the services that are allowed to call the grpc methods of the service.
+var grpcRules = grpcauth.Rules{
+	"/DeliveryService/AddDelivery":          {"order-service"},
+	"/DeliveryService/GetDeliveryByOrderId": {"order-service", "review-service"},
+	"/DeliveryService/AddTip":               {"order-service"},
 }
 
 type Server struct {
@@ -47,12 +71,16 @@ func New(cfg *Config, db *mongo.Client) (*Server, error) {
 	s := &Server{
 		cfg:   cfg,
 		fiber: fiber.New(shared.DefaultFiberConfig),
-		grpc:  grpc.NewServer(grpc.ConnectionTimeout(time.Second * 10)),
+		grpc:  grpc.NewServer(grpc.ConnectionTimeout(time.Second*10), tracing.ServerOption(), metrics.ServerOption(), requestlog.ServerOption(), grpcauth.ServerOption(grpcRules)),
+	}
+
+	store, err := ratelimit.NewMongoStore(context.TODO(), db.Database("delivery-service"))
+	if err != nil {
+		return nil, err
 	}
-	shared.WithDefaultMiddleware(s.fiber)
+	shared.WithDefaultMiddleware(s.fiber, ratelimit.New(store, cfg.RateLimit, nil))
 
-	var err error
-	s.app, err = app.New(s.cfg.Services, db)
+	s.app, err = app.New(s.cfg.Services, s.cfg.Dispatch, s.cfg.Release, s.cfg.Proof, s.cfg.Batch, s.cfg.ETA, s.cfg.Earnings, db)
 	if err != nil {
 		return nil, err
 	}
@@ -64,6 +92,9 @@ func New(cfg *Config, db *mongo.Client) (*Server, error) {
 // This will block until ctx is cancelled.
 func (s *Server) Start(ctx context.Context) error {
 	go s.startGrpcServer(ctx)
+	go s.app.RunDispatcher(ctx)
+	go s.app.RunReleaser(ctx)
+	go s.app.RunBatcher(ctx)
 
 	address := fmt.Sprintf(":%d", s.cfg.Server.Port)
 	if s.cfg.Logger.HideBanner {
@@ -0,0 +1,62 @@
+// Package estimate calculates the estimated preparation and delivery times of orders.
+package estimate
+
+import (
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+)
+
+// Estimator calculates the estimated times of orders.
+type Estimator struct {
+	cfg eta.Config
+}
+
+// New creates a new estimator.
+func New(cfg eta.Config) *Estimator {
+	return &Estimator{cfg: cfg}
+}
+
+// PrepTime returns the time needed by the restaurant to prepare the order.
+func (e *Estimator) PrepTime(order *models.Order) time.Duration {
+	items := make([]time.Duration, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = time.Duration(item.PrepMinutes) * time.Minute
+	}
+
+	return e.cfg.PrepTime(time.Duration(order.Restaurant.PrepMinutes)*time.Minute, items...)
+}
+
+// TravelTime returns the time needed to deliver the order after it is picked up from the restaurant.
+func (e *Estimator) TravelTime(order *models.Order) time.Duration {
+	from, to := order.Restaurant.Location.Coordinates, order.Destination.Position.Coordinates
+	return e.cfg.TravelTime(from[1], from[0], to[1], to[0])
+}
+
+// Estimate returns the estimated ready and delivery times of the order based on its current status.
+// The existing estimates are returned for orders that are not being prepared or delivered.
+func (e *Estimator) Estimate(order *models.Order, now time.Time) (readyAt, deliveryAt *time.Time) {
+	readyAt = order.EstimatedReadyAt
+
+	switch order.Status {
+	case models.StatusPaymentPending, models.StatusPendingAccept, models.StatusPreparing:
+		// preparation starts when the restaurant accepts the order
+		ready := now.Add(e.PrepTime(order))
+		readyAt = &ready
+	case models.StatusAwaitingPickup, models.StatusDelivering:
+		if readyAt == nil || readyAt.After(now) {
+			readyAt = &now
+		}
+	default:
+		return order.EstimatedReadyAt, order.EstimatedDeliveryAt
+	}
+
+	delivery := readyAt.Add(e.TravelTime(order))
+	if delivery.Before(now) {
+		// the order was ready before the driver picked it up
+		delivery = now.Add(e.TravelTime(order))
+	}
+
+	return readyAt, &delivery
+}
@@ -0,0 +1,87 @@
+package estimate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+	"github.com/yehan2002/is/v2"
+)
+
+type estimateTest struct {
+	estimator *Estimator
+}
+
+func (e *estimateTest) order(status models.OrderStatus) *models.Order {
+	return &models.Order{
+		Status: status,
+		Items: []models.OrderItem{
+			{ItemId: "1", PrepMinutes: 10},
+			{ItemId: "2", PrepMinutes: 25},
+		},
+		Restaurant:  models.Restaurant{Id: "1", Location: models.Point{Coordinates: [2]float64{79.8, 6.9}}},
+		Destination: models.Address{Position: models.Point{Coordinates: [2]float64{79.8, 7.0}}},
+	}
+}
+
+func (e *estimateTest) TestPrepTime(is is.Is) {
+	order := e.order(models.StatusPreparing)
+	is.Equal(e.estimator.PrepTime(order), 25*time.Minute, "slowest item should be used")
+
+	order.Restaurant.PrepMinutes = 40
+	is.Equal(e.estimator.PrepTime(order), 40*time.Minute, "restaurant preparation time should be used")
+
+	order.Items = nil
+	order.Restaurant.PrepMinutes = 0
+	is.Equal(e.estimator.PrepTime(order), 15*time.Minute, "default preparation time should be used")
+}
+
+func (e *estimateTest) TestEstimatePreparing(is is.Is) {
+	now := time.Now()
+	order := e.order(models.StatusPreparing)
+
+	readyAt, deliveryAt := e.estimator.Estimate(order, now)
+	is.Equal(*readyAt, now.Add(25*time.Minute), "ready time should include the preparation time")
+	is.Equal(*deliveryAt, readyAt.Add(e.estimator.TravelTime(order)), "delivery time should include the travel time")
+}
+
+func (e *estimateTest) TestEstimateAwaitingPickup(is is.Is) {
+	now := time.Now()
+	order := e.order(models.StatusAwaitingPickup)
+
+	// order became ready earlier than estimated
+	expected := now.Add(10 * time.Minute)
+	order.EstimatedReadyAt = &expected
+
+	readyAt, deliveryAt := e.estimator.Estimate(order, now)
+	is.Equal(*readyAt, now, "ready time should be the current time")
+	is.Equal(*deliveryAt, now.Add(e.estimator.TravelTime(order)), "delivery time should start from the current time")
+
+	// order was waiting for a driver
+	earlier := now.Add(-time.Hour)
+	order.EstimatedReadyAt = &earlier
+
+	readyAt, deliveryAt = e.estimator.Estimate(order, now)
+	is.Equal(*readyAt, earlier, "past ready time should not change")
+	is.Equal(*deliveryAt, now.Add(e.estimator.TravelTime(order)), "delivery time should not be in the past")
+}
+
+func (e *estimateTest) TestEstimateFinished(is is.Is) {
+	now := time.Now()
+	order := e.order(models.StatusDelivered)
+	order.EstimatedReadyAt = &now
+	order.EstimatedDeliveryAt = &now
+
+	readyAt, deliveryAt := e.estimator.Estimate(order, now.Add(time.Hour))
+	is(readyAt == order.EstimatedReadyAt && deliveryAt == order.EstimatedDeliveryAt, "estimates of delivered orders should not change")
+}
+
+func TestEstimate(t *testing.T) {
+	is.Suite(t, &estimateTest{estimator: New(eta.Config{
+		SpeedKmh:        30,
+		RoadFactor:      1.3,
+		DefaultPrepTime: 15 * time.Minute,
+		HandoverTime:    2 * time.Minute,
+	})})
+}
@@ -6,8 +6,8 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type DeliveryClient struct {
@@ -18,7 +18,7 @@ var _ (repo.DeliveryRepo) = (*DeliveryClient)(nil)
 
 // AddDelivery implements repo.DeliveryRepo.
 func (d *DeliveryClient) AddDelivery(ctx context.Context, order *models.Order) (string, error) {
-	result, err := d.client.AddDelivery(ctx, &proto.DeliveryDetails{
+	details := &proto.DeliveryDetails{
 		OrderId: order.OrderId.Hex(),
 		UserId:  order.UserId,
 		Destination: &proto.DeliveryAddress{
@@ -41,7 +41,18 @@ func (d *DeliveryClient) AddDelivery(ctx context.Context, order *models.Order) (
 				Latitude:  order.Restaurant.Location.Coordinates[1],
 			},
 		},
-	})
+	}
+	if order.EstimatedReadyAt != nil {
+		details.EstimatedReadyAt = timestamppb.New(*order.EstimatedReadyAt)
+	}
+	if order.Price != nil {
+		details.DeliveryFee = toProtoMoney(order.Price.DeliveryFee)
+	}
+	if tip := order.TipTotal(); !tip.IsZero() {
+		details.Tip = toProtoMoney(tip)
+	}
+
+	result, err := d.client.AddDelivery(ctx, details)
 	if err != nil {
 		return "", err
 	}
@@ -49,8 +60,19 @@ func (d *DeliveryClient) AddDelivery(ctx context.Context, order *models.Order) (
 	return result.DeliverId, nil
 }
 
-func NewDeliveryClient(addr string) (*DeliveryClient, error) {
-	con, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// AddTip implements repo.DeliveryRepo.
+func (d *DeliveryClient) AddTip(ctx context.Context, orderId string, tip models.Tip) error {
+	_, err := d.client.AddTip(ctx, &proto.DeliveryTip{
+		OrderId: orderId,
+		TipId:   tip.TipId.Hex(),
+		Amount:  toProtoMoney(tip.Amount),
+	})
+	return err
+}
+
+// NewDeliveryClient creates a client for the delivery service at addr.
+func NewDeliveryClient(addr string, cfg grpcclient.Config) (*DeliveryClient, error) {
+	con, err := grpcclient.Dial("delivery-service", addr, cfg)
 	if err != nil {
 		return nil, err
 	}
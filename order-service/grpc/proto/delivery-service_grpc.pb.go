@@ -7,6 +7,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -19,6 +20,8 @@ const _ = grpc.SupportPackageIsVersion7
 type DeliveryServiceClient interface {
 	AddDelivery(ctx context.Context, in *DeliveryDetails, opts ...grpc.CallOption) (*DeliverId, error)
 	GetDeliveryByOrderId(ctx context.Context, in *DeliveryOrderId, opts ...grpc.CallOption) (*Delivery, error)
+	// Credits a tip that was paid after the order was delivered to the driver of the delivery.
+	AddTip(ctx context.Context, in *DeliveryTip, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type deliveryServiceClient struct {
@@ -47,12 +50,23 @@ func (c *deliveryServiceClient) GetDeliveryByOrderId(ctx context.Context, in *De
 	return out, nil
 }
 
+func (c *deliveryServiceClient) AddTip(ctx context.Context, in *DeliveryTip, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/DeliveryService/AddTip", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DeliveryServiceServer is the server API for DeliveryService service.
 // All implementations must embed UnimplementedDeliveryServiceServer
 // for forward compatibility
 type DeliveryServiceServer interface {
 	AddDelivery(context.Context, *DeliveryDetails) (*DeliverId, error)
 	GetDeliveryByOrderId(context.Context, *DeliveryOrderId) (*Delivery, error)
+	// Credits a tip that was paid after the order was delivered to the driver of the delivery.
+	AddTip(context.Context, *DeliveryTip) (*emptypb.Empty, error)
 	mustEmbedUnimplementedDeliveryServiceServer()
 }
 
@@ -66,6 +80,9 @@ func (UnimplementedDeliveryServiceServer) AddDelivery(context.Context, *Delivery
 func (UnimplementedDeliveryServiceServer) GetDeliveryByOrderId(context.Context, *DeliveryOrderId) (*Delivery, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryByOrderId not implemented")
 }
+func (UnimplementedDeliveryServiceServer) AddTip(context.Context, *DeliveryTip) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTip not implemented")
+}
 func (UnimplementedDeliveryServiceServer) mustEmbedUnimplementedDeliveryServiceServer() {}
 
 // UnsafeDeliveryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -115,6 +132,24 @@ func _DeliveryService_GetDeliveryByOrderId_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DeliveryService_AddTip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliveryTip)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DeliveryServiceServer).AddTip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/DeliveryService/AddTip",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DeliveryServiceServer).AddTip(ctx, req.(*DeliveryTip))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _DeliveryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "DeliveryService",
 	HandlerType: (*DeliveryServiceServer)(nil),
@@ -127,6 +162,10 @@ var _DeliveryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetDeliveryByOrderId",
 			Handler:    _DeliveryService_GetDeliveryByOrderId_Handler,
 		},
+		{
+			MethodName: "AddTip",
+			Handler:    _DeliveryService_AddTip_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "delivery-service.proto",
@@ -6,8 +6,8 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 )
 
 type RestaurantClient struct {
@@ -32,15 +32,25 @@ func (r *RestaurantClient) GetItemsById(ctx context.Context, ids []string) ([]mo
 			ItemId:      item.ItemId,
 			Name:        item.Name,
 			Description: item.Description,
-			Price:       item.Price,
+			Price:       itemPrice(item),
 			Restaurant:  item.RestaurantId,
 			Invalid:     item.Invalid,
+			PrepMinutes: int(item.PrepMinutes),
 		}
 	}
 
 	return items, nil
 }
 
+// itemPrice returns the price of the item.
+// The deprecated double price is used if the restaurant service did not send the price as money.
+func itemPrice(item *proto.Item) money.Money {
+	if item.UnitPrice != nil {
+		return money.New(item.UnitPrice.Amount, money.Currency(item.UnitPrice.Currency))
+	}
+	return money.FromFloat(item.Price, money.DefaultCurrency) //nolint: staticcheck
+}
+
 // GetRestaurantById implements repo.RestaurantRepo.
 func (r *RestaurantClient) GetRestaurantById(ctx context.Context, id string) (*models.Restaurant, error) {
 	res, err := r.client.GetRestaurantById(ctx, &proto.RestaurantId{RestaurantId: id})
@@ -56,11 +66,26 @@ func (r *RestaurantClient) GetRestaurantById(ctx context.Context, id string) (*m
 			Type:        "point",
 			Coordinates: [2]float64{res.Location.Latitude, res.Location.Longitude},
 		},
+		PrepMinutes: int(res.PrepMinutes),
 	}, nil
 }
 
-func NewRestaurantClient(addr string) (*RestaurantClient, error) {
-	con, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// CanDeliverTo implements repo.RestaurantRepo.
+func (r *RestaurantClient) CanDeliverTo(ctx context.Context, restaurantId string, position models.Point) (bool, error) {
+	res, err := r.client.CheckDelivery(ctx, &proto.DeliveryCheck{
+		RestaurantId: restaurantId,
+		Location:     &proto.Location{Longitude: position.Coordinates[0], Latitude: position.Coordinates[1]},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return res.Deliverable, nil
+}
+
+// NewRestaurantClient creates a client for the restaurant service at addr.
+func NewRestaurantClient(addr string, cfg grpcclient.Config) (*RestaurantClient, error) {
+	con, err := grpcclient.Dial("restaurant-service", addr, cfg, "/RestaurantService/GetItemsById", "/RestaurantService/GetRestaurantById", "/RestaurantService/CheckDelivery")
 	if err != nil {
 		return nil, err
 	}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc/proto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/notify"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.uber.org/zap"
@@ -15,6 +17,9 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// pendingRefundLimit is the maximum number of refunds returned by GetPendingRefunds.
+const pendingRefundLimit = 50
+
 type orderServiceServer struct {
 	proto.UnimplementedOrderServiceServer
 	orders repo.OrderRepo
@@ -22,7 +27,8 @@ type orderServiceServer struct {
 	user   proto.UserServiceClient
 }
 
-// GetOrderPrice gets the price for the order
+// GetOrderPrice gets the price for the order.
+// For delivered orders, the payable amount is the total of the pending tips.
 func (o *orderServiceServer) GetOrderPrice(ctx context.Context, req *proto.OrderId) (*proto.OrderPrice, error) {
 	orderId, err := bson.ObjectIDFromHex(req.OrderId)
 	if err != nil {
@@ -38,12 +44,43 @@ func (o *orderServiceServer) GetOrderPrice(ctx context.Context, req *proto.Order
 		return nil, status.Errorf(codes.Internal, "Failed to get order")
 	}
 
-	return &proto.OrderPrice{Price: order.Total}, nil
+	payable := order.Total
+	if order.Status == models.StatusDelivered {
+		payable = order.PendingTip()
+		if payable.IsZero() {
+			return nil, status.Errorf(codes.FailedPrecondition, "Order does not have a pending tip")
+		}
+	}
+
+	res := &proto.OrderPrice{
+		Price: payable.Float(),
+		Total: toProtoMoney(payable),
+		Tip:   toProtoMoney(order.TipTotal()),
+
+		Reference: order.PaymentReference(),
+	}
+
+	if p := order.Price; p != nil {
+		res.Breakdown = &proto.PriceBreakdown{
+			Subtotal:           toProtoMoney(p.Subtotal),
+			Discount:           toProtoMoney(p.Discount),
+			DeliveryDistanceKm: p.DeliveryDistance,
+			DeliveryFee:        toProtoMoney(p.DeliveryFee),
+			SmallOrderFee:      toProtoMoney(p.SmallOrderFee),
+			ServiceFee:         toProtoMoney(p.ServiceFee),
+			Tax:                toProtoMoney(p.Tax),
+			Tip:                toProtoMoney(p.Tip),
+			Total:              toProtoMoney(p.Total),
+		}
+	}
+
+	return res, nil
 
 }
 
 // SetPaymentStatus sets the result of the payment.
-// This can be used on orders that are currently in the PendingPayment state.
+// This can be used on orders that are currently in the PendingPayment state,
+// or on delivered orders to set the result of the tip payment.
 func (o *orderServiceServer) SetPaymentStatus(ctx context.Context, req *proto.PaymentStatus) (*emptypb.Empty, error) {
 	orderId, err := bson.ObjectIDFromHex(req.OrderId)
 	if err != nil {
@@ -51,6 +88,17 @@ func (o *orderServiceServer) SetPaymentStatus(ctx context.Context, req *proto.Pa
 	}
 
 	err = o.orders.UpdatePaymentStatus(ctx, orderId, req.Success, req.TransactionId)
+	if errors.Is(err, repo.ErrStateChange) {
+		// the order may be a delivered order with a pending tip
+		// payments without a tip reference were created before references were added
+		tipId, _ := models.ParseTipReference(req.Reference)
+		err = o.orders.UpdateTipPaymentStatus(ctx, orderId, tipId, req.Success, req.TransactionId)
+		if err == nil {
+			return &emptypb.Empty{}, nil
+		} else if errors.Is(err, repo.ErrNoOrder) {
+			err = repo.ErrStateChange
+		}
+	}
 	if err != nil {
 		return o.handleErr("PendingPayment", err)
 	}
@@ -110,7 +158,8 @@ func (o *orderServiceServer) SetRestaurantStatus(ctx context.Context, req *proto
 }
 
 // SetDeliveryDriver sets the delivery driver for an order.
-// This can be used on orders that are currently in the AwaitingPickup state.
+// This can be used on orders that are currently in the AwaitingPickup or Delivering state.
+// An empty driver id removes the driver from the order.
 func (o *orderServiceServer) SetDeliveryDriver(ctx context.Context, req *proto.OrderDriver) (*emptypb.Empty, error) {
 	orderId, err := bson.ObjectIDFromHex(req.OrderId)
 	if err != nil {
@@ -126,33 +175,144 @@ func (o *orderServiceServer) SetDeliveryDriver(ctx context.Context, req *proto.O
 }
 
 // SetDeliveryStatus sets the delivery status for an order.
-// This can be used on orders that are currently in the Delivering state.
+// Delivered can be used on orders that are currently in the Delivering state.
+// Failed can be used on orders that are awaiting pickup or being delivered, and Returned on orders that failed to deliver.
+// PickUp and Delivering update the estimated delivery time if it is set.
 func (o *orderServiceServer) SetDeliveryStatus(ctx context.Context, req *proto.DeliveryProgress) (*emptypb.Empty, error) {
 	orderId, err := bson.ObjectIDFromHex(req.OrderId)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Invalid order id")
 	}
 
-	// ignore the status if the delivery is not complete
-	if req.Status != proto.DeliveryStatus_Delivered {
-		return &emptypb.Empty{}, nil
+	switch req.Status {
+	case proto.DeliveryStatus_Delivered:
+		err = o.orders.SetOrderDelivered(ctx, orderId)
+		if err != nil {
+			return o.handleErr("Delivering", err)
+		}
+
+		o.notifyUser(ctx, orderId, "order-delivered", nil)
+
+	case proto.DeliveryStatus_Failed:
+		return o.deliveryFailed(ctx, orderId, req.FailureReason)
+
+	case proto.DeliveryStatus_Returned:
+		err = o.orders.SetOrderReturned(ctx, orderId)
+		if err != nil {
+			return o.handleErr("DeliveryFailed", err)
+		}
+
+	case proto.DeliveryStatus_PickUp, proto.DeliveryStatus_Delivering:
+		if req.EstimatedDeliveryAt == nil {
+			break
+		}
+
+		err = o.orders.SetEstimatedDelivery(ctx, orderId, req.EstimatedDeliveryAt.AsTime())
+		if err != nil {
+			return o.handleErr("AwaitingPickup or Delivering", err)
+		}
+	}
+
+	// other statuses do not change the order status
+	return &emptypb.Empty{}, nil
+}
+
+// deliveryFailed marks the order as failed to deliver, refunds the user if the failure was not caused by the user
+// and notifies the user.
+func (o *orderServiceServer) deliveryFailed(ctx context.Context, orderId bson.ObjectID, reason string) (*emptypb.Empty, error) {
+	order, err := o.orders.GetOrderById(ctx, orderId)
+	if err != nil {
+		return o.handleErr("AwaitingPickup or Delivering", err)
+	}
+
+	refund := order.RefundForFailure(reason)
+	err = o.orders.SetDeliveryFailed(ctx, orderId, reason, refund)
+	if err != nil {
+		return o.handleErr("AwaitingPickup or Delivering", err)
+	}
+
+	content := map[string]any{"reason": reason}
+	if refund != nil {
+		zap.L().Info("Refund created for failed delivery", zap.String("orderId", orderId.Hex()), zap.Stringer("amount", refund.Amount))
+		content["refund"] = refund.Amount.String()
 	}
+	o.notifyOrderUser(ctx, order, "order-delivery-failed", content)
 
-	err = o.orders.SetOrderDelivered(ctx, orderId)
+	return &emptypb.Empty{}, nil
+}
+
+// GetPendingRefunds gets the refunds that have not been processed by the payment service.
+func (o *orderServiceServer) GetPendingRefunds(ctx context.Context, _ *emptypb.Empty) (*proto.PendingRefunds, error) {
+	orders, err := o.orders.GetPendingRefunds(ctx, pendingRefundLimit)
 	if err != nil {
-		return o.handleErr("Delivering", err)
+		zap.L().Error("Failed to get pending refunds", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, "Failed to get pending refunds")
+	}
+
+	res := &proto.PendingRefunds{Refunds: make([]*proto.PendingRefund, 0, len(orders))}
+	for _, order := range orders {
+		res.Refunds = append(res.Refunds, &proto.PendingRefund{
+			OrderId:       order.OrderId.Hex(),
+			TransactionId: order.TransactionId,
+			Amount:        toProtoMoney(order.Refund.Amount),
+			Reason:        order.Refund.Reason,
+		})
+	}
+
+	return res, nil
+}
+
+// SetRefundStatus sets the result of the pending refund of an order and notifies the user if the refund was completed.
+func (o *orderServiceServer) SetRefundStatus(ctx context.Context, req *proto.RefundStatus) (*emptypb.Empty, error) {
+	orderId, err := bson.ObjectIDFromHex(req.OrderId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid order id")
+	}
+
+	err = o.orders.SetRefundStatus(ctx, orderId, req.Success, req.RefundId)
+	if errors.Is(err, repo.ErrStateChange) {
+		return nil, status.Errorf(codes.FailedPrecondition, "Order does not have a pending refund")
+	} else if err != nil {
+		return o.handleErr("DeliveryFailed or Returned", err)
+	}
+
+	if !req.Success {
+		// failed refunds are handled manually
+		zap.L().Error("Refund failed", zap.String("orderId", req.OrderId))
+		return &emptypb.Empty{}, nil
 	}
 
 	order, err := o.orders.GetOrderById(ctx, orderId)
-	if err == nil {
-		o.sendMessage(ctx, order.UserId, &notify.TemplateMessage{Type: notify.MsgTypSMS, Template: "order-delivered", Content: map[string]any{"orderId": order.OrderId}})
-	} else {
+	if err != nil {
 		zap.L().Error("Failed to send notification", zap.Error(err))
+		return &emptypb.Empty{}, nil
 	}
 
+	o.notifyOrderUser(ctx, order, "order-refunded", map[string]any{"refund": order.Refund.Amount.String()})
 	return &emptypb.Empty{}, nil
 }
 
+// notifyUser sends a notification about the order to the user that placed the order.
+func (o *orderServiceServer) notifyUser(ctx context.Context, orderId bson.ObjectID, template string, content map[string]any) {
+	order, err := o.orders.GetOrderById(ctx, orderId)
+	if err != nil {
+		zap.L().Error("Failed to send notification", zap.Error(err))
+		return
+	}
+
+	o.notifyOrderUser(ctx, order, template, content)
+}
+
+// notifyOrderUser sends a notification about the order to the user that placed the order.
+func (o *orderServiceServer) notifyOrderUser(ctx context.Context, order *models.Order, template string, content map[string]any) {
+	if content == nil {
+		content = map[string]any{}
+	}
+	content["orderId"] = order.OrderId
+
+	o.sendMessage(ctx, order.UserId, &notify.TemplateMessage{Type: notify.MsgTypSMS, Template: template, Content: content})
+}
+
 // handleErr handles returning the grpc error for the given error
 func (o *orderServiceServer) handleErr(expectedState string, err error) (*emptypb.Empty, error) {
 	if errors.Is(err, repo.ErrNoOrder) {
@@ -172,3 +332,7 @@ func NewServer(db repo.OrderRepo, notifier *notify.Notify, user proto.UserServic
 		user:   user,
 	}
 }
+
+func toProtoMoney(m money.Money) *proto.Money {
+	return &proto.Money{Amount: m.Amount, Currency: string(m.Currency)}
+}
@@ -1,12 +1,16 @@
 package orderservice
 
 import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/estimate"
 	grpc "github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/handlers"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/pricing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/gofiber/fiber/v3"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 )
 
@@ -14,6 +18,10 @@ func userPermissionCheck(c fiber.Ctx, tc middleware.TokenClaims) bool {
 	return c.Params("userId") == tc.UserId
 }
 
+func driverPermissionCheck(c fiber.Ctx, tc middleware.TokenClaims) bool {
+	return c.Params("driverId") == tc.UserId
+}
+
 // RegisterRoutes registers all routes in the server
 func (s *Server) RegisterRoutes() error {
 	db := s.db.Database("order-service")
@@ -23,18 +31,23 @@ func (s *Server) RegisterRoutes() error {
 		zap.L().Fatal("Failed to create cart repo", zap.Error(err))
 	}
 
-	order, err := repo.NewOrderRepo(db, cart, s.services.restaurant, s.services.delivery)
+	prices := pricing.New(s.cfg.Pricing)
+
+	order, err := repo.NewOrderRepo(db, cart, s.services.restaurant, s.services.delivery, prices, estimate.New(s.cfg.ETA))
 	if err != nil {
 		zap.L().Fatal("Failed to create order repo", zap.Error(err))
 	}
 
+	prometheus.MustRegister(repo.NewStatusCollector(order))
+
 	s.app.Use(middleware.Auth(s.key))
 
 	{
-		handler := handlers.NewCart(zap.L(), cart)
+		handler := handlers.NewCart(zap.L(), cart, s.services.restaurant, prices)
 		group := s.app.Group("/cart/:userId")
 
 		group.Use(middleware.RequireRoleFunc(userPermissionCheck, "user_admin"))
+		group.Use(s.idempotency.Middleware())
 
 		group.Get("/", handler.GetCart)
 		group.Delete("/", handler.ClearCart)
@@ -54,15 +67,18 @@ func (s *Server) RegisterRoutes() error {
 		group.Get("/", handler.GetByAll)
 		group.Get("/by-restaurant/:restaurantId", handler.GetByRestaurant)
 		group.Get("/by-user/:userId", handler.GetByUser)
+		group.Get("/tips/by-driver/:driverId", handler.GetDriverTips, middleware.RequireRoleFunc(driverPermissionCheck, "user_admin"))
 
 		group.Get("/:orderId", handler.GetOrder)
 		group.Post("/:orderId/restaurant-status", handler.SetRestaurantOrderStatus)
 		group.Delete("/:orderId", handler.CancelOrder)
-		group.Post("/from-cart/:userId", handler.CreateOrder, middleware.RequireRoleFunc(userPermissionCheck, "user_admin"))
+		group.Post("/:orderId/tip", handler.AddTip, s.idempotency.Middleware())
+		group.Post("/from-cart/:userId", handler.CreateOrder, middleware.RequireRoleFunc(userPermissionCheck, "user_admin"), s.idempotency.Middleware())
 	}
 
 	{
 		proto.RegisterOrderServiceServer(s.grpc, grpc.NewServer(order, &s.services.notification, s.services.user))
+		health.RegisterServer(s.grpc)
 	}
 
 	return nil
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/pricing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -10,16 +13,20 @@ import (
 )
 
 type Cart struct {
-	repo     repo.CartRepo
-	log      *zap.Logger
-	validate *validate.Validator
+	repo       repo.CartRepo
+	restaurant repo.RestaurantRepo
+	pricing    *pricing.Engine
+	log        *zap.Logger
+	validate   *validate.Validator
 }
 
-func NewCart(logger *zap.Logger, cart repo.CartRepo) *Cart {
+func NewCart(logger *zap.Logger, cart repo.CartRepo, restaurant repo.RestaurantRepo, pricing *pricing.Engine) *Cart {
 	return &Cart{
-		repo:     cart,
-		log:      logger,
-		validate: validate.New(),
+		repo:       cart,
+		restaurant: restaurant,
+		pricing:    pricing,
+		log:        logger,
+		validate:   validate.New(),
 	}
 }
 
@@ -31,11 +38,39 @@ func (c *Cart) GetCart(ctx fiber.Ctx) (err error) {
 	}
 
 	// get cart from db
-	cart, err := c.repo.GetCartByUserId(ctx.RequestCtx(), userId)
+	cart, err := c.repo.GetCartByUserId(ctx.Context(), userId)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
 
+	// check if the restaurant can deliver to the location
+	if deliverTo := ctx.Query("deliverable_to"); deliverTo != "" && len(cart.Items) > 0 {
+		lat, lng, err := location.ParseLatLng(deliverTo)
+		if err != nil {
+			return ctx.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Delivery location must be in the format latitude,longitude"})
+		}
+
+		position := models.Point{Type: "Point", Coordinates: [2]float64{lng, lat}}
+		deliverable, err := c.restaurant.CanDeliverTo(ctx.Context(), cart.Items[0].Restaurant, position)
+		if err != nil {
+			return sendError(ctx, c.log, err)
+		}
+		cart.Deliverable = &deliverable
+
+		if deliverable {
+			// the price breakdown is shown to the user before checking out. The tip is added at checkout.
+			restaurant, err := c.restaurant.GetRestaurantById(ctx.Context(), cart.Items[0].Restaurant)
+			if err != nil {
+				return sendError(ctx, c.log, err)
+			}
+
+			cart.Price, err = c.pricing.Calculate(cart.SubtotalPrice, cart.TotalPrice, money.Money{}, restaurant.Location, position)
+			if err != nil {
+				return sendError(ctx, c.log, err)
+			}
+		}
+	}
+
 	return ctx.Status(200).JSON(models.Response{Ok: true, Data: cart})
 }
 
@@ -46,7 +81,7 @@ func (c *Cart) ClearCart(ctx fiber.Ctx) error {
 		return ctx.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Missing user id"})
 	}
 
-	err := c.repo.ClearCart(ctx.RequestCtx(), userId)
+	err := c.repo.ClearCart(ctx.Context(), userId)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -74,7 +109,7 @@ func (c *Cart) AddToCart(ctx fiber.Ctx) (err error) {
 		return sendError(ctx, c.log, err)
 	}
 
-	cart, err := c.repo.AddItem(ctx.RequestCtx(), userId, item.Id, item.Amount, item.Data)
+	cart, err := c.repo.AddItem(ctx.Context(), userId, item.Id, item.Amount, item.Data)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -94,7 +129,7 @@ func (c *Cart) RemoveFromCart(ctx fiber.Ctx) error {
 		return ctx.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Invalid cart item id"})
 	}
 
-	err = c.repo.RemoveItem(ctx.RequestCtx(), userId, cartItemId)
+	err = c.repo.RemoveItem(ctx.Context(), userId, cartItemId)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -127,7 +162,7 @@ func (c *Cart) UpdateCartItem(ctx fiber.Ctx) error {
 		return sendError(ctx, c.log, err)
 	}
 
-	cart, err := c.repo.UpdateItem(ctx.RequestCtx(), userId, cartItemId, item.Amount, item.Data)
+	cart, err := c.repo.UpdateItem(ctx.Context(), userId, cartItemId, item.Amount, item.Data)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -154,7 +189,7 @@ func (c *Cart) ApplyCoupon(ctx fiber.Ctx) (err error) {
 		return sendError(ctx, c.log, err)
 	}
 
-	cart, err := c.repo.SetCartCoupon(ctx.RequestCtx(), userId, coupon.Id)
+	cart, err := c.repo.SetCartCoupon(ctx.Context(), userId, coupon.Id)
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -169,7 +204,7 @@ func (c *Cart) RemoveCoupon(ctx fiber.Ctx) error {
 		return ctx.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Missing user id"})
 	}
 
-	cart, err := c.repo.SetCartCoupon(ctx.RequestCtx(), userId, "")
+	cart, err := c.repo.SetCartCoupon(ctx.Context(), userId, "")
 	if err != nil {
 		return sendError(ctx, c.log, err)
 	}
@@ -1,6 +1,9 @@
 package handlers
 
-import "github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+)
 
 type cartItem struct {
 	Id     string         `json:"item" validate:"required,min=1,max=32"`
@@ -25,4 +28,16 @@ type orderCreate struct {
 			Latitude  float64 `json:"lat"`
 		} `json:"position"`
 	} `json:"address"`
+
+	// Total is the cart total that was shown to the user.
+	Total *money.Money `json:"total" validate:"omitempty,min=0"`
+	// Prices contains the item prices that were shown to the user keyed by the cart item id.
+	Prices map[string]money.Money `json:"prices"`
+
+	// Tip is the tip for the driver.
+	Tip money.Money `json:"tip" validate:"min=0,max=100000"`
+}
+
+type tipCreate struct {
+	Amount money.Money `json:"amount" validate:"min=1,max=100000"`
 }
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"io"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
@@ -26,6 +28,15 @@ func sendError(ctx fiber.Ctx, log *zap.Logger, err error) error {
 		return ctx.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{Ok: false, Error: "Order with the given id was not found"})
 	case repo.ErrRestaurant:
 		return ctx.Status(fiber.StatusConflict).JSON(models.ErrorResponse{Ok: false, Error: "Cannot order from multiple restaurants"})
+	case repo.ErrNotDeliverable:
+		return ctx.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{Ok: false, Error: "Restaurant does not deliver to the given address"})
+	case repo.ErrCannotTip:
+		return ctx.Status(fiber.StatusConflict).JSON(models.ErrorResponse{Ok: false, Error: "Order cannot be tipped until it is delivered or while a tip payment is pending"})
+	}
+
+	var changedErr *repo.CartChangedError
+	if errors.As(err, &changedErr) {
+		return ctx.Status(fiber.StatusConflict).JSON(models.ErrorResponse{Ok: false, Error: "Cart has changed since it was last viewed", Reason: changedErr.Diff})
 	}
 
 	if verr, ok := err.(*validate.ValidationErrors); ok {
@@ -33,7 +44,7 @@ func sendError(ctx fiber.Ctx, log *zap.Logger, err error) error {
 	} else if fiberErr, ok := err.(*fiber.Error); ok {
 		return ctx.Status(fiberErr.Code).JSON(fiber.Map{"ok": false, "error": fiberErr.Message})
 	} else {
-		log.Error("Request failed due to error", zap.Error(err))
+		requestlog.Logger(ctx.Context(), log).Error("Request failed due to error", zap.Error(err))
 		return ctx.Status(500).JSON(models.ErrorResponse{Ok: false, Error: "Internal server error"})
 	}
 }
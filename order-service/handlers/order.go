@@ -5,13 +5,22 @@ import (
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/notify"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.uber.org/zap"
 )
 
+// orderPages contains the sort fields supported by the order lists.
+var orderPages = pagination.Options{
+	Sort:      map[string]string{"created_at": "created_at", "total": "total.amount"},
+	DateField: "created_at",
+}
+
 type Order struct {
 	repo     repo.OrderRepo
 	log      *zap.Logger
@@ -51,12 +60,14 @@ func (o *Order) CreateOrder(c fiber.Ctx) error {
 	address := order.Address.Address
 	address.Position = models.Point{Coordinates: [2]float64{coords.Longitude, coords.Latitude}, Type: "point"}
 
-	orderId, err := o.repo.CreateOrderFromCart(c.RequestCtx(), userId, &address)
+	expected := &models.CheckoutExpectation{Total: order.Total, Prices: order.Prices}
+
+	orderId, err := o.repo.CreateOrderFromCart(c.Context(), userId, &address, expected, order.Tip)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
 
-	err = o.notify.Send(c.RequestCtx(), &notify.SimpleMessage{Type: "email", To: []string{"abc"}, Content: "Order placed"})
+	err = o.notify.Send(c.Context(), &notify.SimpleMessage{Type: "email", To: []string{"abc"}, Content: "Order placed"})
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
@@ -77,12 +88,17 @@ func (o *Order) GetByRestaurant(c fiber.Ctx) error {
 		}
 	}
 
-	orders, err := o.repo.GetOrdersByRestaurant(c.RequestCtx(), restaurantId, models.OrderStatus(status))
+	page, err := pagination.FromQuery(c, orderPages)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: orders})
+	orders, next, err := o.repo.GetOrdersByRestaurant(c.Context(), restaurantId, models.OrderStatus(status), page)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(orders, next))
 }
 
 func (o *Order) GetByUser(c fiber.Ctx) error {
@@ -98,12 +114,17 @@ func (o *Order) GetByUser(c fiber.Ctx) error {
 		}
 	}
 
-	orders, err := o.repo.GetOrdersByUser(c.RequestCtx(), userId, models.OrderStatus(status))
+	page, err := pagination.FromQuery(c, orderPages)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	orders, next, err := o.repo.GetOrdersByUser(c.Context(), userId, models.OrderStatus(status), page)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: orders})
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(orders, next))
 }
 
 func (o *Order) GetByAll(c fiber.Ctx) error {
@@ -114,12 +135,17 @@ func (o *Order) GetByAll(c fiber.Ctx) error {
 		}
 	}
 
-	orders, err := o.repo.GetAllOrders(c.RequestCtx(), models.OrderStatus(status))
+	page, err := pagination.FromQuery(c, orderPages)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: orders})
+	orders, next, err := o.repo.GetAllOrders(c.Context(), models.OrderStatus(status), page)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(orders, next))
 }
 
 func (o *Order) GetOrder(c fiber.Ctx) error {
@@ -129,7 +155,7 @@ func (o *Order) GetOrder(c fiber.Ctx) error {
 		return c.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Invalid or missing order id"})
 	}
 
-	order, err := o.repo.GetOrderById(c.RequestCtx(), orderId)
+	order, err := o.repo.GetOrderById(c.Context(), orderId)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
@@ -161,11 +187,11 @@ func (o *Order) SetRestaurantOrderStatus(c fiber.Ctx) error {
 
 	switch models.OrderStatus(req.Status) {
 	case models.StatusPreparing:
-		err = o.repo.UpdateAcceptedStatus(c.RequestCtx(), orderId, true, "")
+		err = o.repo.UpdateAcceptedStatus(c.Context(), orderId, true, "")
 	case models.StatusRejected:
-		err = o.repo.UpdateAcceptedStatus(c.RequestCtx(), orderId, false, req.Reason)
+		err = o.repo.UpdateAcceptedStatus(c.Context(), orderId, false, req.Reason)
 	case models.StatusAwaitingPickup:
-		err = o.repo.SetOrderPickupReady(c.RequestCtx(), orderId)
+		err = o.repo.SetOrderPickupReady(c.Context(), orderId)
 	default:
 		return c.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Invalid status"})
 	}
@@ -186,10 +212,49 @@ func (o *Order) CancelOrder(c fiber.Ctx) error {
 		return c.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Invalid or missing order id"})
 	}
 
-	err = o.repo.CancelOrder(c.RequestCtx(), orderId)
+	err = o.repo.CancelOrder(c.Context(), orderId)
 	if err != nil {
 		return sendError(c, o.log, err)
 	}
 
 	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: "Order canceled successfully"})
 }
+
+func (o *Order) AddTip(c fiber.Ctx) error {
+	orderId, err := bson.ObjectIDFromHex(c.Params("orderId"))
+	if err != nil {
+		return c.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Invalid or missing order id"})
+	}
+
+	var req tipCreate
+	err = c.Bind().Body(&req)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	err = o.validate.Validate(req)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	tip, err := o.repo.AddTip(c.Context(), orderId, middleware.GetUser(c).UserId, req.Amount)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{Ok: true, Data: tip})
+}
+
+func (o *Order) GetDriverTips(c fiber.Ctx) error {
+	driverId := c.Params("driverId")
+	if len(driverId) == 0 {
+		return c.Status(400).JSON(models.ErrorResponse{Ok: false, Error: "Missing driver id"})
+	}
+
+	tips, err := o.repo.GetTipsByDriver(c.Context(), driverId)
+	if err != nil {
+		return sendError(c, o.log, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: tips})
+}
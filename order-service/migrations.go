@@ -0,0 +1,27 @@
+package orderservice
+
+import "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+
+// Migrations contains the database migrations of the service.
+// New migrations must be added at the end with a higher version.
+var Migrations = []migrate.Migration{
+	{
+		Version:     1,
+		Description: "Add order and cart indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("orders",
+				migrate.Index("user_id", "created_at"),
+				migrate.Index("restaurant.id", "created_at"),
+				migrate.Index("status"),
+				migrate.Index("delivery_id"),
+				migrate.Index("created_at"),
+			),
+			migrate.CreateIndexes("carts", migrate.Index("user_id")),
+		),
+	},
+	{
+		Version:     2,
+		Description: "Add pending refund index",
+		Up:          migrate.CreateIndexes("orders", migrate.Index("refund.status", "refund.created_at")),
+	},
+}
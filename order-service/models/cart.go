@@ -1,6 +1,9 @@
 package models
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 type Cart struct {
 	CartId bson.ObjectID `json:"-" bson:"_id,omitempty"`
@@ -10,8 +13,12 @@ type Cart struct {
 
 	// the following fields are not stored in the db.
 	// These values will be added by fetching the data from menu and promotion microservices.
-	SubtotalPrice float64 `json:"sub_total" bson:"-"`
-	TotalPrice    float64 `json:"total" bson:"-"`
+	SubtotalPrice money.Money `json:"sub_total" bson:"-"`
+	TotalPrice    money.Money `json:"total" bson:"-"`
+	// Deliverable is set if the cart was requested with a delivery location.
+	Deliverable *bool `json:"deliverable,omitempty" bson:"-"`
+	// Price is the price breakdown for the delivery location. It is set if the restaurant can deliver to the location.
+	Price *PriceBreakdown `json:"price_breakdown,omitempty" bson:"-"`
 }
 
 type Coupon struct {
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// CheckoutExpectation contains the cart details that were shown to the user before checking out.
+type CheckoutExpectation struct {
+	// Total is the order total including fees, taxes and the tip shown to the user.
+	// This is nil if the client did not send the total.
+	Total *money.Money
+	// Prices contains the item prices shown to the user keyed by the cart item id.
+	Prices map[string]money.Money
+}
+
+// CheckoutDiff describes the differences between the cart shown to the user and the current cart.
+type CheckoutDiff struct {
+	ExpectedTotal *money.Money     `json:"expected_total,omitempty"`
+	Total         money.Money      `json:"total"`
+	PriceChanges  []PriceChange    `json:"price_changes,omitempty"`
+	Unavailable   []CartItemStatus `json:"unavailable_items,omitempty"`
+	// Price is the current price breakdown. It is not set if the cart contains unavailable items.
+	Price *PriceBreakdown `json:"price_breakdown,omitempty"`
+}
+
+// PriceChange contains the old and new price of a cart item.
+type PriceChange struct {
+	CartItemId    bson.ObjectID `json:"cart_id"`
+	ItemId        string        `json:"item_id"`
+	Name          string        `json:"name"`
+	ExpectedPrice money.Money   `json:"expected_price"`
+	Price         money.Money   `json:"price"`
+}
+
+// CartItemStatus identifies a cart item that can no longer be ordered.
+type CartItemStatus struct {
+	CartItemId bson.ObjectID `json:"cart_id"`
+	ItemId     string        `json:"item_id"`
+}
+
+// HasChanges returns true if the diff contains any differences.
+func (d *CheckoutDiff) HasChanges() bool {
+	return d.ExpectedTotal != nil || len(d.PriceChanges) > 0 || len(d.Unavailable) > 0
+}
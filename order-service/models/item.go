@@ -1,15 +1,20 @@
 package models
 
-import "go.mongodb.org/mongo-driver/v2/bson"
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
 
 // Item contains the data returned by the menu microservice.
 type Item struct {
-	ItemId      string  `json:"item_id" bson:"item_id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Invalid     bool    `json:"invalid,omitempty"`
-	Restaurant  string  `json:"restaurant"`
+	ItemId      string      `json:"item_id" bson:"item_id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price"`
+	Invalid     bool        `json:"invalid,omitempty"`
+	Restaurant  string      `json:"restaurant"`
+	// PrepMinutes is the time needed to prepare the item. 0 if the restaurant did not set it.
+	PrepMinutes int `json:"-"`
 }
 
 // CartItem contains data about an item in an user's cart.
@@ -21,10 +26,11 @@ type CartItem struct {
 	Restaurant string         `json:"restaurant" bson:"restaurant"`
 
 	// Not stored in db because values can be changed by restaurant-service before checkout.
-	Name        string  `json:"name" bson:"-"`
-	Description string  `json:"description" bson:"-"`
-	Price       float64 `json:"price" bson:"-"`
-	Invalid     bool    `json:"invalid,omitempty" bson:"-"`
+	Name        string      `json:"name" bson:"-"`
+	Description string      `json:"description" bson:"-"`
+	Price       money.Money `json:"price" bson:"-"`
+	Invalid     bool        `json:"invalid,omitempty" bson:"-"`
+	PrepMinutes int         `json:"-" bson:"-"`
 }
 
 // OrderItem contains data about the data in an order.
@@ -34,5 +40,7 @@ type OrderItem struct {
 	Name   string         `json:"name" bson:"name"`
 	Amount int            `json:"amount" bson:"amount"`
 	Extra  map[string]any `json:"extra,omitempty" bson:"extra,omitempty"`
-	Price  float64        `json:"price" bson:"price"`
+	Price  money.Money    `json:"price" bson:"price"`
+	// PrepMinutes is the preparation time of the item when the order was created.
+	PrepMinutes int `json:"-" bson:"prep_minutes,omitempty"`
 }
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -30,10 +31,16 @@ const (
 	StatusDelivering OrderStatus = "delivering"
 	// StatusDelivered is the state when delivering the order completes.
 	StatusDelivered OrderStatus = "delivered"
+	// StatusDeliveryFailed is the state when the driver could not deliver the order.
+	// If the order was picked up, the state changes to [StatusReturned] once the driver returns it to the restaurant.
+	StatusDeliveryFailed OrderStatus = "delivery_failed"
+	// StatusReturned is the state when the order was returned to the restaurant after the delivery failed.
+	StatusReturned OrderStatus = "returned_to_restaurant"
 )
 
 var AllStatuses = []OrderStatus{
 	StatusPaymentPending, StatusPaymentFailed, StatusCanceled, StatusPendingAccept, StatusRejected, StatusPreparing, StatusAwaitingPickup, StatusDelivering, StatusDelivered,
+	StatusDeliveryFailed, StatusReturned,
 }
 
 var RestaurantStatuses = []OrderStatus{StatusRejected, StatusPreparing, StatusAwaitingPickup}
@@ -43,19 +50,32 @@ type Order struct {
 	UserId   string        `json:"user_id" bson:"user_id"`
 	Items    []OrderItem   `json:"items" bson:"items"`
 	Coupon   *Coupon       `json:"coupon,omitempty" bson:"coupon"`
-	Subtotal float64       `json:"subtotal" bson:"subtotal"`
-	Total    float64       `json:"total" bson:"total"`
+	Subtotal money.Money   `json:"subtotal" bson:"subtotal"`
+	Total    money.Money   `json:"total" bson:"total"`
+	// Price contains the itemized price. This is nil for orders created before fees were added.
+	Price *PriceBreakdown `json:"price_breakdown,omitempty" bson:"price_breakdown,omitempty"`
+	// Tips contains the tips for the driver.
+	Tips []Tip `json:"tips,omitempty" bson:"tips,omitempty"`
 
 	Status        OrderStatus `json:"status" bson:"status"`
 	TransactionId string      `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
 	RejectReason  string      `json:"restaurant_reject_reason,omitempty" bson:"res_rej_reason,omitempty"`
 	Driver        string      `json:"assigned_driver,omitempty" bson:"driver,omitempty"`
+	// DeliveryFailure is the reason the delivery failed. This is only set if the status is [StatusDeliveryFailed] or [StatusReturned].
+	DeliveryFailure string `json:"delivery_failure_reason,omitempty" bson:"delivery_failure,omitempty"`
+	// Refund is set if the user is refunded for a failed delivery.
+	Refund *Refund `json:"refund,omitempty" bson:"refund,omitempty"`
 
 	Restaurant  Restaurant `json:"restaurant" bson:"restaurant"`
 	Destination Address    `json:"destination" bson:"destination"`
 
 	DeliveryId string `json:"delivery_id,omitempty" bson:"delivery_id,omitempty"`
 
+	// EstimatedReadyAt is the time the order is expected to be ready for pickup.
+	EstimatedReadyAt *time.Time `json:"estimated_ready_at,omitempty" bson:"estimated_ready_at,omitempty"`
+	// EstimatedDeliveryAt is the time the order is expected to be delivered.
+	EstimatedDeliveryAt *time.Time `json:"estimated_delivery_at,omitempty" bson:"estimated_delivery_at,omitempty"`
+
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -0,0 +1,22 @@
+package models
+
+import "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+
+// PriceBreakdown contains the itemized price of an order.
+type PriceBreakdown struct {
+	// Subtotal is the total price of the items before discounts.
+	Subtotal money.Money `json:"subtotal" bson:"subtotal"`
+	// Discount is the amount removed by the coupon.
+	Discount money.Money `json:"discount" bson:"discount"`
+	// DeliveryDistance is the distance between the restaurant and the destination in kilometers.
+	DeliveryDistance float64     `json:"delivery_distance_km" bson:"delivery_distance_km"`
+	DeliveryFee      money.Money `json:"delivery_fee" bson:"delivery_fee"`
+	// SmallOrderFee is added when the discounted subtotal is below the small order threshold.
+	SmallOrderFee money.Money `json:"small_order_fee" bson:"small_order_fee"`
+	ServiceFee    money.Money `json:"service_fee" bson:"service_fee"`
+	Tax           money.Money `json:"tax" bson:"tax"`
+	// Tip is the tip for the driver added at checkout. Tips are not taxed.
+	Tip money.Money `json:"tip" bson:"tip"`
+	// Total is the amount the user has to pay.
+	Total money.Money `json:"total" bson:"total"`
+}
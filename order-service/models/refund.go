@@ -0,0 +1,47 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+)
+
+// RefundStatus is the status of a refund.
+type RefundStatus string
+
+const (
+	// RefundPending is the status of a refund until it is processed by the payment service.
+	RefundPending RefundStatus = "pending"
+	// RefundCompleted is the status of a refund that was completed by the payment provider.
+	RefundCompleted RefundStatus = "completed"
+	// RefundFailed is the status of a refund that was rejected by the payment provider. These must be handled manually.
+	RefundFailed RefundStatus = "failed"
+)
+
+// Refund is a refund issued to the user for an order that could not be delivered.
+type Refund struct {
+	Amount    money.Money  `json:"amount" bson:"amount"`
+	Reason    string       `json:"reason" bson:"reason"`
+	Status    RefundStatus `json:"status" bson:"status"`
+	CreatedAt time.Time    `json:"created_at" bson:"created_at"`
+	// RefundId is the id of the refund from the payment provider.
+	RefundId string `json:"refund_id,omitempty" bson:"refund_id,omitempty"`
+	// ProcessedAt is the time the payment service processed the refund.
+	ProcessedAt *time.Time `json:"processed_at,omitempty" bson:"processed_at,omitempty"`
+}
+
+// customerFailureReasons contains the delivery failure reasons that are caused by the customer.
+// Orders that fail for these reasons are not refunded.
+var customerFailureReasons = []string{"customer_unreachable", "wrong_address"}
+
+// RefundForFailure returns the refund for an order that could not be delivered for the given reason.
+// It returns nil if the failure was caused by the customer or the order was not paid.
+// Pending refunds are sent to the payment provider by payment-service.
+func (o *Order) RefundForFailure(reason string) *Refund {
+	if o.TransactionId == "" || slices.Contains(customerFailureReasons, reason) {
+		return nil
+	}
+
+	return &Refund{Amount: o.Total, Reason: reason, Status: RefundPending, CreatedAt: time.Now()}
+}
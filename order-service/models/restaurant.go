@@ -4,4 +4,6 @@ type Restaurant struct {
 	Id       string `json:"id" bson:"id"`
 	Name     string `json:"name" bson:"name"`
 	Location Point  `json:"location" bson:"location"`
+	// PrepMinutes is the usual preparation time of the restaurant when the order was created.
+	PrepMinutes int `json:"-" bson:"prep_minutes,omitempty"`
 }
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Tip is a tip for the driver that delivered the order.
+type Tip struct {
+	TipId  bson.ObjectID `json:"tip_id" bson:"tip_id"`
+	Amount money.Money   `json:"amount" bson:"amount"`
+	// DriverId is the driver that receives the tip.
+	// This is empty for tips added at checkout until a driver is assigned to the order.
+	DriverId string `json:"driver_id,omitempty" bson:"driver_id,omitempty"`
+	// AfterDelivery is true if the tip was added after the order was delivered.
+	// Tips added at checkout are paid together with the order.
+	AfterDelivery bool `json:"after_delivery" bson:"after_delivery"`
+	// Paid is true once the payment for a tip added after delivery succeeds.
+	Paid          bool      `json:"paid" bson:"paid"`
+	TransactionId string    `json:"transaction_id,omitempty" bson:"transaction_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at" bson:"created_at"`
+}
+
+// DriverTip is a tip received by a driver.
+type DriverTip struct {
+	OrderId bson.ObjectID `json:"order_id" bson:"order_id"`
+	Tip     Tip           `json:"tip" bson:"tip"`
+}
+
+// PendingTip returns the total of the tips added after delivery that have not been paid.
+func (o *Order) PendingTip() money.Money {
+	total := money.Zero(o.Total.Currency)
+	for _, tip := range o.Tips {
+		if tip.AfterDelivery && !tip.Paid {
+			total.Amount += tip.Amount.Amount
+		}
+	}
+	return total
+}
+
+// PaymentReference returns the reference of the payable amount of the order.
+// For delivered orders, this is the pending tip (tip:<tipId>). Otherwise, it is the order (order:<orderId>).
+func (o *Order) PaymentReference() string {
+	if o.Status == StatusDelivered {
+		for _, tip := range o.Tips {
+			if tip.AfterDelivery && !tip.Paid {
+				return TipReference(tip.TipId)
+			}
+		}
+	}
+	return "order:" + o.OrderId.Hex()
+}
+
+// TipReference returns the payment reference of a tip.
+func TipReference(tipId bson.ObjectID) string {
+	return "tip:" + tipId.Hex()
+}
+
+// ParseTipReference returns the tip id of a tip payment reference.
+func ParseTipReference(reference string) (bson.ObjectID, bool) {
+	hex, ok := strings.CutPrefix(reference, "tip:")
+	if !ok {
+		return bson.ObjectID{}, false
+	}
+	tipId, err := bson.ObjectIDFromHex(hex)
+	return tipId, err == nil
+}
+
+// TipTotal returns the total of the tips that were paid or will be paid with the order.
+func (o *Order) TipTotal() money.Money {
+	total := money.Zero(o.Total.Currency)
+	for _, tip := range o.Tips {
+		if !tip.AfterDelivery || tip.Paid {
+			total.Amount += tip.Amount.Amount
+		}
+	}
+	return total
+}
@@ -0,0 +1,148 @@
+// Package pricing calculates the fees and taxes for orders.
+package pricing
+
+import (
+	"math"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+)
+
+// Config contains the fee and tax settings. All amounts are in major units of the currency.
+type Config struct {
+	// Currency is the currency of the configured amounts.
+	Currency string
+
+	// DeliveryBaseFee is the delivery fee for distances up to DeliveryIncludedKm.
+	DeliveryBaseFee float64
+	// DeliveryIncludedKm is the distance covered by the base fee.
+	DeliveryIncludedKm float64
+	// DeliveryPerKm is the fee for each kilometer after DeliveryIncludedKm.
+	DeliveryPerKm float64
+	// DeliveryMaxFee is the maximum delivery fee. Zero disables the limit.
+	DeliveryMaxFee float64
+
+	// SmallOrderThreshold is the discounted subtotal below which the small order fee is added.
+	SmallOrderThreshold float64
+	SmallOrderFee       float64
+
+	// ServicePercent is the service fee as a percentage of the discounted subtotal.
+	ServicePercent float64
+	// ServiceMinFee is the minimum service fee.
+	ServiceMinFee float64
+	// ServiceMaxFee is the maximum service fee. Zero disables the limit.
+	ServiceMaxFee float64
+
+	// TaxPercent is the tax percentage applied to the discounted subtotal and all fees.
+	TaxPercent float64
+}
+
+// Engine calculates the price breakdown of orders.
+type Engine struct {
+	cfg      Config
+	currency money.Currency
+}
+
+// New creates a new pricing engine.
+func New(cfg Config) *Engine {
+	currency := money.Currency(cfg.Currency)
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	return &Engine{cfg: cfg, currency: currency}
+}
+
+// amount converts a configured amount to money.
+func (e *Engine) amount(major float64) money.Money {
+	return money.FromFloat(major, e.currency)
+}
+
+// Calculate calculates the price breakdown for an order.
+// subtotal is the price of the items, total is the price after applying discounts and tip is the driver tip.
+//
+// Each fee is rounded to the nearest minor unit separately and the tax is calculated once on the
+// discounted subtotal and the fees, so the fields of the breakdown always add up to the total.
+// The tip is added after calculating the tax.
+func (e *Engine) Calculate(subtotal, total, tip money.Money, restaurant models.Point, destination models.Point) (*models.PriceBreakdown, error) {
+	price := &models.PriceBreakdown{Subtotal: subtotal}
+
+	discount, err := subtotal.Sub(total)
+	if err != nil {
+		return nil, err
+	}
+	price.Discount = discount
+
+	price.DeliveryDistance = distance(restaurant, destination)
+	price.DeliveryFee = e.deliveryFee(price.DeliveryDistance)
+
+	if total.Amount < e.amount(e.cfg.SmallOrderThreshold).Amount {
+		price.SmallOrderFee = e.amount(e.cfg.SmallOrderFee)
+	} else {
+		price.SmallOrderFee = money.Zero(e.currency)
+	}
+
+	price.ServiceFee = e.serviceFee(total)
+
+	taxable := total
+	for _, fee := range []money.Money{price.DeliveryFee, price.SmallOrderFee, price.ServiceFee} {
+		if taxable, err = taxable.Add(fee); err != nil {
+			return nil, err
+		}
+	}
+
+	price.Tax = taxable.Percent(e.cfg.TaxPercent)
+	if price.Total, err = taxable.Add(price.Tax); err != nil {
+		return nil, err
+	}
+
+	price.Tip = money.New(tip.Amount, tip.Currency)
+	if price.Total, err = price.Total.Add(price.Tip); err != nil {
+		return nil, err
+	}
+
+	return price, nil
+}
+
+// deliveryFee calculates the delivery fee for the given distance.
+func (e *Engine) deliveryFee(km float64) money.Money {
+	fee := e.cfg.DeliveryBaseFee
+	if extra := km - e.cfg.DeliveryIncludedKm; extra > 0 {
+		fee += extra * e.cfg.DeliveryPerKm
+	}
+
+	if e.cfg.DeliveryMaxFee > 0 {
+		fee = math.Min(fee, e.cfg.DeliveryMaxFee)
+	}
+
+	return e.amount(fee)
+}
+
+// serviceFee calculates the service fee for the given discounted subtotal.
+func (e *Engine) serviceFee(total money.Money) money.Money {
+	fee := total.Percent(e.cfg.ServicePercent)
+
+	if minFee := e.amount(e.cfg.ServiceMinFee); fee.Amount < minFee.Amount {
+		fee = minFee
+	}
+
+	if e.cfg.ServiceMaxFee > 0 {
+		if maxFee := e.amount(e.cfg.ServiceMaxFee); fee.Amount > maxFee.Amount {
+			fee = maxFee
+		}
+	}
+
+	return fee
+}
+
+// distance returns the distance between two points in kilometers rounded to 100m.
+// Zero is returned if any of the points are not set.
+func distance(from, to models.Point) float64 {
+	if from.Coordinates == [2]float64{} || to.Coordinates == [2]float64{} {
+		return 0
+	}
+
+	km := location.DistanceKm(from.Coordinates[1], from.Coordinates[0], to.Coordinates[1], to.Coordinates[0])
+	return math.Round(km*10) / 10
+}
@@ -0,0 +1,105 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"github.com/yehan2002/is/v2"
+)
+
+var testConfig = Config{
+	Currency:            "LKR",
+	DeliveryBaseFee:     150,
+	DeliveryIncludedKm:  2,
+	DeliveryPerKm:       40,
+	DeliveryMaxFee:      800,
+	SmallOrderThreshold: 500,
+	SmallOrderFee:       50,
+	ServicePercent:      5,
+	ServiceMinFee:       20,
+	ServiceMaxFee:       300,
+	TaxPercent:          10,
+}
+
+// point creates a point from a latitude and longitude.
+func point(lat, lng float64) models.Point {
+	return models.Point{Coordinates: [2]float64{lng, lat}}
+}
+
+type pricingTest struct{}
+
+func TestPricing(t *testing.T) {
+	is.Suite(t, &pricingTest{})
+}
+
+func (p *pricingTest) TestBreakdown(is is.Is) {
+	engine := New(testConfig)
+
+	subtotal := money.New(200000, money.LKR)
+	total, _ := subtotal.Discount(10)
+
+	// about 5.9km
+	price, err := engine.Calculate(subtotal, total, money.Money{}, point(6.9271, 79.8612), point(6.8770, 79.8800))
+	is.Ok(err, "failed to calculate price")
+
+	is(price.Discount.Amount == 20000, "incorrect discount %s", price.Discount)
+	is(price.DeliveryDistance == 5.9, "incorrect distance %v", price.DeliveryDistance)
+	is(price.DeliveryFee.Amount == 30600, "incorrect delivery fee %s", price.DeliveryFee)
+	is(price.SmallOrderFee.IsZero(), "small order fee should not be added")
+	is(price.ServiceFee.Amount == 9000, "incorrect service fee %s", price.ServiceFee)
+	is(price.Tax.Amount == 21960, "incorrect tax %s", price.Tax)
+
+	sum := price.Subtotal.Amount - price.Discount.Amount + price.DeliveryFee.Amount + price.SmallOrderFee.Amount +
+		price.ServiceFee.Amount + price.Tax.Amount
+	is(sum == price.Total.Amount, "breakdown does not add up to the total")
+}
+
+func (p *pricingTest) TestSmallOrder(is is.Is) {
+	engine := New(testConfig)
+
+	subtotal := money.New(30000, money.LKR)
+	price, err := engine.Calculate(subtotal, subtotal, money.Money{}, models.Point{}, models.Point{})
+	is.Ok(err, "failed to calculate price")
+
+	is(price.DeliveryDistance == 0, "distance should be zero when the location is unknown")
+	is(price.DeliveryFee.Amount == 15000, "incorrect delivery fee %s", price.DeliveryFee)
+	is(price.SmallOrderFee.Amount == 5000, "small order fee should be added")
+	is(price.ServiceFee.Amount == 2000, "minimum service fee should be used")
+}
+
+func (p *pricingTest) TestFeeLimits(is is.Is) {
+	engine := New(testConfig)
+
+	subtotal := money.New(10000000, money.LKR)
+	price, err := engine.Calculate(subtotal, subtotal, money.Money{}, point(6.9271, 79.8612), point(7.2906, 80.6337))
+	is.Ok(err, "failed to calculate price")
+
+	is(price.DeliveryFee.Amount == 80000, "delivery fee should be limited")
+	is(price.ServiceFee.Amount == 30000, "service fee should be limited")
+}
+
+func (p *pricingTest) TestNoFees(is is.Is) {
+	engine := New(Config{})
+
+	subtotal := money.New(12345, money.LKR)
+	price, err := engine.Calculate(subtotal, subtotal, money.Money{}, point(6.9271, 79.8612), point(6.8770, 79.8800))
+	is.Ok(err, "failed to calculate price")
+
+	is(price.Total.Equal(subtotal), "total should equal the subtotal when there are no fees")
+}
+
+func (p *pricingTest) TestTip(is is.Is) {
+	engine := New(testConfig)
+
+	subtotal := money.New(100000, money.LKR)
+	withoutTip, err := engine.Calculate(subtotal, subtotal, money.Money{}, models.Point{}, models.Point{})
+	is.Ok(err, "failed to calculate price")
+
+	price, err := engine.Calculate(subtotal, subtotal, money.New(20000, money.LKR), models.Point{}, models.Point{})
+	is.Ok(err, "failed to calculate price")
+
+	is(price.Tip.Amount == 20000, "tip should be in the breakdown")
+	is(price.Tax.Equal(withoutTip.Tax), "tip should not be taxed")
+	is(price.Total.Amount == withoutTip.Total.Amount+20000, "tip should be added to the total")
+}
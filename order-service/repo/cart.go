@@ -7,6 +7,7 @@ import (
 	"math"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -190,8 +191,8 @@ func (c *cartRepo) ClearCart(ctx context.Context, userId UserId) error {
 
 // populateCart populates item details and coupon details by fetching the data over grpc
 func (c *cartRepo) populateCart(ctx context.Context, cart *models.Cart) error {
-	var subtotalPrice float64
-	var totalPrice float64
+	subtotalPrice := money.Zero(money.DefaultCurrency)
+	totalPrice := subtotalPrice
 
 	if len(cart.Items) > 0 {
 		ids := make([]string, len(cart.Items))
@@ -225,9 +226,19 @@ func (c *cartRepo) populateCart(ctx context.Context, cart *models.Cart) error {
 			item.Price = data.Price
 			item.Restaurant = data.Restaurant
 			item.Invalid = data.Invalid
+			item.PrepMinutes = data.PrepMinutes
+
+			// invalid items do not have a price
+			if item.Invalid {
+				continue
+			}
 
 			// update total price
-			subtotalPrice += data.Price * float64(item.Amount)
+			var err error
+			subtotalPrice, err = subtotalPrice.Add(data.Price.Mul(int64(item.Amount)))
+			if err != nil {
+				return fmt.Errorf("item %s: %w", item.ItemId, err)
+			}
 		}
 	}
 
@@ -239,9 +250,8 @@ func (c *cartRepo) populateCart(ctx context.Context, cart *models.Cart) error {
 		}
 		cart.Coupon = promo
 
-		// apply discount to total price
-		discount := (100 - math.Min(math.Max(1, promo.Discount), 99)) / 100
-		totalPrice = subtotalPrice * discount
+		// apply discount to total price. The discount is rounded once on the subtotal.
+		totalPrice, _ = subtotalPrice.Discount(math.Min(math.Max(1, promo.Discount), 99))
 	} else {
 		totalPrice = subtotalPrice
 	}
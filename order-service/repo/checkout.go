@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+)
+
+// CartChangedError is returned when creating an order if the cart contains items that cannot be ordered
+// or if the prices are different from the prices that were shown to the user.
+type CartChangedError struct {
+	Diff *models.CheckoutDiff
+}
+
+func (e *CartChangedError) Error() string { return "cart changed since checkout started" }
+
+// checkCart compares the cart and its price with the details shown to the user.
+// price is nil if the cart cannot be priced because it contains invalid items. The total is not checked in that case.
+// It returns nil if the cart can be ordered as expected.
+func checkCart(cart *models.Cart, price *models.PriceBreakdown, expected *models.CheckoutExpectation) *models.CheckoutDiff {
+	diff := &models.CheckoutDiff{Total: cart.TotalPrice}
+	if price != nil {
+		diff.Total, diff.Price = price.Total, price
+	}
+
+	for _, item := range cart.Items {
+		// invalid items can never be ordered
+		if item.Invalid {
+			diff.Unavailable = append(diff.Unavailable, models.CartItemStatus{CartItemId: item.CartItemId, ItemId: item.ItemId})
+			continue
+		}
+
+		if expected == nil {
+			continue
+		}
+
+		expectedPrice, ok := expected.Prices[item.CartItemId.Hex()]
+		if ok && !expectedPrice.Equal(item.Price) {
+			diff.PriceChanges = append(diff.PriceChanges, models.PriceChange{
+				CartItemId:    item.CartItemId,
+				ItemId:        item.ItemId,
+				Name:          item.Name,
+				ExpectedPrice: expectedPrice,
+				Price:         item.Price,
+			})
+		}
+	}
+
+	if price != nil && expected != nil && expected.Total != nil && !expected.Total.Equal(price.Total) {
+		diff.ExpectedTotal = expected.Total
+	}
+
+	if !diff.HasChanges() {
+		return nil
+	}
+
+	return diff
+}
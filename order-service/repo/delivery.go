@@ -9,6 +9,8 @@ import (
 
 type DeliveryRepo interface {
 	AddDelivery(ctx context.Context, order *models.Order) (string, error)
+	// AddTip credits a tip that was paid after delivery to the driver of the order.
+	AddTip(ctx context.Context, orderId string, tip models.Tip) error
 }
 
 type stubDeliveryService struct{}
@@ -19,6 +21,12 @@ func (s *stubDeliveryService) AddDelivery(ctx context.Context, order *models.Ord
 	return order.OrderId.Hex(), nil
 }
 
+// AddTip implements DeliveryRepo.
+func (s *stubDeliveryService) AddTip(ctx context.Context, orderId string, tip models.Tip) error {
+	zap.S().Infof("Sending tip for order %s to delivery service: %v", orderId, tip)
+	return nil
+}
+
 func NewDeliveryRepo() DeliveryRepo {
 	return &stubDeliveryService{}
 }
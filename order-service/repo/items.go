@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -29,7 +30,7 @@ func (s *stubItemRepo) GetItemsById(ctx context.Context, ids []string) ([]models
 				ItemId:      id,
 				Name:        "Test Item",
 				Description: "Item Description",
-				Price:       200,
+				Price:       money.New(20000, money.LKR),
 				Restaurant:  id[:12] + "000000000000",
 			}
 		}
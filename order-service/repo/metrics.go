@@ -0,0 +1,51 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+var ordersCreated = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "orders_created_total",
+	Help: "Number of orders created from carts.",
+})
+
+// statusCollector collects the number of orders in each status when metrics are scraped.
+type statusCollector struct {
+	orders OrderRepo
+	desc   *prometheus.Desc
+}
+
+// NewStatusCollector creates a collector that reports the number of orders in each status.
+func NewStatusCollector(orders OrderRepo) prometheus.Collector {
+	return &statusCollector{
+		orders: orders,
+		desc:   prometheus.NewDesc("orders_by_status", "Number of orders in each status.", []string{"status"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (s *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.desc
+}
+
+// Collect implements prometheus.Collector.
+func (s *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	counts, err := s.orders.CountByStatus(ctx)
+	if err != nil {
+		zap.L().Error("Failed to count orders by status", zap.Error(err))
+		ch <- prometheus.NewInvalidMetric(s.desc, err)
+		return
+	}
+
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(s.desc, prometheus.GaugeValue, float64(count), string(status))
+	}
+}
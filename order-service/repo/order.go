@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/estimate"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/pricing"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 var ErrNoOrder = fmt.Errorf("order not found")
@@ -16,14 +22,21 @@ var ErrEmptyCart = fmt.Errorf("cart is empty")
 var ErrStateChange = fmt.Errorf("invalid order state change")
 var ErrCannotCancelOrder = fmt.Errorf("cannot cancel order")
 var ErrRestaurant = fmt.Errorf("cannot order from multiple restaurants")
+var ErrCannotTip = fmt.Errorf("order cannot be tipped")
+var ErrNotDeliverable = fmt.Errorf("restaurant does not deliver to the address")
 
 type TransactionId = string
 type RestaurantId = string
 
 type OrderRepo interface {
-	GetAllOrders(ctx context.Context, status models.OrderStatus) ([]*models.Order, error)
+	// GetAllOrders gets a page of all orders. The returned cursor is empty if there are no more pages.
+	GetAllOrders(ctx context.Context, status models.OrderStatus, p pagination.Params) ([]*models.Order, string, error)
+	// CountByStatus returns the number of orders in each status.
+	CountByStatus(ctx context.Context) (map[models.OrderStatus]int64, error)
 	// CreateOrderFromCart creates a order from the users current cart content.
-	CreateOrderFromCart(ctx context.Context, userId UserId, location *models.Address) (bson.ObjectID, error)
+	// If the cart contains invalid items or does not match expected, a [*CartChangedError] is returned.
+	// expected can be nil to only check for invalid items. tip is the driver tip added to the order total.
+	CreateOrderFromCart(ctx context.Context, userId UserId, location *models.Address, expected *models.CheckoutExpectation, tip money.Money) (bson.ObjectID, error)
 	// CreateOrder creates a new order. (used for tests)
 	CreateOrder(ctx context.Context, order *models.Order) (bson.ObjectID, error)
 	// GetOrderById returns the order with the given id
@@ -38,13 +51,35 @@ type OrderRepo interface {
 	// SetOrderPickupReady marks the order as ready to pickup
 	SetOrderPickupReady(ctx context.Context, orderId bson.ObjectID) error
 	// SetDeliveryDriver sets the delivery driver that will deliver the order.
+	// The driver of an order that is being delivered can be changed if the delivery is reassigned.
+	// If driverId is empty, the driver is removed and the order goes back to awaiting pickup.
 	SetDeliveryDriver(ctx context.Context, orderId bson.ObjectID, driverId UserId) error
 	// SetOrderDelivered marks the order as delivered
 	SetOrderDelivered(ctx context.Context, orderId bson.ObjectID) error
-	// GetOrdersByRestaurant gets all orders for an restaurant
-	GetOrdersByRestaurant(ctx context.Context, restaurantId RestaurantId, filter models.OrderStatus) ([]*models.Order, error)
-	// GetOrdersByUser gets all orders for an user
-	GetOrdersByUser(ctx context.Context, userId RestaurantId, filter models.OrderStatus) ([]*models.Order, error)
+	// SetDeliveryFailed marks the order as failed to deliver and adds the refund if refund is not nil.
+	SetDeliveryFailed(ctx context.Context, orderId bson.ObjectID, reason string, refund *models.Refund) error
+	// GetPendingRefunds gets up to limit orders with a pending refund, oldest refund first.
+	GetPendingRefunds(ctx context.Context, limit int64) ([]*models.Order, error)
+	// SetRefundStatus sets the result of the pending refund of the order.
+	// It returns [ErrStateChange] if the refund is not pending, unless it was already given the same result.
+	SetRefundStatus(ctx context.Context, orderId bson.ObjectID, successful bool, refundId string) error
+	// SetOrderReturned marks an order that failed to deliver as returned to the restaurant.
+	SetOrderReturned(ctx context.Context, orderId bson.ObjectID) error
+	// SetEstimatedDelivery sets the estimated delivery time of an order that is awaiting pickup or being delivered.
+	SetEstimatedDelivery(ctx context.Context, orderId bson.ObjectID, deliveryAt time.Time) error
+	// GetOrdersByRestaurant gets a page of orders for an restaurant
+	GetOrdersByRestaurant(ctx context.Context, restaurantId RestaurantId, filter models.OrderStatus, p pagination.Params) ([]*models.Order, string, error)
+	// GetOrdersByUser gets a page of orders for an user
+	GetOrdersByUser(ctx context.Context, userId RestaurantId, filter models.OrderStatus, p pagination.Params) ([]*models.Order, string, error)
+	// AddTip adds a tip for the driver to a delivered order of the user.
+	// The tip will be pending until the payment is completed using [OrderRepo.UpdateTipPaymentStatus].
+	AddTip(ctx context.Context, orderId bson.ObjectID, userId UserId, amount money.Money) (*models.Tip, error)
+	// UpdateTipPaymentStatus updates the payment status of the pending tips of the order.
+	// If tipId is not zero, only the pending tip with the id is updated.
+	// Pending tips are removed if the payment failed.
+	UpdateTipPaymentStatus(ctx context.Context, orderId bson.ObjectID, tipId bson.ObjectID, successful bool, transactionId TransactionId) error
+	// GetTipsByDriver gets all tips received by the driver.
+	GetTipsByDriver(ctx context.Context, driverId UserId) ([]models.DriverTip, error)
 }
 
 type orderRepo struct {
@@ -53,10 +88,12 @@ type orderRepo struct {
 	cart       CartRepo
 	restaurant RestaurantRepo
 	delivery   DeliveryRepo
+	pricing    *pricing.Engine
+	estimator  *estimate.Estimator
 }
 
 // CreateOrderFromCart creates a order from the users current cart content.
-func (o *orderRepo) CreateOrderFromCart(ctx context.Context, userId UserId, location *models.Address) (bson.ObjectID, error) {
+func (o *orderRepo) CreateOrderFromCart(ctx context.Context, userId UserId, location *models.Address, expected *models.CheckoutExpectation, tip money.Money) (bson.ObjectID, error) {
 	session, err := o.client.StartSession()
 	if err != nil {
 		return bson.NilObjectID, err
@@ -73,6 +110,11 @@ func (o *orderRepo) CreateOrderFromCart(ctx context.Context, userId UserId, loca
 			return nil, ErrEmptyCart
 		}
 
+		// invalid items can never be ordered, so the cart is rejected before calculating the price
+		if slices.ContainsFunc(cart.Items, func(item models.CartItem) bool { return item.Invalid }) {
+			return nil, &CartChangedError{Diff: checkCart(cart, nil, expected)}
+		}
+
 		restaurantId := cart.Items[0].Restaurant
 		for _, item := range cart.Items {
 			if item.Restaurant != restaurantId {
@@ -85,31 +127,62 @@ func (o *orderRepo) CreateOrderFromCart(ctx context.Context, userId UserId, loca
 			return nil, err
 		}
 
+		// reject the order if the address is outside the delivery area of the restaurant
+		deliverable, err := o.restaurant.CanDeliverTo(ctx, restaurantId, location.Position)
+		if err != nil {
+			return nil, err
+		}
+		if !deliverable {
+			return nil, ErrNotDeliverable
+		}
+
+		// calculate the delivery fee, service fees and taxes
+		price, err := o.pricing.Calculate(cart.SubtotalPrice, cart.TotalPrice, tip, restaurant.Location, location.Position)
+		if err != nil {
+			return nil, err
+		}
+
+		// reject the order if the cart or the fees changed after the user viewed it
+		if diff := checkCart(cart, price, expected); diff != nil {
+			return nil, &CartChangedError{Diff: diff}
+		}
+
+		var tips []models.Tip
+		if tip.Amount > 0 {
+			tips = append(tips, models.Tip{TipId: bson.NewObjectID(), Amount: price.Tip, CreatedAt: time.Now()})
+		}
+
 		// convert cart items to [models.OrderItem]
 		orderItems := make([]models.OrderItem, len(cart.Items))
 		for i, item := range cart.Items {
 			orderItems[i] = models.OrderItem{
-				ItemId: item.ItemId,
-				Name:   item.Name,
-				Amount: item.Amount,
-				Extra:  item.Extra,
-				Price:  item.Price,
+				ItemId:      item.ItemId,
+				Name:        item.Name,
+				Amount:      item.Amount,
+				Extra:       item.Extra,
+				Price:       item.Price,
+				PrepMinutes: item.PrepMinutes,
 			}
 		}
 
-		// create the order
-		result, err := o.orders.InsertOne(ctx, models.Order{
+		order := models.Order{
 			UserId:      userId,
 			Items:       orderItems,
 			Coupon:      cart.Coupon,
 			Subtotal:    cart.SubtotalPrice,
-			Total:       cart.TotalPrice,
+			Total:       price.Total,
+			Price:       price,
+			Tips:        tips,
 			Destination: *location,
 			Status:      models.StatusPaymentPending,
 			Restaurant:  *restaurant,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
-		})
+		}
+		order.EstimatedReadyAt, order.EstimatedDeliveryAt = o.estimator.Estimate(&order, time.Now())
+
+		// create the order
+		result, err := o.orders.InsertOne(ctx, order)
 		if err != nil {
 			return nil, err
 		}
@@ -127,9 +200,35 @@ func (o *orderRepo) CreateOrderFromCart(ctx context.Context, userId UserId, loca
 		return bson.NilObjectID, err
 	}
 
+	ordersCreated.Inc()
 	return orderId.(bson.ObjectID), nil
 }
 
+// CountByStatus returns the number of orders in each status.
+func (o *orderRepo) CountByStatus(ctx context.Context) (map[models.OrderStatus]int64, error) {
+	cursor, err := o.orders.Aggregate(ctx, bson.A{
+		bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		Status models.OrderStatus `bson:"_id"`
+		Count  int64              `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.OrderStatus]int64, len(results))
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+
+	return counts, nil
+}
+
 // CreateOrder creates a new order. (used for tests)
 func (o *orderRepo) CreateOrder(ctx context.Context, order *models.Order) (bson.ObjectID, error) {
 	result, err := o.orders.InsertOne(ctx, order)
@@ -155,12 +254,24 @@ func (o *orderRepo) GetOrderById(ctx context.Context, orderId bson.ObjectID) (*m
 
 // SetDeliveryDriver sets the delivery driver that will deliver the order.
 func (o *orderRepo) SetDeliveryDriver(ctx context.Context, orderId bson.ObjectID, driverId UserId) error {
-	res, err := o.orders.UpdateByID(ctx, orderId, bson.A{bson.M{
-		"$set": bson.D{
-			updateIfStatus("status", models.StatusDelivering, models.StatusAwaitingPickup),
-			updateIfStatus("driver", driverId, models.StatusAwaitingPickup),
-		},
-	}})
+	update := bson.D{
+		updateIfStatus("status", models.StatusAwaitingPickup, models.StatusDelivering),
+		updateIfStatus("driver", "$$REMOVE", models.StatusDelivering),
+	}
+
+	if driverId != "" {
+		update = bson.D{
+			updateIfStatus("status", models.StatusDelivering, models.StatusAwaitingPickup, models.StatusDelivering),
+			updateIfStatus("driver", driverId, models.StatusAwaitingPickup, models.StatusDelivering),
+			// tips added at checkout are given to the assigned driver
+			updateIfStatus("tips", bson.M{"$map": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$tips", bson.A{}}},
+				"in":    bson.M{"$mergeObjects": bson.A{"$$this", bson.M{"driver_id": driverId}}},
+			}}, models.StatusAwaitingPickup, models.StatusDelivering),
+		}
+	}
+
+	res, err := o.orders.UpdateByID(ctx, orderId, bson.A{bson.M{"$set": update}})
 	if err != nil {
 		return err
 	}
@@ -169,11 +280,11 @@ func (o *orderRepo) SetDeliveryDriver(ctx context.Context, orderId bson.ObjectID
 		// Order not found
 		return ErrNoOrder
 	} else if res.ModifiedCount == 0 {
-		// If modified count is 0, the order was found but its status was not [StatusAwaitingPickup].
+		// If modified count is 0, the order was found but its status was not [StatusAwaitingPickup] or [StatusDelivering].
 		return ErrStateChange
 	}
 
-	return nil
+	return o.updateEstimates(ctx, orderId)
 }
 
 // UpdateAcceptedStatus updates the accepted status.
@@ -201,7 +312,11 @@ func (o *orderRepo) UpdateAcceptedStatus(ctx context.Context, orderId bson.Objec
 		return ErrStateChange
 	}
 
-	return nil
+	if !accepted {
+		return nil
+	}
+
+	return o.updateEstimates(ctx, orderId)
 }
 
 // SetOrderPickupReady marks the order as ready to pickup
@@ -230,6 +345,11 @@ func (o *orderRepo) SetOrderPickupReady(ctx context.Context, orderId bson.Object
 			// return nil, ErrStateChange
 		}
 
+		err = o.updateEstimates(ctx, orderId)
+		if err != nil {
+			return nil, err
+		}
+
 		order, err := o.GetOrderById(ctx, orderId)
 		if err != nil {
 			return nil, err
@@ -273,6 +393,142 @@ func (o *orderRepo) SetOrderDelivered(ctx context.Context, orderId bson.ObjectID
 	return nil
 }
 
+// SetDeliveryFailed implements OrderRepo.
+func (o *orderRepo) SetDeliveryFailed(ctx context.Context, orderId bson.ObjectID, reason string, refund *models.Refund) error {
+	update := bson.D{
+		updateIfStatus("status", models.StatusDeliveryFailed, models.StatusAwaitingPickup, models.StatusDelivering),
+		updateIfStatus("delivery_failure", reason, models.StatusAwaitingPickup, models.StatusDelivering),
+	}
+	if refund != nil {
+		update = append(update, updateIfStatus("refund", bson.M{"$literal": refund}, models.StatusAwaitingPickup, models.StatusDelivering))
+	}
+
+	res, err := o.orders.UpdateByID(ctx, orderId, bson.A{bson.M{"$set": update}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		// Order not found
+		return ErrNoOrder
+	} else if res.ModifiedCount == 0 {
+		// If modified count is 0, the order was found but its status was not [StatusAwaitingPickup] or [StatusDelivering].
+		return ErrStateChange
+	}
+
+	return nil
+}
+
+// GetPendingRefunds implements OrderRepo.
+func (o *orderRepo) GetPendingRefunds(ctx context.Context, limit int64) ([]*models.Order, error) {
+	cursor, err := o.orders.Find(ctx,
+		bson.D{{Key: "refund.status", Value: models.RefundPending}},
+		options.Find().SetSort(bson.D{{Key: "refund.created_at", Value: 1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []*models.Order{}
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// SetRefundStatus implements OrderRepo.
+func (o *orderRepo) SetRefundStatus(ctx context.Context, orderId bson.ObjectID, successful bool, refundId string) error {
+	status := models.RefundFailed
+	if successful {
+		status = models.RefundCompleted
+	}
+
+	res, err := o.orders.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: orderId}, {Key: "refund.status", Value: models.RefundPending}},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "refund.status", Value: status},
+			{Key: "refund.refund_id", Value: refundId},
+			{Key: "refund.processed_at", Value: time.Now()},
+		}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		order, err := o.GetOrderById(ctx, orderId)
+		if err != nil {
+			return err
+		}
+
+		// the payment service may retry after the status was set
+		if order.Refund == nil || order.Refund.Status != status || order.Refund.RefundId != refundId {
+			return ErrStateChange
+		}
+	}
+
+	return nil
+}
+
+// SetOrderReturned implements OrderRepo.
+func (o *orderRepo) SetOrderReturned(ctx context.Context, orderId bson.ObjectID) error {
+	res, err := o.orders.UpdateByID(ctx, orderId, bson.A{bson.M{
+		"$set": bson.D{
+			updateIfStatus("status", models.StatusReturned, models.StatusDeliveryFailed),
+		},
+	}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		// Order not found
+		return ErrNoOrder
+	} else if res.ModifiedCount == 0 {
+		// If modified count is 0, the order was found but its status was not [StatusDeliveryFailed].
+		return ErrStateChange
+	}
+
+	return nil
+}
+
+// SetEstimatedDelivery implements OrderRepo.
+func (o *orderRepo) SetEstimatedDelivery(ctx context.Context, orderId bson.ObjectID, deliveryAt time.Time) error {
+	res, err := o.orders.UpdateByID(ctx, orderId, bson.A{bson.M{
+		"$set": bson.D{
+			updateIfStatus("estimated_delivery_at", deliveryAt, models.StatusAwaitingPickup, models.StatusDelivering),
+		},
+	}})
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		// Order not found
+		return ErrNoOrder
+	}
+
+	// ModifiedCount is not checked because the estimate may not have changed.
+	return nil
+}
+
+// updateEstimates recalculates the estimated times of the order after its status changed.
+func (o *orderRepo) updateEstimates(ctx context.Context, orderId bson.ObjectID) error {
+	order, err := o.GetOrderById(ctx, orderId)
+	if err != nil {
+		return err
+	}
+
+	readyAt, deliveryAt := o.estimator.Estimate(order, time.Now())
+
+	_, err = o.orders.UpdateByID(ctx, orderId, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "estimated_ready_at", Value: readyAt},
+		{Key: "estimated_delivery_at", Value: deliveryAt},
+	}}})
+	return err
+}
+
 // UpdatePaymentStatus updates the payment status of the order
 func (o *orderRepo) UpdatePaymentStatus(ctx context.Context, orderId bson.ObjectID, successful bool, transactionId TransactionId) error {
 	newState := models.StatusPendingAccept
@@ -298,7 +554,11 @@ func (o *orderRepo) UpdatePaymentStatus(ctx context.Context, orderId bson.Object
 		return ErrStateChange
 	}
 
-	return nil
+	if !successful {
+		return nil
+	}
+
+	return o.updateEstimates(ctx, orderId)
 }
 
 var canCancelStatus = []models.OrderStatus{models.StatusPaymentPending, models.StatusPendingAccept, models.StatusPaymentFailed, models.StatusCanceled}
@@ -326,21 +586,19 @@ func (o *orderRepo) CancelOrder(ctx context.Context, orderId bson.ObjectID) erro
 	return nil
 }
 
-func (o *orderRepo) GetOrdersByRestaurant(ctx context.Context, restaurantId RestaurantId, status models.OrderStatus) ([]*models.Order, error) {
-	return o.getOrders(ctx, restaurantId, "", status)
+func (o *orderRepo) GetOrdersByRestaurant(ctx context.Context, restaurantId RestaurantId, status models.OrderStatus, p pagination.Params) ([]*models.Order, string, error) {
+	return o.getOrders(ctx, restaurantId, "", status, p)
 }
 
-func (o *orderRepo) GetOrdersByUser(ctx context.Context, userId UserId, status models.OrderStatus) ([]*models.Order, error) {
-	return o.getOrders(ctx, "", userId, status)
+func (o *orderRepo) GetOrdersByUser(ctx context.Context, userId UserId, status models.OrderStatus, p pagination.Params) ([]*models.Order, string, error) {
+	return o.getOrders(ctx, "", userId, status, p)
 }
 
-func (o *orderRepo) GetAllOrders(ctx context.Context, status models.OrderStatus) ([]*models.Order, error) {
-	return o.getOrders(ctx, "", "", status)
+func (o *orderRepo) GetAllOrders(ctx context.Context, status models.OrderStatus, p pagination.Params) ([]*models.Order, string, error) {
+	return o.getOrders(ctx, "", "", status, p)
 }
 
-func (o *orderRepo) getOrders(ctx context.Context, restaurantId RestaurantId, userId UserId, status models.OrderStatus) ([]*models.Order, error) {
-	var orders []*models.Order
-
+func (o *orderRepo) getOrders(ctx context.Context, restaurantId RestaurantId, userId UserId, status models.OrderStatus, p pagination.Params) ([]*models.Order, string, error) {
 	var filter = bson.D{}
 	if len(restaurantId) > 0 {
 		filter = append(filter, bson.E{Key: "restaurant.id", Value: restaurantId})
@@ -352,27 +610,17 @@ func (o *orderRepo) getOrders(ctx context.Context, restaurantId RestaurantId, us
 		filter = append(filter, bson.E{Key: "status", Value: status})
 	}
 
-	cursor, err := o.orders.Find(ctx, filter)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrNoOrder
-		}
-		return nil, err
-	}
-
-	if err := cursor.All(ctx, &orders); err != nil {
-		return nil, err
-	}
-
-	return orders, nil
+	return pagination.Find[*models.Order](ctx, o.orders, filter, p)
 }
 
-func NewOrderRepo(db *mongo.Database, cartRepo CartRepo, restaurant RestaurantRepo, delivery DeliveryRepo) (OrderRepo, error) {
+func NewOrderRepo(db *mongo.Database, cartRepo CartRepo, restaurant RestaurantRepo, delivery DeliveryRepo, engine *pricing.Engine, estimator *estimate.Estimator) (OrderRepo, error) {
 	return &orderRepo{
 		orders:     db.Collection("orders"),
 		cart:       cartRepo,
 		restaurant: restaurant,
 		client:     db.Client(),
 		delivery:   delivery,
+		pricing:    engine,
+		estimator:  estimator,
 	}, nil
 }
@@ -2,10 +2,16 @@ package repo
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/estimate"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/pricing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"github.com/yehan2002/is/v2"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
@@ -33,10 +39,10 @@ func (o *orderTest) TestCreateFromCart(is is.Is) {
 	cart, err := cartRepo.SetCartCoupon(context.TODO(), userId, couponId)
 	is.Ok(err, "failed to apply coupon")
 
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
-	orderId, err := repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{})
+	orderId, err := repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, nil, money.Money{})
 	is.Ok(err, "failed to create order")
 	is(orderId != bson.NilObjectID, "invalid obj id")
 
@@ -54,18 +60,108 @@ func (o *orderTest) TestCreateFromCart(is is.Is) {
 	is.Equal(order.Coupon.CouponId, couponId, "incorrect coupon id")
 }
 
+func (o *orderTest) TestCreateFromCartPriceChanged(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	itemId := bson.NewObjectID().Hex()
+	userId := bson.NewObjectID().Hex()
+
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	cart, err := cartRepo.AddItem(context.TODO(), userId, itemId, 2, nil)
+	is.Ok(err, "failed to add item")
+
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	oldTotal := money.New(cart.TotalPrice.Amount-5000, cart.TotalPrice.Currency)
+	_, err = repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, &models.CheckoutExpectation{
+		Total:  &oldTotal,
+		Prices: map[string]money.Money{cart.Items[0].CartItemId.Hex(): money.New(cart.Items[0].Price.Amount-2500, money.LKR)},
+	}, money.Money{})
+
+	var changedErr *CartChangedError
+	is(errors.As(err, &changedErr), "order should be rejected when the price changed")
+	is(changedErr.Diff.ExpectedTotal != nil && *changedErr.Diff.ExpectedTotal == oldTotal, "diff should contain the expected total")
+	is(changedErr.Diff.Total == cart.TotalPrice, "diff should contain the current total")
+	is(len(changedErr.Diff.PriceChanges) == 1, "diff should contain the changed item")
+	is(changedErr.Diff.PriceChanges[0].Price == cart.Items[0].Price, "diff should contain the current item price")
+
+	total := cart.TotalPrice
+	_, err = repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, &models.CheckoutExpectation{Total: &total}, money.Money{})
+	is.Ok(err, "order should be created when the total matches")
+}
+
+func (o *orderTest) TestCreateFromCartFeesChanged(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	userId := bson.NewObjectID().Hex()
+
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	cart, err := cartRepo.AddItem(context.TODO(), userId, bson.NewObjectID().Hex(), 1, nil)
+	is.Ok(err, "failed to add item")
+
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{Currency: "LKR", DeliveryBaseFee: 250}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	// the cart total does not contain the delivery fee
+	itemsTotal := cart.TotalPrice
+	_, err = repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, &models.CheckoutExpectation{Total: &itemsTotal}, money.Money{})
+
+	var changedErr *CartChangedError
+	is(errors.As(err, &changedErr), "order should be rejected when the total does not contain the fees")
+	is(changedErr.Diff.Price != nil, "diff should contain the price breakdown")
+	is(changedErr.Diff.Price.DeliveryFee == money.New(25000, money.LKR), "diff should contain the delivery fee")
+	is(changedErr.Diff.Total == money.New(itemsTotal.Amount+25000, money.LKR), "diff should contain the total with fees")
+
+	total := changedErr.Diff.Total
+	orderId, err := repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, &models.CheckoutExpectation{Total: &total}, money.Money{})
+	is.Ok(err, "order should be created when the total contains the fees")
+
+	order, err := repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "failed to get order")
+	is(order.Total == total, "order total should match the expected total")
+}
+
+func (o *orderTest) TestCreateFromCartInvalidItem(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	userId := bson.NewObjectID().Hex()
+
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	_, err = cartRepo.AddItem(context.TODO(), userId, bson.NewObjectID().Hex(), 1, nil)
+	is.Ok(err, "failed to add item")
+	_, err = cartRepo.AddItem(context.TODO(), userId, "invalid-item", 1, nil)
+	is.Ok(err, "failed to add item")
+
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	_, err = repo.CreateOrderFromCart(context.TODO(), userId, &models.Address{}, nil, money.Money{})
+
+	var changedErr *CartChangedError
+	is(errors.As(err, &changedErr), "order should not be created with invalid items")
+	is(len(changedErr.Diff.Unavailable) == 1, "diff should contain the invalid item")
+	is(changedErr.Diff.Unavailable[0].ItemId == "invalid-item", "incorrect invalid item")
+}
+
 func (o *orderTest) TestOrderPaymentSuccess(is is.Is) {
 	db, close := database.ConnectTestDB()
 	defer close()
 
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusPaymentPending,
 	})
 	is.Ok(err, "Failed to create order")
@@ -90,13 +186,13 @@ func (o *orderTest) TestOrderCancel(is is.Is) {
 	// setup repos
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	// create order
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusPaymentPending,
 	})
 	is.Ok(err, "Failed to create order")
@@ -106,7 +202,7 @@ func (o *orderTest) TestOrderCancel(is is.Is) {
 
 	orderId, err = repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusPreparing,
 	})
 	is.Ok(err, "Failed to create order")
@@ -122,13 +218,13 @@ func (o *orderTest) TestOrderAccept(is is.Is) {
 	// setup repos
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	// create order
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusPendingAccept,
 	})
 	is.Ok(err, "Failed to create order")
@@ -138,7 +234,7 @@ func (o *orderTest) TestOrderAccept(is is.Is) {
 
 	orderId, err = repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusCanceled,
 	})
 	is.Ok(err, "Failed to create order")
@@ -154,13 +250,13 @@ func (o *orderTest) TestOrderSetDriver(is is.Is) {
 	// setup repos
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	// create order
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusAwaitingPickup,
 	})
 	is.Ok(err, "Failed to create order")
@@ -168,9 +264,25 @@ func (o *orderTest) TestOrderSetDriver(is is.Is) {
 	err = repo.SetDeliveryDriver(context.Background(), orderId, "driver-id")
 	is.Ok(err, "Failed to set driver")
 
+	err = repo.SetDeliveryDriver(context.Background(), orderId, "driver-2")
+	is.Ok(err, "Failed to reassign driver")
+
+	order, err := repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is.Equal(order.Driver, "driver-2", "driver should be reassigned")
+	is.Equal(order.Status, models.StatusDelivering, "order should be delivering")
+
+	err = repo.SetDeliveryDriver(context.Background(), orderId, "")
+	is.Ok(err, "Failed to remove driver")
+
+	order, err = repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is.Equal(order.Driver, "", "driver should be removed")
+	is.Equal(order.Status, models.StatusAwaitingPickup, "order should be awaiting pickup")
+
 	orderId, err = repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusCanceled,
 	})
 	is.Ok(err, "Failed to create order")
@@ -186,13 +298,13 @@ func (o *orderTest) TestOrderSetDeliveryComplete(is is.Is) {
 	// setup repos
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	// create order
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusDelivering,
 	})
 	is.Ok(err, "Failed to create order")
@@ -202,7 +314,7 @@ func (o *orderTest) TestOrderSetDeliveryComplete(is is.Is) {
 
 	orderId, err = repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusCanceled,
 	})
 	is.Ok(err, "Failed to create order")
@@ -211,6 +323,110 @@ func (o *orderTest) TestOrderSetDeliveryComplete(is is.Is) {
 	is.Err(err, ErrStateChange, "should not allow changing status order")
 }
 
+func (o *orderTest) TestOrderDeliveryFailed(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	// setup repos
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	// create order
+	order := &models.Order{
+		UserId:        "12314124",
+		Total:         money.New(10000, money.LKR),
+		Status:        models.StatusDelivering,
+		TransactionId: "cs_test",
+	}
+	orderId, err := repo.CreateOrder(context.TODO(), order)
+	is.Ok(err, "Failed to create order")
+
+	err = repo.SetOrderReturned(context.Background(), orderId)
+	is.Err(err, ErrStateChange, "should not allow returning an order that did not fail")
+
+	err = repo.SetDeliveryFailed(context.Background(), orderId, "damaged", order.RefundForFailure("damaged"))
+	is.Ok(err, "Failed to set delivery failed")
+
+	saved, err := repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is.Equal(saved.Status, models.StatusDeliveryFailed, "order should be failed")
+	is.Equal(saved.DeliveryFailure, "damaged", "failure reason should be saved")
+	is(saved.Refund != nil && saved.Refund.Amount.Equal(order.Total), "order should be refunded")
+
+	err = repo.SetOrderReturned(context.Background(), orderId)
+	is.Ok(err, "Failed to set order returned")
+
+	err = repo.SetDeliveryFailed(context.Background(), orderId, "damaged", nil)
+	is.Err(err, ErrStateChange, "should not allow failing a returned order")
+}
+
+func (o *orderTest) TestRefundForFailure(is is.Is) {
+	order := &models.Order{Total: money.New(250000, money.LKR), TransactionId: "cs_test"}
+
+	is(order.RefundForFailure("customer_unreachable") == nil, "customer failures should not be refunded")
+	is(order.RefundForFailure("wrong_address") == nil, "customer failures should not be refunded")
+
+	refund := order.RefundForFailure("damaged")
+	is(refund != nil, "other failures should be refunded")
+	is.Equal(refund.Amount, order.Total, "refund should be the order total")
+	is.Equal(refund.Status, models.RefundPending, "refund should be pending")
+
+	unpaid := &models.Order{Total: money.New(250000, money.LKR)}
+	is(unpaid.RefundForFailure("damaged") == nil, "unpaid orders should not be refunded")
+}
+
+func (o *orderTest) TestOrderRefunds(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	// setup repos
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	// create a failed order with a refund
+	order := &models.Order{
+		UserId:        "12314124",
+		Total:         money.New(10000, money.LKR),
+		Status:        models.StatusDelivering,
+		TransactionId: "cs_test",
+	}
+	orderId, err := repo.CreateOrder(context.TODO(), order)
+	is.Ok(err, "Failed to create order")
+
+	err = repo.SetRefundStatus(context.Background(), orderId, true, "re_test")
+	is.Err(err, ErrStateChange, "should not allow completing a refund that does not exist")
+
+	err = repo.SetDeliveryFailed(context.Background(), orderId, "damaged", order.RefundForFailure("damaged"))
+	is.Ok(err, "Failed to set delivery failed")
+
+	pending, err := repo.GetPendingRefunds(context.Background(), 1000)
+	is.Ok(err, "Failed to get pending refunds")
+	is(slices.ContainsFunc(pending, func(o *models.Order) bool { return o.OrderId == orderId }), "refund should be pending")
+
+	err = repo.SetRefundStatus(context.Background(), orderId, true, "re_test")
+	is.Ok(err, "Failed to complete refund")
+
+	saved, err := repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is.Equal(saved.Refund.Status, models.RefundCompleted, "refund should be completed")
+	is.Equal(saved.Refund.RefundId, "re_test", "refund id should be saved")
+	is(saved.Refund.ProcessedAt != nil, "refund processed time should be saved")
+
+	pending, err = repo.GetPendingRefunds(context.Background(), 1000)
+	is.Ok(err, "Failed to get pending refunds")
+	is(!slices.ContainsFunc(pending, func(o *models.Order) bool { return o.OrderId == orderId }), "completed refund should not be pending")
+
+	err = repo.SetRefundStatus(context.Background(), orderId, true, "re_test")
+	is.Ok(err, "should allow retrying the same result")
+
+	err = repo.SetRefundStatus(context.Background(), orderId, false, "")
+	is.Err(err, ErrStateChange, "should not allow changing the result of a refund")
+}
+
 func (o *orderTest) TestOrderPrepareDone(is is.Is) {
 	db, close := database.ConnectTestDB()
 	defer close()
@@ -218,13 +434,13 @@ func (o *orderTest) TestOrderPrepareDone(is is.Is) {
 	// setup repos
 	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
 	is.Ok(err, "failed to create cart repo")
-	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo())
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
 	is.Ok(err, "failed to create repo")
 
 	// create order
 	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusPreparing,
 	})
 	is.Ok(err, "Failed to create order")
@@ -234,7 +450,7 @@ func (o *orderTest) TestOrderPrepareDone(is is.Is) {
 
 	orderId, err = repo.CreateOrder(context.TODO(), &models.Order{
 		UserId: "12314124",
-		Total:  100,
+		Total:  money.New(10000, money.LKR),
 		Status: models.StatusCanceled,
 	})
 	is.Ok(err, "Failed to create order")
@@ -242,3 +458,88 @@ func (o *orderTest) TestOrderPrepareDone(is is.Is) {
 	err = repo.SetOrderPickupReady(context.Background(), orderId)
 	is.Err(err, ErrStateChange, "should not allow changing status order")
 }
+
+func (o *orderTest) TestOrderTips(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	// setup repos
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	userId := bson.NewObjectID().Hex()
+	driverId := bson.NewObjectID().Hex()
+
+	// create order with a tip added at checkout
+	orderId, err := repo.CreateOrder(context.TODO(), &models.Order{
+		UserId: userId,
+		Total:  money.New(10000, money.LKR),
+		Status: models.StatusAwaitingPickup,
+		Tips:   []models.Tip{{TipId: bson.NewObjectID(), Amount: money.New(1000, money.LKR)}},
+	})
+	is.Ok(err, "Failed to create order")
+
+	_, err = repo.AddTip(context.TODO(), orderId, userId, money.New(500, money.LKR))
+	is.Err(err, ErrCannotTip, "should not allow tipping before delivery")
+
+	err = repo.SetDeliveryDriver(context.TODO(), orderId, driverId)
+	is.Ok(err, "Failed to set driver")
+	err = repo.SetOrderDelivered(context.TODO(), orderId)
+	is.Ok(err, "Failed to set delivered")
+
+	_, err = repo.AddTip(context.TODO(), orderId, bson.NewObjectID().Hex(), money.New(500, money.LKR))
+	is.Err(err, ErrNoOrder, "should not allow tipping orders of other users")
+
+	tip, err := repo.AddTip(context.TODO(), orderId, userId, money.New(500, money.LKR))
+	is.Ok(err, "Failed to add tip")
+	is(tip.DriverId == driverId, "tip should be given to the driver")
+
+	_, err = repo.AddTip(context.TODO(), orderId, userId, money.New(500, money.LKR))
+	is.Err(err, ErrCannotTip, "should not allow multiple pending tips")
+
+	tips, err := repo.GetTipsByDriver(context.TODO(), driverId)
+	is.Ok(err, "Failed to get tips")
+	is(len(tips) == 1, "pending tips should not be included")
+	is(tips[0].Tip.Amount.Amount == 1000, "checkout tip should be given to the driver")
+
+	order, err := repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is(order.PaymentReference() == models.TipReference(tip.TipId), "the payment should be for the pending tip")
+
+	err = repo.UpdateTipPaymentStatus(context.TODO(), orderId, bson.NewObjectID(), true, "abc123")
+	is.Err(err, ErrNoOrder, "should not pay a tip with a different id")
+
+	err = repo.UpdateTipPaymentStatus(context.TODO(), orderId, tip.TipId, true, "abc123")
+	is.Ok(err, "Failed to update tip payment status")
+
+	tips, err = repo.GetTipsByDriver(context.TODO(), driverId)
+	is.Ok(err, "Failed to get tips")
+	is(len(tips) == 2, "paid tips should be included")
+
+	order, err = repo.GetOrderById(context.TODO(), orderId)
+	is.Ok(err, "Failed to get order")
+	is(order.TipTotal().Amount == 1500, "incorrect tip total")
+	is(order.PendingTip().IsZero(), "tip should not be pending")
+}
+
+func (o *orderTest) TestCountByStatus(is is.Is) {
+	db, close := database.ConnectTestDB()
+	defer close()
+
+	cartRepo, err := NewCartRepo(db, NewItemRepo(), NewPromoRepo())
+	is.Ok(err, "failed to create cart repo")
+	repo, err := NewOrderRepo(db, cartRepo, NewRestaurantRepo(), NewDeliveryRepo(), pricing.New(pricing.Config{}), estimate.New(eta.Config{}))
+	is.Ok(err, "failed to create repo")
+
+	for _, status := range []models.OrderStatus{models.StatusPaymentPending, models.StatusPaymentPending, models.StatusDelivered} {
+		_, err = repo.CreateOrder(context.TODO(), &models.Order{UserId: "12314124", Status: status})
+		is.Ok(err, "Failed to create order")
+	}
+
+	counts, err := repo.CountByStatus(context.TODO())
+	is.Ok(err, "Failed to count orders")
+
+	is.Equal(counts, map[models.OrderStatus]int64{models.StatusPaymentPending: 2, models.StatusDelivered: 1}, "incorrect counts")
+}
@@ -9,6 +9,8 @@ import (
 
 type RestaurantRepo interface {
 	GetRestaurantById(ctx context.Context, id string) (*models.Restaurant, error)
+	// CanDeliverTo checks if the restaurant can deliver to the given position.
+	CanDeliverTo(ctx context.Context, restaurantId string, position models.Point) (bool, error)
 }
 
 type stubRestaurantRepo struct{}
@@ -27,6 +29,11 @@ func (s *stubRestaurantRepo) GetRestaurantById(ctx context.Context, id string) (
 	}, nil
 }
 
+// CanDeliverTo implements RestaurantRepo.
+func (s *stubRestaurantRepo) CanDeliverTo(ctx context.Context, restaurantId string, position models.Point) (bool, error) {
+	return true, nil
+}
+
 func NewRestaurantRepo() RestaurantRepo {
 	return &stubRestaurantRepo{}
 }
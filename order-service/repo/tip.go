@@ -0,0 +1,165 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+)
+
+// pendingTip matches tips added after delivery that have not been paid.
+var pendingTip = bson.D{{Key: "after_delivery", Value: true}, {Key: "paid", Value: false}}
+
+// AddTip implements OrderRepo.
+func (o *orderRepo) AddTip(ctx context.Context, orderId bson.ObjectID, userId UserId, amount money.Money) (*models.Tip, error) {
+	order, err := o.GetOrderById(ctx, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.UserId != userId {
+		return nil, ErrNoOrder
+	}
+
+	if order.Status != models.StatusDelivered || order.Driver == "" {
+		return nil, ErrCannotTip
+	}
+
+	// only allow one pending tip at a time so that the payable amount does not change during payment
+	for _, tip := range order.Tips {
+		if tip.AfterDelivery && !tip.Paid {
+			return nil, ErrCannotTip
+		}
+	}
+
+	tip := &models.Tip{
+		TipId:         bson.NewObjectID(),
+		Amount:        amount,
+		DriverId:      order.Driver,
+		AfterDelivery: true,
+		CreatedAt:     time.Now(),
+	}
+
+	res, err := o.orders.UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: orderId},
+			{Key: "status", Value: models.StatusDelivered},
+			{Key: "tips", Value: bson.D{{Key: "$not", Value: bson.D{{Key: "$elemMatch", Value: pendingTip}}}}},
+		},
+		bson.D{{Key: "$push", Value: bson.D{{Key: "tips", Value: tip}}}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.ModifiedCount == 0 {
+		// the order was changed after it was fetched
+		return nil, ErrCannotTip
+	}
+
+	return tip, nil
+}
+
+// UpdateTipPaymentStatus implements OrderRepo.
+func (o *orderRepo) UpdateTipPaymentStatus(ctx context.Context, orderId bson.ObjectID, tipId bson.ObjectID, successful bool, transactionId TransactionId) error {
+	tipFilter := pendingTip
+	arrayFilter := bson.D{
+		{Key: "tip.after_delivery", Value: true},
+		{Key: "tip.paid", Value: false},
+	}
+	if !tipId.IsZero() {
+		tipFilter = append(bson.D{{Key: "tip_id", Value: tipId}}, pendingTip...)
+		arrayFilter = append(arrayFilter, bson.E{Key: "tip.tip_id", Value: tipId})
+	}
+
+	filter := bson.D{
+		{Key: "_id", Value: orderId},
+		{Key: "tips", Value: bson.D{{Key: "$elemMatch", Value: tipFilter}}},
+	}
+
+	var res *mongo.UpdateResult
+	var err error
+	if successful {
+		res, err = o.orders.UpdateOne(ctx, filter,
+			bson.D{{Key: "$set", Value: bson.D{
+				{Key: "tips.$[tip].paid", Value: true},
+				{Key: "tips.$[tip].transaction_id", Value: transactionId},
+			}}},
+			options.UpdateOne().SetArrayFilters([]any{arrayFilter}),
+		)
+	} else {
+		res, err = o.orders.UpdateOne(ctx, filter, bson.D{{Key: "$pull", Value: bson.D{{Key: "tips", Value: tipFilter}}}})
+	}
+	if err != nil {
+		return err
+	}
+
+	if res.MatchedCount == 0 {
+		// Order not found or the order does not have any pending tips
+		return ErrNoOrder
+	}
+
+	if successful {
+		o.creditTips(ctx, orderId, transactionId)
+	}
+
+	return nil
+}
+
+// creditTips sends the tips paid by the transaction to the delivery service.
+// Errors are logged since the payment was already recorded.
+func (o *orderRepo) creditTips(ctx context.Context, orderId bson.ObjectID, transactionId TransactionId) {
+	order, err := o.GetOrderById(ctx, orderId)
+	if err != nil {
+		zap.L().Error("Failed to get order to credit tips", zap.String("orderId", orderId.Hex()), zap.Error(err))
+		return
+	}
+
+	for _, tip := range order.Tips {
+		if !tip.AfterDelivery || !tip.Paid || tip.TransactionId != transactionId {
+			continue
+		}
+
+		err = o.delivery.AddTip(ctx, orderId.Hex(), tip)
+		if err != nil {
+			zap.L().Error("Failed to credit tip", zap.String("orderId", orderId.Hex()), zap.Error(err))
+		}
+	}
+}
+
+// GetTipsByDriver implements OrderRepo.
+func (o *orderRepo) GetTipsByDriver(ctx context.Context, driverId UserId) ([]models.DriverTip, error) {
+	// tips added at checkout are paid with the order. Other tips are only included after they are paid.
+	cursor, err := o.orders.Aggregate(ctx, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "tips.driver_id", Value: driverId}}}},
+		bson.D{{Key: "$unwind", Value: "$tips"}},
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "tips.driver_id", Value: driverId},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "tips.after_delivery", Value: false}},
+				bson.D{{Key: "tips.paid", Value: true}},
+			}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.D{
+			{Key: "_id", Value: 0},
+			{Key: "order_id", Value: "$_id"},
+			{Key: "tip", Value: "$tips"},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "tip.created_at", Value: -1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tips := []models.DriverTip{}
+	if err := cursor.All(ctx, &tips); err != nil {
+		return nil, err
+	}
+
+	return tips, nil
+}
@@ -9,16 +9,25 @@ import (
 
 	services "github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/grpc/proto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/pricing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/order-service/repo"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/eta"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/idempotency"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/notify"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/ratelimit"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 //go:generate protoc --go_out=./grpc/proto --go_opt=paths=source_relative  --go-grpc_out=./grpc/proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/order-service.proto
@@ -29,6 +38,8 @@ import (
 
 //go:generate protoc --go_out=./grpc/proto --go_opt=paths=source_relative  --go-grpc_out=./grpc/proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/user-service.proto
 
+//go:generate protoc --go_out=./grpc/proto --go_opt=paths=source_relative  --go-grpc_out=./grpc/proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/money.proto
+
 type Config struct {
 	Server struct {
 		Port int
@@ -42,14 +53,36 @@ type Config struct {
 		Promotion  string
 		Delivery   string
 		User       string
+		Clients    grpcclient.Config
 	}
 
 	Notify notify.Config
 
+	Pricing pricing.Config
+
+	ETA eta.Config
+
+	Idempotency idempotency.Config
+
+	RateLimit ratelimit.Config
+
+	Tracing tracing.Config
+
 	Database database.MongoConfig
 	Logger   logger.Config
 }
 
+// grpcRules contains the services that are allowed to call the grpc methods of the service.
+var grpcRules = grpcauth.Rules{
+	"/OrderService/GetOrderPrice":       {"payment-service"},
+	"/OrderService/SetPaymentStatus":    {"payment-service"},
+	"/OrderService/SetRestaurantStatus": {"restaurant-service"},
+	"/OrderService/SetDeliveryDriver":   {"delivery-service"},
+	"/OrderService/SetDeliveryStatus":   {"delivery-service"},
+	"/OrderService/GetPendingRefunds":   {"payment-service"},
+	"/OrderService/SetRefundStatus":     {"payment-service"},
+}
+
 type Server struct {
 	app  *fiber.App
 	grpc *grpc.Server
@@ -57,6 +90,8 @@ type Server struct {
 	db   *mongo.Client
 	key  *rsa.PublicKey
 
+	idempotency *idempotency.Store
+
 	services struct {
 		items        repo.ItemRepo
 		restaurant   repo.RestaurantRepo
@@ -77,8 +112,24 @@ func New(cfg *Config, db *mongo.Client, key *rsa.PublicKey) *Server {
 	})
 
 	s.app.Use(middleware.Recover())
+	s.app.Use(tracing.Middleware())
+	s.app.Use(requestlog.Middleware())
+	metrics.Register(s.app)
+	health.Register(s.app)
+
+	limits, err := ratelimit.NewMongoStore(context.TODO(), db.Database("order-service"))
+	if err != nil {
+		zap.L().Fatal("Failed to create rate limit store", zap.Error(err))
+	}
+	ratelimit.New(limits, cfg.RateLimit, key).Register(s.app)
+
+	s.idempotency, err = idempotency.NewStore(context.TODO(), db.Database("order-service"), cfg.Idempotency)
+	if err != nil {
+		zap.L().Fatal("Failed to create idempotency store", zap.Error(err))
+	}
 
-	s.grpc = grpc.NewServer(grpc.ConnectionTimeout(time.Second * 10))
+	s.grpc = grpc.NewServer(grpc.ConnectionTimeout(time.Second*10), tracing.ServerOption(), metrics.ServerOption(), requestlog.ServerOption(), grpcauth.ServerOption(grpcRules),
+		s.idempotency.ServerOption("/OrderService/SetPaymentStatus"))
 
 	return s
 }
@@ -88,7 +139,7 @@ func (s *Server) ConnectServices() {
 	var err error
 
 	if len(s.cfg.Services.Restaurant) != 0 {
-		restaurantClient, err := services.NewRestaurantClient(s.cfg.Services.Restaurant)
+		restaurantClient, err := services.NewRestaurantClient(s.cfg.Services.Restaurant, s.cfg.Services.Clients)
 		if err != nil {
 			zap.L().Fatal("Failed to connect to restaurant service", zap.Error(err))
 		}
@@ -110,7 +161,7 @@ func (s *Server) ConnectServices() {
 	}
 
 	if len(s.cfg.Services.Delivery) != 0 {
-		s.services.delivery, err = services.NewDeliveryClient(s.cfg.Services.Delivery)
+		s.services.delivery, err = services.NewDeliveryClient(s.cfg.Services.Delivery, s.cfg.Services.Clients)
 		if err != nil {
 			zap.L().Fatal("Failed to connect to delivery service", zap.Error(err))
 		}
@@ -126,7 +177,7 @@ func (s *Server) ConnectServices() {
 		zap.L().Fatal("Failed to connect to notification service", zap.Error(err))
 	}
 
-	con, err := grpc.NewClient(s.cfg.Services.User, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	con, err := grpcclient.Dial("user-service", s.cfg.Services.User, s.cfg.Services.Clients, "/UserService/GetUserBy")
 	if err != nil {
 		zap.L().Fatal("Failed to connect to user service", zap.Error(err))
 	}
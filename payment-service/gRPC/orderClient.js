@@ -2,6 +2,7 @@ import path from "path";
 import { fileURLToPath } from "url";
 import { loadPackageDefinition, credentials } from "@grpc/grpc-js";
 import protoLoader from "@grpc/proto-loader";
+import { serviceAuth } from "./serviceAuth.js";
 
 const __dirname = path.dirname(fileURLToPath(import.meta.url));
 const PROTO_PATH = path.resolve(__dirname, "./order-service.proto");
@@ -19,4 +20,6 @@ const grpcObject = loadPackageDefinition(packageDefinition);
 const { OrderService } = grpcObject;
 
 // Create a gRPC client for OrderService
-export const orderClient = new OrderService(`${process.env.APP_SERVICES_ORDER}`, credentials.createInsecure());
+export const orderClient = new OrderService(`${process.env.APP_SERVICES_ORDER}`, credentials.createInsecure(), {
+  interceptors: [serviceAuth("payment-service")],
+});
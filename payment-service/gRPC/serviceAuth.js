@@ -0,0 +1,55 @@
+import crypto from "crypto";
+import fs from "fs";
+import { InterceptingCall } from "@grpc/grpc-js";
+
+// Service tokens are valid for 5 minutes and are reused until they are close to expiring.
+const TOKEN_LIFETIME = 5 * 60;
+
+let key;
+let cached = { token: "", expires: 0 };
+
+// Loads the ed25519 private key of this service the same way as the go services.
+function loadServiceKey() {
+  let data;
+  if (process.env.APP_SECRET_service_key !== undefined) {
+    data = process.env.APP_SECRET_service_key;
+  } else {
+    try {
+      data = fs.readFileSync("/run/secrets/service_key");
+    } catch {
+      data = fs.readFileSync("service.key");
+    }
+  }
+
+  return crypto.createPrivateKey(data);
+}
+
+const encode = (value) => Buffer.from(JSON.stringify(value)).toString("base64url");
+
+// Creates a jwt token that identifies this service to other services.
+function serviceToken(service) {
+  const now = Math.floor(Date.now() / 1000);
+  if (cached.token && now + TOKEN_LIFETIME / 5 < cached.expires) {
+    return cached.token;
+  }
+
+  key ??= loadServiceKey();
+
+  const expires = now + TOKEN_LIFETIME;
+  const body = `${encode({ alg: "EdDSA", typ: "JWT" })}.${encode({ sub: service, aud: "services", iat: now, exp: expires })}`;
+  const signature = crypto.sign(null, Buffer.from(body), key).toString("base64url");
+
+  cached = { token: `${body}.${signature}`, expires };
+  return cached.token;
+}
+
+// Returns a grpc interceptor that sends the service token with every call.
+export function serviceAuth(service) {
+  return (options, nextCall) =>
+    new InterceptingCall(nextCall(options), {
+      start(metadata, listener, next) {
+        metadata.set("authorization", `Bearer ${serviceToken(service)}`);
+        next(metadata, listener);
+      },
+    });
+}
@@ -1,6 +1,7 @@
 import express from "express";
 import dotenv from "dotenv";
 import paymentRoutes from "./routes/paymentRoutes.js";
+import { startRefunds } from "./services/refundService.js";
 
 
 dotenv.config();
@@ -17,4 +18,7 @@ app.use("/pay", paymentRoutes); // Fixed the path
 const PORT = process.env.PORT || 5000;
 app.listen(PORT, () => {
   console.log(`Server running on port ${PORT}`);
-});
\ No newline at end of file
+});
+
+// refunds for failed deliveries are created by order-service and sent to Stripe here
+startRefunds(Number(process.env.APP_REFUND_INTERVAL) || 60000);
\ No newline at end of file
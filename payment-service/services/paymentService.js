@@ -1,5 +1,6 @@
 import Stripe from "stripe";
 import dotenv from "dotenv";
+import { Metadata } from "@grpc/grpc-js";
 import { orderClient } from "../gRPC/orderClient.js";
 
 dotenv.config();
@@ -19,9 +20,14 @@ function getOrderAsync(request) {
   });
 }
 
-function updateOrderAsync(request) {
+// updateOrderAsync sets the payment status of the order.
+// The idempotency key makes order-service replay the first result if the status is sent again for the same session.
+function updateOrderAsync(request, idempotencyKey) {
+  const metadata = new Metadata();
+  metadata.set("idempotency-key", idempotencyKey);
+
   return new Promise((resolve, reject) => {
-    orderClient.SetPaymentStatus(request, (err, order) => {
+    orderClient.SetPaymentStatus(request, metadata, (err, order) => {
       if (err) return reject(err);
       resolve(order);
     });
@@ -29,8 +35,25 @@ function updateOrderAsync(request) {
 }
 
 
+// getMinorAmount returns the order total in minor units (cents).
+// Falls back to the legacy price field for older order-service versions.
+function getMinorAmount(order) {
+  if (order.total) {
+    return { amount: Number(order.total.amount), currency: order.total.currency || "LKR" };
+  }
+  return { amount: Math.round(order.price * 100), currency: "LKR" };
+}
+
+// getReference returns the reference of the payable amount (the order or the pending tip).
+// Falls back to the order id for older order-service versions.
+function getReference(order, orderId) {
+  return order.reference || `order:${orderId}`;
+}
+
 export const createPayment = async (orderId) => {
   const order = await getOrderAsync({ orderId: orderId });
+  const { amount, currency } = getMinorAmount(order);
+  const reference = getReference(order, orderId);
 
   const completeUrl = domain + "/api/v1/payments/done?session_id={CHECKOUT_SESSION_ID}";
   const paymentIntent = await stripe.checkout.sessions.create(
@@ -40,18 +63,19 @@ export const createPayment = async (orderId) => {
         {
           price_data: {
             product_data: { name: "test item" },
-            unit_amount: Math.round(order.price * 100),
-            currency: "LKR",
+            unit_amount: amount,
+            currency: currency,
           },
           quantity: 1
         }
       ],
-      currency: "lkr",
-      metadata: { orderId },
+      currency: currency.toLowerCase(),
+      metadata: { orderId, reference },
       success_url: completeUrl,
       cancel_url: completeUrl,
     },
-    { idempotencyKey: `pi_${orderId}` }
+    // each tip added after delivery has its own reference so that it gets a separate payment
+    { idempotencyKey: `pi_${reference}` }
   );
 
   return paymentIntent.url;
@@ -62,9 +86,14 @@ export const getPayment = async (sessionId) => {
   const payment = await stripe.checkout.sessions.retrieve(sessionId);
 
   const success = payment.payment_status === "no_payment_required" || payment.payment_status === "paid";
-  const result = { orderId: payment.metadata.orderId, success: success, transactionId: payment.id }
+  const result = {
+    orderId: payment.metadata.orderId,
+    success: success,
+    transactionId: payment.id,
+    reference: payment.metadata.reference || "",
+  };
 
-  await updateOrderAsync(result);
+  await updateOrderAsync(result, `payment:${payment.id}:${payment.payment_status}`);
 
   return payment.metadata.orderId
 };
\ No newline at end of file
@@ -0,0 +1,79 @@
+import Stripe from "stripe";
+import dotenv from "dotenv";
+import { orderClient } from "../gRPC/orderClient.js";
+
+dotenv.config();
+
+const stripe = new Stripe(process.env.STRIPE_SECRET_KEY || process.env.APP_STRIPE_SECRET_KEY, {
+  apiVersion: "2022-11-15",
+});
+
+function getPendingRefundsAsync() {
+  return new Promise((resolve, reject) => {
+    orderClient.GetPendingRefunds({}, (err, res) => {
+      if (err) return reject(err);
+      resolve(res.refunds);
+    });
+  });
+}
+
+function setRefundStatusAsync(request) {
+  return new Promise((resolve, reject) => {
+    orderClient.SetRefundStatus(request, (err, res) => {
+      if (err) return reject(err);
+      resolve(res);
+    });
+  });
+}
+
+// refundOrder refunds the checkout session of the order and returns the refund id.
+// The idempotency key makes Stripe return the first refund if the order is refunded again.
+async function refundOrder(refund) {
+  const session = await stripe.checkout.sessions.retrieve(refund.transactionId);
+
+  const result = await stripe.refunds.create(
+    {
+      payment_intent: session.payment_intent,
+      amount: Number(refund.amount.amount),
+      metadata: { orderId: refund.orderId, reason: refund.reason },
+    },
+    { idempotencyKey: `refund_${refund.orderId}` }
+  );
+
+  return result.id;
+}
+
+// processRefunds sends the pending refunds of failed deliveries to Stripe.
+// Refunds rejected by Stripe are marked as failed. Other errors leave the refund pending so that it is retried.
+export const processRefunds = async () => {
+  const refunds = await getPendingRefundsAsync();
+
+  for (const refund of refunds) {
+    let status;
+    try {
+      const refundId = await refundOrder(refund);
+      status = { orderId: refund.orderId, success: true, refundId };
+    } catch (err) {
+      if (!(err instanceof Stripe.errors.StripeInvalidRequestError)) {
+        console.error(`Failed to refund order ${refund.orderId}:`, err);
+        continue;
+      }
+
+      console.error(`Refund for order ${refund.orderId} was rejected:`, err.message);
+      status = { orderId: refund.orderId, success: false, refundId: "" };
+    }
+
+    await setRefundStatusAsync(status);
+  }
+};
+
+// startRefunds processes the pending refunds every interval milliseconds.
+export const startRefunds = (interval) => {
+  const run = () => {
+    processRefunds()
+      .catch((err) => console.error("Failed to process refunds:", err))
+      .finally(() => setTimeout(run, interval));
+  };
+
+  run();
+};
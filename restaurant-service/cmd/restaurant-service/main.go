@@ -7,7 +7,10 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/config"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"go.uber.org/zap"
 )
 
@@ -17,6 +20,24 @@ func main() {
 
 	serverCtx := shared.AppContext()
 
+	shutdownTracing, err := tracing.Setup(serverCtx, "restaurant-service", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Failed to setup tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background()) //nolint: errcheck
+
+	serviceKey, err := config.LoadServiceKey()
+	if err != nil {
+		zap.L().Fatal("Failed to load service key", zap.Error(err))
+	}
+	serviceKeys, err := config.LoadServicePublicKeys()
+	if err != nil {
+		zap.L().Fatal("Failed to load service public keys", zap.Error(err))
+	}
+	if err := grpcauth.Setup("restaurant-service", serviceKey, serviceKeys); err != nil {
+		zap.L().Fatal("Failed to setup service authentication", zap.Error(err))
+	}
+
 	con, err := database.ConnectMongo(serverCtx, cfg.Database)
 	if err != nil {
 		zap.L().Panic("Failed to connect to the database", zap.Error(err))
@@ -24,6 +45,14 @@ func main() {
 	zap.L().Info("Connected to MongoDB successfully")
 	defer con.Disconnect(context.Background())
 
+	exit, err := migrate.Startup(serverCtx, con.Database("restaurant-service"), service.Migrations, cfg.Database.Migrate)
+	if err != nil {
+		zap.L().Fatal("Failed to apply migrations", zap.Error(err))
+	}
+	if exit {
+		return
+	}
+
 	s := service.New(cfg, con)
 
 	err = s.RegisterRoutes()
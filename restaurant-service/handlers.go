@@ -4,9 +4,12 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/handlers/grpc"
 	menuitem "github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/handlers/menuItem"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/handlers/restaurant"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/handlers/zone"
 	auth "github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/zones"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
 	middleware "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
 	"go.uber.org/zap"
 )
@@ -15,19 +18,21 @@ import (
 func (s *Server) RegisterRoutes() error {
 	restaurantRepo := repo.NewRestaurantRepo(s.db.Database("restaurant-service"))
 	menuItemRepo := repo.NewMenItemRepo(s.db.Database("restaurant-service"))
+	zoneRepo := repo.NewZoneRepo(s.db.Database("restaurant-service"))
 	authHandler := auth.NewAuth(restaurantRepo, menuItemRepo)
+	checker := zones.New(zoneRepo, s.cfg.Delivery)
 
 	auth := middleware.New()
 
 	{
-		handler, err := restaurant.New(restaurantRepo, zap.L(), s.cfg.Notify, s.cfg.Services.User)
+		handler, err := restaurant.New(restaurantRepo, checker, zap.L(), s.cfg.Notify, s.cfg.Services.User, s.cfg.Services.Clients)
 		if err != nil {
 			return err
 		}
 
 		group := s.app.Group("/restaurants/")
 
-		group.Post("/", handler.HandleCreateRestaurant, auth)
+		group.Post("/", handler.HandleCreateRestaurant, auth, s.idempotency.Middleware())
 		group.Get("/", handler.HandleGetAllRestaurants)
 		group.Get("/owner", handler.HandleGetRestaurantsByOwnerId, auth)
 		group.Get("/:restaurantId", handler.HandleGetRestaurantById)
@@ -57,7 +62,7 @@ func (s *Server) RegisterRoutes() error {
 		group.Get("/restaurant/:restaurantId", handler.HandleGetRestaurantMenuItems)
 		group.Get("/:menuItemId", handler.HandleGetMenuItemById)
 
-		group.Post("/", handler.HandleCreateMenuItem, auth)
+		group.Post("/", handler.HandleCreateMenuItem, auth, s.idempotency.Middleware())
 
 		ownerGroup := group.Group("/:menuItemId")
 		ownerGroup.Use(auth)
@@ -68,7 +73,23 @@ func (s *Server) RegisterRoutes() error {
 	}
 
 	{
-		proto.RegisterRestaurantServiceServer(s.grpc, grpc.New(restaurantRepo, menuItemRepo))
+		handler, err := zone.New(zoneRepo, zap.L())
+		if err != nil {
+			return err
+		}
+
+		group := s.app.Group("/zones/")
+		group.Use(auth)
+		group.Use(middleware.Role("user_admin"))
+		group.Get("/", handler.HandleGetZones)
+		group.Post("/", handler.HandleCreateZone)
+		group.Patch("/:zoneId", handler.HandleUpdateZone)
+		group.Delete("/:zoneId", handler.HandleDeleteZone)
+	}
+
+	{
+		proto.RegisterRestaurantServiceServer(s.grpc, grpc.New(restaurantRepo, menuItemRepo, checker))
+		health.RegisterServer(s.grpc)
 	}
 
 	return nil
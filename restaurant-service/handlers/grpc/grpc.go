@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/zones"
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -14,20 +17,29 @@ type GrpcHandler struct {
 	proto.UnimplementedRestaurantServiceServer
 	restaurantRepo repo.RestaurantRepo
 	menuItemRepo   repo.MenuItemRepo
+	zones          *zones.Checker
 }
 
 func (g *GrpcHandler) GetItemsById(ctx context.Context, idList *proto.ItemIdList) (*proto.ItemList, error) {
+	menuItems, err := g.menuItemRepo.GetMenuItemsByIds(ctx, idList.ItemId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Internal error in GetMenuItemsByIds")
+	}
+
+	itemData := make(map[bson.ObjectID]*models.MenuItem, len(menuItems))
+	for i := range menuItems {
+		itemData[menuItems[i].Id] = &menuItems[i]
+	}
+
+	// build the result in the same order as the requested ids
 	items := make([]*proto.Item, len(idList.ItemId))
 	for i, itemId := range idList.ItemId {
-		item, err := g.menuItemRepo.GetMenuItemById(ctx, itemId)
-
-		if err != nil {
-			if errors.Is(err, repo.ErrNoMenu) || errors.Is(err, repo.ErrInvalidId) {
-				// mark item id as invalid
-				items[i] = &proto.Item{ItemId: itemId, Invalid: true}
-				continue
-			}
-			return nil, status.Errorf(codes.Internal, "Internal error in GetMenuItemById")
+		objId, err := bson.ObjectIDFromHex(itemId)
+		item, ok := itemData[objId]
+		if err != nil || !ok {
+			// mark item id as invalid
+			items[i] = &proto.Item{ItemId: itemId, Invalid: true}
+			continue
 		}
 
 		items[i] = &proto.Item{
@@ -35,7 +47,9 @@ func (g *GrpcHandler) GetItemsById(ctx context.Context, idList *proto.ItemIdList
 			RestaurantId: item.RestaurantId.Hex(),
 			Name:         item.Name,
 			Description:  item.Description,
-			Price:        item.Price,
+			Price:        item.Price.Float(), //nolint: staticcheck
+			UnitPrice:    &proto.Money{Amount: item.Price.Amount, Currency: string(item.Price.Currency)},
+			PrepMinutes:  int32(item.PrepMinutes),
 		}
 	}
 
@@ -61,12 +75,37 @@ func (g *GrpcHandler) GetRestaurantById(ctx context.Context, restaurantId *proto
 		Location: &proto.Location{
 			Longitude: result.Address.Position.Coordinates[0],
 			Latitude:  result.Address.Position.Coordinates[1]},
+		PrepMinutes: int32(result.PrepMinutes),
 	}, nil
 }
 
-func New(restaurantRepo repo.RestaurantRepo, menuItemRepo repo.MenuItemRepo) *GrpcHandler {
+func (g *GrpcHandler) CheckDelivery(ctx context.Context, req *proto.DeliveryCheck) (*proto.DeliveryCheckResult, error) {
+	if req.Location == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Location is required")
+	}
+
+	result, err := g.restaurantRepo.GetRestaurantById(ctx, req.RestaurantId)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoRes) {
+			return nil, status.Errorf(codes.NotFound, "Restaurant does not exist")
+		} else if errors.Is(err, repo.ErrInvalidId) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid Restaurant ID")
+		}
+		return nil, status.Errorf(codes.Internal, "Internal error in CheckDelivery")
+	}
+
+	deliverable, err := g.zones.CanDeliver(ctx, result, req.Location.Latitude, req.Location.Longitude)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Internal error in CheckDelivery")
+	}
+
+	return &proto.DeliveryCheckResult{Deliverable: deliverable}, nil
+}
+
+func New(restaurantRepo repo.RestaurantRepo, menuItemRepo repo.MenuItemRepo, checker *zones.Checker) *GrpcHandler {
 	return &GrpcHandler{
 		restaurantRepo: restaurantRepo,
 		menuItemRepo:   menuItemRepo,
+		zones:          checker,
 	}
 }
@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/proto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// createItems creates n menu items and returns their ids.
+func createItems(tb testing.TB, menu repo.MenuItemRepo, n int) []string {
+	tb.Helper()
+
+	restaurantId := bson.NewObjectID()
+	ids := make([]string, n)
+	for i := range ids {
+		id, err := menu.CreateMenuItem(context.TODO(), &models.MenuItem{
+			RestaurantId: restaurantId,
+			Name:         fmt.Sprintf("Item %d", i),
+			Price:        money.New(int64(10000+i), money.LKR),
+		})
+		if err != nil {
+			tb.Fatalf("failed to create menu item: %s", err)
+		}
+		ids[i] = id
+	}
+
+	return ids
+}
+
+func TestGetItemsById(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	menu := repo.NewMenItemRepo(db)
+	handler := New(repo.NewRestaurantRepo(db), menu, nil)
+
+	ids := createItems(t, menu, 3)
+	request := []string{ids[2], "invalid-id", ids[0], bson.NewObjectID().Hex(), ids[1], ids[0]}
+
+	res, err := handler.GetItemsById(context.TODO(), &proto.ItemIdList{ItemId: request})
+	if err != nil {
+		t.Fatalf("GetItemsById failed: %s", err)
+	}
+
+	if len(res.Item) != len(request) {
+		t.Fatalf("expected %d items got %d", len(request), len(res.Item))
+	}
+
+	for i, item := range res.Item {
+		if item.ItemId != request[i] {
+			t.Errorf("item %d: expected id %s got %s", i, request[i], item.ItemId)
+		}
+	}
+
+	for _, i := range []int{1, 3} {
+		if !res.Item[i].Invalid {
+			t.Errorf("item %d should be marked as invalid", i)
+		}
+	}
+
+	for _, i := range []int{0, 2, 4, 5} {
+		if res.Item[i].Invalid || len(res.Item[i].Name) == 0 {
+			t.Errorf("item %d should contain the menu item data", i)
+		}
+	}
+}
+
+func BenchmarkGetItemsById(b *testing.B) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	menu := repo.NewMenItemRepo(db)
+	handler := New(repo.NewRestaurantRepo(db), menu, nil)
+
+	ids := createItems(b, menu, 500)
+
+	for _, size := range []int{1, 10, 100, 500} {
+		request := &proto.ItemIdList{ItemId: ids[:size]}
+
+		b.Run(fmt.Sprintf("items=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				_, err := handler.GetItemsById(context.TODO(), request)
+				if err != nil {
+					b.Fatalf("GetItemsById failed: %s", err)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
@@ -52,9 +54,20 @@ func New(db repo.MenuItemRepo, logger *zap.Logger) (*Handler, error) {
 	return menuitem, nil
 }
 
-// HandleGetAllMenuItems retrieves all menu items from the database and returns them as a JSON response.
+// menuItemPages contains the sort fields supported by the menu item list.
+var menuItemPages = pagination.Options{
+	Sort:      map[string]string{"name": "name", "price": "price.amount", "created_at": "created_at"},
+	DateField: "created_at",
+}
+
+// HandleGetAllMenuItems retrieves a page of menu items from the database and returns them as a JSON response.
 func (h *Handler) HandleGetAllMenuItems(c fiber.Ctx) error {
-	menuItems, err := h.db.GetAllMenuItems(c.RequestCtx())
+	page, err := pagination.FromQuery(c, menuItemPages)
+	if err != nil {
+		return err
+	}
+
+	menuItems, next, err := h.db.GetAllMenuItems(c.Context(), page)
 	if err != nil {
 		h.logger.Error("Failed to get all menu items", zap.Error(err))
 		// Return generic internal error for unexpected DB errors
@@ -66,7 +79,7 @@ func (h *Handler) HandleGetAllMenuItems(c fiber.Ctx) error {
 		menuItems = []models.MenuItem{}
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: menuItems})
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(menuItems, next))
 }
 
 // HandleGetRestaurantMenuItems retrieves menu items for a specific restaurant by restaurant ID.
@@ -77,7 +90,7 @@ func (h *Handler) HandleGetRestaurantMenuItems(c fiber.Ctx) error {
 		return ErrInvalidRestaurantId
 	}
 
-	menuItems, err := h.db.GetRestaurantMenuItems(c.RequestCtx(), restaurantId)
+	menuItems, err := h.db.GetRestaurantMenuItems(c.Context(), restaurantId)
 
 	if err != nil {
 		// Map known repository errors to API errors
@@ -105,7 +118,7 @@ func (h *Handler) HandleGetMenuItemById(c fiber.Ctx) error {
 		return ErrInvalidRestaurantId
 	}
 
-	menuItem, err := h.db.GetMenuItemById(c.RequestCtx(), menuItemId)
+	menuItem, err := h.db.GetMenuItemById(c.Context(), menuItemId)
 
 	if err != nil {
 		// Map known repository errors to API errors
@@ -145,7 +158,7 @@ func (h *Handler) HandleCreateMenuItem(c fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
-	menuItemId, err := h.db.CreateMenuItem(c.RequestCtx(), menuitem)
+	menuItemId, err := h.db.CreateMenuItem(c.Context(), menuitem)
 	if err != nil {
 		// Handle potential DB errors (e.g., duplicate registration number if unique index exists)
 		h.logger.Error("Failed to create Menu Item in DB", zap.Error(err))
@@ -177,7 +190,7 @@ func (h *Handler) HandleUpdateMenuItemById(c fiber.Ctx) error {
 	}
 
 	// Pass the pointer to the update struct to the (assumed modified) repo function
-	updatedMenuItem, err := h.db.UpdateMenuItemById(c.RequestCtx(), menuItemId, req)
+	updatedMenuItem, err := h.db.UpdateMenuItemById(c.Context(), menuItemId, req)
 
 	if err != nil {
 		fmt.Println(err)
@@ -211,7 +224,7 @@ func (h *Handler) HandleUpdateMenuItemImageById(c fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
 	}
 
-	updatedMenuItem, err := h.db.UpdateMenuItemImageById(c.RequestCtx(), menuItemId, req.ImgURL)
+	updatedMenuItem, err := h.db.UpdateMenuItemImageById(c.Context(), menuItemId, req.ImgURL)
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
 			return apiErr
@@ -231,7 +244,7 @@ func (h *Handler) HandleDeleteMenuItemById(c fiber.Ctx) error {
 		return ErrInvalidMenuItemId
 	}
 
-	err := h.db.DeleteMenuItemById(c.RequestCtx(), menuItemId)
+	err := h.db.DeleteMenuItemById(c.Context(), menuItemId)
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
 			return apiErr
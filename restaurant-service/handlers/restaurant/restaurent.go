@@ -9,13 +9,16 @@ import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/zones"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/notify"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 // --- API Error Definitions ---
@@ -29,6 +32,9 @@ var ErrInvalidRestaurantId = fiber.NewError(fiber.StatusBadRequest, "Restaurant
 // ErrBadRequest is returned for general validation errors or malformed requests.
 var ErrBadRequest = fiber.NewError(fiber.StatusBadRequest, "Bad request")
 
+// ErrInvalidLocation is returned when the delivery location is not a valid latitude and longitude.
+var ErrInvalidLocation = fiber.NewError(fiber.StatusBadRequest, "Delivery location must be in the format latitude,longitude")
+
 // InternalServerError is a generic response for unexpected errors.
 var InternalServerError = models.ErrorResponse{Ok: false, Error: "Internal server error"}
 
@@ -46,17 +52,18 @@ type Handler struct {
 	logger   *zap.Logger
 	notify   notify.Notify
 	user     proto.UserServiceClient
+	zones    *zones.Checker
 }
 
 // New create a new Restaurant Handler
-func New(db repo.RestaurantRepo, logger *zap.Logger, notifyCfg notify.Config, userService string) (*Handler, error) {
-	restaurant := &Handler{db: db, validate: validate.New(), logger: logger}
+func New(db repo.RestaurantRepo, checker *zones.Checker, logger *zap.Logger, notifyCfg notify.Config, userService string, clients grpcclient.Config) (*Handler, error) {
+	restaurant := &Handler{db: db, zones: checker, validate: validate.New(), logger: logger}
 	err := restaurant.notify.Connect(context.TODO(), notifyCfg)
 	if err != nil {
 		return nil, err
 	}
 
-	con, err := grpc.NewClient(userService, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	con, err := grpcclient.Dial("user-service", userService, clients, "/UserService/GetUserBy")
 	if err != nil {
 		return nil, fmt.Errorf("Failed to connect to order service: %w", err)
 	}
@@ -66,7 +73,14 @@ func New(db repo.RestaurantRepo, logger *zap.Logger, notifyCfg notify.Config, us
 	return restaurant, nil
 }
 
-// HandleGetAllRestaurants handles sending a list of all non-deleted restaurants.
+// restaurantPages contains the sort fields supported by the restaurant lists.
+var restaurantPages = pagination.Options{
+	Sort:      map[string]string{"name": "name", "created_at": "created_at"},
+	DateField: "created_at",
+}
+
+// HandleGetAllRestaurants handles sending a page of all non-deleted restaurants.
+// If deliverable_to is set, only restaurants that can deliver to the location are returned.
 func (h *Handler) HandleGetAllRestaurants(c fiber.Ctx) error {
 	approve := c.Query("approve", "all")
 	var filter repo.RestaurantFilter
@@ -81,7 +95,31 @@ func (h *Handler) HandleGetAllRestaurants(c fiber.Ctx) error {
 		return ErrBadRequest
 	}
 
-	restaurants, err := h.db.GetAllRestaurant(c.RequestCtx(), filter)
+	page, err := pagination.FromQuery(c, restaurantPages)
+	if err != nil {
+		return err
+	}
+
+	var keep func(*models.Restaurant) bool
+	if deliverTo := c.Query("deliverable_to"); deliverTo != "" {
+		lat, lng, err := location.ParseLatLng(deliverTo)
+		if err != nil {
+			return ErrInvalidLocation
+		}
+
+		var ok bool
+		keep, ok, err = h.zones.Deliverable(c.Context(), lat, lng)
+		if err != nil {
+			h.logger.Error("Failed to check the delivery location", zap.Error(err))
+			return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
+		}
+		if !ok {
+			// the location is outside the delivery zones
+			return c.Status(fiber.StatusOK).JSON(dto.Paged([]models.Restaurant{}, ""))
+		}
+	}
+
+	restaurants, next, err := h.db.GetAllRestaurant(c.Context(), filter, page, keep)
 	if err != nil {
 		h.logger.Error("Failed to get all restaurants", zap.Error(err))
 		// Return generic internal error for unexpected DB errors
@@ -93,12 +131,16 @@ func (h *Handler) HandleGetAllRestaurants(c fiber.Ctx) error {
 		restaurants = []models.Restaurant{}
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: restaurants})
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(restaurants, next))
 }
 
 func (h *Handler) HandleGetAllApprovedRestaurants(c fiber.Ctx) error {
+	page, err := pagination.FromQuery(c, restaurantPages)
+	if err != nil {
+		return err
+	}
 
-	restaurants, err := h.db.GetAllRestaurant(c.RequestCtx(), repo.RestaurantFilterApprove)
+	restaurants, next, err := h.db.GetAllRestaurant(c.Context(), repo.RestaurantFilterApprove, page, nil)
 	if err != nil {
 		h.logger.Error("Failed to get all restaurants", zap.Error(err))
 		// Return generic internal error for unexpected DB errors
@@ -110,7 +152,7 @@ func (h *Handler) HandleGetAllApprovedRestaurants(c fiber.Ctx) error {
 		restaurants = []models.Restaurant{}
 	}
 
-	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: restaurants})
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(restaurants, next))
 }
 
 // HandleGetRestaurantById handles getting a single restaurant by its ID.
@@ -121,7 +163,7 @@ func (h *Handler) HandleGetRestaurantById(c fiber.Ctx) error {
 		return ErrInvalidRestaurantId
 	}
 
-	restaurant, err := h.db.GetRestaurantById(c.RequestCtx(), restaurantId)
+	restaurant, err := h.db.GetRestaurantById(c.Context(), restaurantId)
 	if err != nil {
 		// Map known repository errors to API errors
 		if apiErr, ok := errorMap[err]; ok {
@@ -143,7 +185,7 @@ func (h *Handler) HandleGetRestaurantLogoById(c fiber.Ctx) error {
 		return ErrInvalidRestaurantId
 	}
 
-	restaurant, err := h.db.GetRestaurantById(c.RequestCtx(), restaurantId)
+	restaurant, err := h.db.GetRestaurantById(c.Context(), restaurantId)
 	if err != nil {
 		// Map known repository errors to API errors
 		if apiErr, ok := errorMap[err]; ok {
@@ -189,7 +231,7 @@ func (h *Handler) HandleCreateRestaurant(c fiber.Ctx) error {
 	}
 
 	// Pass the pointer to the repository function
-	restaurantId, err := h.db.CreateRestaurant(c.RequestCtx(), restaurant)
+	restaurantId, err := h.db.CreateRestaurant(c.Context(), restaurant)
 	if err != nil {
 		h.logger.Error("Failed to create restaurant in DB", zap.Error(err))
 		// TODO: Check for specific DB errors like duplicate keys if needed
@@ -224,7 +266,7 @@ func (h *Handler) HandleUpdateRestaurant(c fiber.Ctx) error {
 	}
 
 	// Pass the pointer to the update struct to the (assumed modified) repo function
-	updatedRestaurant, err := h.db.UpdateRestaurantById(c.RequestCtx(), restaurantId, req)
+	updatedRestaurant, err := h.db.UpdateRestaurantById(c.Context(), restaurantId, req)
 
 	if err != nil {
 		fmt.Println(err)
@@ -259,7 +301,7 @@ func (h *Handler) HandleUpdateLogoById(c fiber.Ctx) error {
 	}
 
 	// Call the specific repo function
-	updatedRestaurant, err := h.db.UpdateLogoById(c.RequestCtx(), restaurantId, req.LogoURL)
+	updatedRestaurant, err := h.db.UpdateLogoById(c.Context(), restaurantId, req.LogoURL)
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
 			return apiErr
@@ -292,7 +334,7 @@ func (h *Handler) HandleUpdateCoverById(c fiber.Ctx) error {
 	}
 
 	// Call the specific repo function
-	updatedRestaurant, err := h.db.UpdateCoverById(c.RequestCtx(), restaurantId, req.CoverURL)
+	updatedRestaurant, err := h.db.UpdateCoverById(c.Context(), restaurantId, req.CoverURL)
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
 			return apiErr
@@ -312,7 +354,7 @@ func (h *Handler) HandleDeleteRestaurantById(c fiber.Ctx) error {
 		return ErrInvalidRestaurantId
 	}
 
-	err := h.db.DeleteRestaurantById(c.RequestCtx(), restaurantId)
+	err := h.db.DeleteRestaurantById(c.Context(), restaurantId)
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
 			return apiErr
@@ -342,7 +384,7 @@ func (h *Handler) ApproveRestaurantById(c fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
-	err := h.db.ApproveRestaurantById(c.RequestCtx(), restaurantId, req.Approved)
+	err := h.db.ApproveRestaurantById(c.Context(), restaurantId, req.Approved)
 
 	if err != nil {
 		if apiErr, ok := errorMap[err]; ok {
@@ -352,13 +394,13 @@ func (h *Handler) ApproveRestaurantById(c fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
 	}
 
-	restaurant, err := h.db.GetRestaurantById(c.RequestCtx(), restaurantId)
+	restaurant, err := h.db.GetRestaurantById(c.Context(), restaurantId)
 	if err != nil {
 		h.logger.Error("Failed to get restaurant", zap.String("restaurantId", restaurantId), zap.Error(err))
 		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
 	}
 
-	h.sendMessage(c.RequestCtx(), restaurant.Owner.Hex(), &notify.TemplateMessage{
+	h.sendMessage(c.Context(), restaurant.Owner.Hex(), &notify.TemplateMessage{
 		Type:     notify.MsgTypeEmail,
 		Template: "restaurant-approved-email",
 		Content: map[string]any{
@@ -375,7 +417,7 @@ func (h *Handler) ApproveRestaurantById(c fiber.Ctx) error {
 
 func (h *Handler) HandleGetRestaurantsByOwnerId(c fiber.Ctx) error {
 	ownerId := middleware.GetUser(c).UserId
-	restaurants, err := h.db.GetRestaurantsByOwnerId(c.RequestCtx(), ownerId)
+	restaurants, err := h.db.GetRestaurantsByOwnerId(c.Context(), ownerId)
 	if err != nil {
 		h.logger.Error("Failed to get all restaurants of owner", zap.Error(err))
 		// Return generic internal error for unexpected DB errors
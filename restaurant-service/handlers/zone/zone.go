@@ -0,0 +1,123 @@
+package zone
+
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// --- API Error Definitions ---
+
+// ErrZoneNotFound is returned if the delivery zone for the given operation is not found.
+var ErrZoneNotFound = fiber.NewError(fiber.StatusNotFound, "Delivery zone with the given id was not found")
+
+// ErrInvalidZoneId is returned when the delivery zone id is missing or invalid.
+var ErrInvalidZoneId = fiber.NewError(fiber.StatusBadRequest, "Delivery zone id is not specified or is invalid")
+
+// InternalServerError is a generic response for unexpected errors.
+var InternalServerError = models.ErrorResponse{Ok: false, Error: "Internal server error"}
+
+// --- Error Mapping ---
+
+// Maps errors returned by ZoneRepo to API errors.
+var errorMap = map[error]error{
+	repo.ErrInvalidId: ErrInvalidZoneId,
+	repo.ErrNoZone:    ErrZoneNotFound,
+}
+
+type Handler struct {
+	db       repo.ZoneRepo
+	validate *validate.Validator
+	logger   *zap.Logger
+}
+
+// New create a new Delivery Zone Handler
+func New(db repo.ZoneRepo, logger *zap.Logger) (*Handler, error) {
+	return &Handler{db: db, validate: validate.New(), logger: logger}, nil
+}
+
+// HandleGetZones handles sending a list of all delivery zones.
+func (h *Handler) HandleGetZones(c fiber.Ctx) error {
+	zones, err := h.db.GetZones(c.Context(), fiber.Query[bool](c, "active"))
+	if err != nil {
+		h.logger.Error("Failed to get delivery zones", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: zones})
+}
+
+// HandleCreateZone handles creating a new delivery zone.
+func (h *Handler) HandleCreateZone(c fiber.Ctx) error {
+	var req models.DeliveryZoneCreate
+
+	if err := c.Bind().Body(&req); err != nil {
+		h.logger.Warn("Failed to bind request body for create delivery zone", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := h.validate.Validate(req); err != nil {
+		h.logger.Warn("Validation failed for create delivery zone", zap.Error(err))
+		return err
+	}
+
+	zoneId, err := h.db.CreateZone(c.Context(), req.ToDeliveryZone())
+	if err != nil {
+		h.logger.Error("Failed to create delivery zone in DB", zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(models.Response{Ok: true, Data: fiber.Map{"zoneId": zoneId}})
+}
+
+// HandleUpdateZone handles updating an existing delivery zone.
+func (h *Handler) HandleUpdateZone(c fiber.Ctx) error {
+	zoneId := c.Params("zoneId")
+	if len(zoneId) == 0 {
+		return ErrInvalidZoneId
+	}
+
+	var req models.DeliveryZoneUpdate
+
+	if err := c.Bind().Body(&req); err != nil {
+		h.logger.Warn("Failed to bind request body for update delivery zone", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+
+	if err := h.validate.Validate(req); err != nil {
+		h.logger.Warn("Validation failed for update delivery zone", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Validation failed: "+err.Error())
+	}
+
+	zone, err := h.db.UpdateZoneById(c.Context(), zoneId, &req)
+	if err != nil {
+		if apiErr, ok := errorMap[err]; ok {
+			return apiErr
+		}
+		h.logger.Error("Failed to update delivery zone", zap.String("zoneId", zoneId), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(models.Response{Ok: true, Data: zone})
+}
+
+// HandleDeleteZone handles deleting a delivery zone.
+func (h *Handler) HandleDeleteZone(c fiber.Ctx) error {
+	zoneId := c.Params("zoneId")
+	if len(zoneId) == 0 {
+		return ErrInvalidZoneId
+	}
+
+	err := h.db.DeleteZoneById(c.Context(), zoneId)
+	if err != nil {
+		if apiErr, ok := errorMap[err]; ok {
+			return apiErr
+		}
+		h.logger.Error("Failed to delete delivery zone", zap.String("zoneId", zoneId), zap.Error(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(InternalServerError)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
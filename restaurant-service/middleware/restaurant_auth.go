@@ -13,7 +13,7 @@ type RestaurantAuth struct {
 }
 
 func (r *RestaurantAuth) RestaurantPermissionFunc(c fiber.Ctx, token middleware.TokenClaims) bool {
-	result, err := r.restaurantRepo.GetRestaurantById(c.RequestCtx(), c.Params("restaurantId"))
+	result, err := r.restaurantRepo.GetRestaurantById(c.Context(), c.Params("restaurantId"))
 
 	if err != nil {
 		zap.L().Error("Failed to get restaurant ", zap.Error(err))
@@ -24,13 +24,13 @@ func (r *RestaurantAuth) RestaurantPermissionFunc(c fiber.Ctx, token middleware.
 }
 
 func (r *RestaurantAuth) MenuPermissionFunc(c fiber.Ctx, token middleware.TokenClaims) bool {
-	menuItem, err := r.menuItemRepo.GetMenuItemById(c.RequestCtx(), c.Params("menuItemId"))
+	menuItem, err := r.menuItemRepo.GetMenuItemById(c.Context(), c.Params("menuItemId"))
 	if err != nil {
 		zap.L().Error("Failed to get menu item ", zap.Error(err))
 		return false
 	}
 
-	restaurant, err := r.restaurantRepo.GetRestaurantById(c.RequestCtx(), menuItem.RestaurantId.Hex())
+	restaurant, err := r.restaurantRepo.GetRestaurantById(c.Context(), menuItem.RestaurantId.Hex())
 	if err != nil {
 		zap.L().Error("Failed to get restaurant ", zap.Error(err))
 		return false
@@ -0,0 +1,25 @@
+package restaurantservice
+
+import "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+
+// Migrations contains the database migrations of the service.
+// New migrations must be added at the end with a higher version.
+var Migrations = []migrate.Migration{
+	{
+		Version:     1,
+		Description: "Add restaurant and menu item indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("restaurant",
+				migrate.Index("owner"),
+				migrate.Index("approved", "name"),
+				migrate.Index("name"),
+				migrate.Index("created_at"),
+			),
+			migrate.CreateIndexes("menu_items",
+				migrate.Index("restaurant_id"),
+				migrate.Index("name"),
+				migrate.Index("created_at"),
+			),
+		),
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"go.mongodb.org/mongo-driver/v2/bson"
 )
 
@@ -12,26 +13,30 @@ type MenuItem struct {
 	RestaurantId bson.ObjectID `json:"restaurant_id" bson:"restaurant_id"`
 	Name         string        `json:"name" bson:"name"`
 	Description  string        `json:"description" bson:"description"`
-	Price        float64       `json:"price" bson:"price"`
+	Price        money.Money   `json:"price" bson:"price"`
 	Image        string        `json:"image" bson:"image"`
-	CreatedAt    time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at" bson:"updated_at"`
-	DeletedAt    *time.Time    `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// PrepMinutes is the time needed to prepare the item. 0 uses the restaurant preparation time.
+	PrepMinutes int        `json:"prep_minutes" bson:"prep_minutes"`
+	CreatedAt   time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" bson:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 type MenuItemUpdate struct {
-	Name        string  `json:"name" validate:"omitempty,min=2,max=100" bson:"name"`
-	Description string  `json:"description" validate:"omitempty,max=500" bson:"description"`
-	Price       float64 `json:"price" validate:"omitempty" bson:"price"`
-	Image       string  `json:"image" validate:"omitempty,filepath" bson:"image,omitempty"`
+	Name        string      `json:"name" validate:"omitempty,min=2,max=100" bson:"name"`
+	Description string      `json:"description" validate:"omitempty,max=500" bson:"description"`
+	Price       money.Money `json:"price" validate:"omitempty" bson:"price"`
+	Image       string      `json:"image" validate:"omitempty,filepath" bson:"image,omitempty"`
+	PrepMinutes *int        `json:"prep_minutes" validate:"omitempty,min=0,max=240" bson:"prep_minutes,omitempty"`
 }
 
 type MenuItemCreate struct {
-	RestaurantId string  `json:"restaurant_id" bson:"restaurant_id"`
-	Name         string  `json:"name" bson:"name" validate:"min=4,max=100"`
-	Description  string  `json:"description" validate:"max=500" bson:"description"`
-	Price        float64 `json:"price" bson:"price" validate:"min=1,max=100000"`
-	Image        string  `json:"image" validate:"filepath" bson:"image"`
+	RestaurantId string      `json:"restaurant_id" bson:"restaurant_id"`
+	Name         string      `json:"name" bson:"name" validate:"min=4,max=100"`
+	Description  string      `json:"description" validate:"max=500" bson:"description"`
+	Price        money.Money `json:"price" bson:"price" validate:"min=1,max=100000"`
+	Image        string      `json:"image" validate:"filepath" bson:"image"`
+	PrepMinutes  int         `json:"prep_minutes" validate:"min=0,max=240" bson:"prep_minutes"`
 }
 
 func (mc *MenuItemCreate) ToMenuItem() (*MenuItem, error) {
@@ -46,6 +51,7 @@ func (mc *MenuItemCreate) ToMenuItem() (*MenuItem, error) {
 		Description:  mc.Description,
 		Price:        mc.Price,
 		Image:        mc.Image,
+		PrepMinutes:  mc.PrepMinutes,
 	}
 
 	return menuItem, nil
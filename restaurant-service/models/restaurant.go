@@ -23,10 +23,14 @@ type Restaurant struct {
 	Description    string        `json:"description" bson:"description"`
 	Tags           []string      `json:"tags" bson:"tags"`
 	OperatingTime  OperatingTime `json:"operation_time" bson:"operation_time"`
-	Approved       bool          `json:"approved" bson:"approved"`
-	CreatedAt      time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt      time.Time     `json:"updated_at" bson:"updated_at"`
-	DeletedAt      *time.Time    `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// PrepMinutes is the usual time needed to prepare an order. 0 uses the default preparation time.
+	PrepMinutes int `json:"prep_minutes" bson:"prep_minutes"`
+	// MaxDeliveryKm is the maximum delivery distance. 0 uses the default maximum delivery distance.
+	MaxDeliveryKm float64    `json:"max_delivery_km" bson:"max_delivery_km"`
+	Approved      bool       `json:"approved" bson:"approved"`
+	CreatedAt     time.Time  `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 }
 
 type RestaurantUpdate struct {
@@ -37,6 +41,8 @@ type RestaurantUpdate struct {
 	Logo          string          `json:"logo" validate:"omitempty,filepath" bson:"logo,omitempty"`
 	Cover         string          `json:"cover" validate:"omitempty,filepath" bson:"cover,omitempty"`
 	OperatingTime *OperatingTime  `json:"operation_time" bson:"operation_time,omitempty"`
+	PrepMinutes   *int            `json:"prep_minutes" validate:"omitempty,min=0,max=240" bson:"prep_minutes,omitempty"`
+	MaxDeliveryKm *float64        `json:"max_delivery_km" validate:"omitempty,min=0,max=100" bson:"max_delivery_km,omitempty"`
 }
 
 type RestaurantCreate struct {
@@ -47,6 +53,8 @@ type RestaurantCreate struct {
 	Logo           string         `json:"logo" validate:"filepath" bson:"logo"`
 	Cover          string         `json:"cover" validate:"filepath" bson:"cover"`
 	OperatingTime  OperatingTime  `json:"operation_time" bson:"operation_time"`
+	PrepMinutes    int            `json:"prep_minutes" validate:"min=0,max=240" bson:"prep_minutes"`
+	MaxDeliveryKm  float64        `json:"max_delivery_km" validate:"min=0,max=100" bson:"max_delivery_km"`
 	RegistrationNo string         `json:"registration_no" validate:"required"`
 	OwnerID        string         `json:"-"`
 }
@@ -67,6 +75,8 @@ func (rc *RestaurantCreate) ToRestaurant(ownerId string) (*Restaurant, error) {
 		Logo:           rc.Logo,
 		Cover:          rc.Cover,
 		OperatingTime:  rc.OperatingTime,
+		PrepMinutes:    rc.PrepMinutes,
+		MaxDeliveryKm:  rc.MaxDeliveryKm,
 		Approved:       false,
 	}
 
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// Polygon is a GeoJSON polygon. Positions are [longitude, latitude].
+// The first ring is the outer boundary and the other rings are holes.
+type Polygon struct {
+	Type        string         `json:"type" bson:"type" validate:"eq=Polygon"`
+	Coordinates [][][2]float64 `json:"coordinates" bson:"coordinates" validate:"min=1,dive,min=4"`
+}
+
+// Contains checks if the coordinate is inside the polygon.
+func (p *Polygon) Contains(lat, lng float64) bool {
+	return location.PointInPolygon(lat, lng, p.Coordinates)
+}
+
+// DeliveryZone is an area where drivers deliver orders.
+type DeliveryZone struct {
+	Id        bson.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name      string        `json:"name" bson:"name"`
+	Area      Polygon       `json:"area" bson:"area"`
+	Active    bool          `json:"active" bson:"active"`
+	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
+}
+
+type DeliveryZoneCreate struct {
+	Name   string  `json:"name" validate:"min=2,max=100"`
+	Area   Polygon `json:"area"`
+	Active bool    `json:"active"`
+}
+
+func (zc *DeliveryZoneCreate) ToDeliveryZone() *DeliveryZone {
+	return &DeliveryZone{Name: zc.Name, Area: zc.Area, Active: zc.Active}
+}
+
+type DeliveryZoneUpdate struct {
+	Name   string   `json:"name" validate:"omitempty,min=2,max=100" bson:"name,omitempty"`
+	Area   *Polygon `json:"area" validate:"omitempty" bson:"area,omitempty"`
+	Active *bool    `json:"active" bson:"active,omitempty"`
+}
+
+func (z *DeliveryZone) MarshalBSON() ([]byte, error) {
+	if z.CreatedAt.IsZero() {
+		z.CreatedAt = time.Now()
+	}
+	z.UpdatedAt = time.Now()
+
+	type t DeliveryZone
+	return bson.Marshal((*t)(z))
+}
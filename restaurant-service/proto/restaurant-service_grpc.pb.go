@@ -20,6 +20,8 @@ type RestaurantServiceClient interface {
 	// Gets the items
 	GetItemsById(ctx context.Context, in *ItemIdList, opts ...grpc.CallOption) (*ItemList, error)
 	GetRestaurantById(ctx context.Context, in *RestaurantId, opts ...grpc.CallOption) (*Restaurant, error)
+	// Checks if the restaurant can deliver to the location
+	CheckDelivery(ctx context.Context, in *DeliveryCheck, opts ...grpc.CallOption) (*DeliveryCheckResult, error)
 }
 
 type restaurantServiceClient struct {
@@ -48,6 +50,15 @@ func (c *restaurantServiceClient) GetRestaurantById(ctx context.Context, in *Res
 	return out, nil
 }
 
+func (c *restaurantServiceClient) CheckDelivery(ctx context.Context, in *DeliveryCheck, opts ...grpc.CallOption) (*DeliveryCheckResult, error) {
+	out := new(DeliveryCheckResult)
+	err := c.cc.Invoke(ctx, "/RestaurantService/CheckDelivery", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RestaurantServiceServer is the server API for RestaurantService service.
 // All implementations must embed UnimplementedRestaurantServiceServer
 // for forward compatibility
@@ -55,6 +66,8 @@ type RestaurantServiceServer interface {
 	// Gets the items
 	GetItemsById(context.Context, *ItemIdList) (*ItemList, error)
 	GetRestaurantById(context.Context, *RestaurantId) (*Restaurant, error)
+	// Checks if the restaurant can deliver to the location
+	CheckDelivery(context.Context, *DeliveryCheck) (*DeliveryCheckResult, error)
 	mustEmbedUnimplementedRestaurantServiceServer()
 }
 
@@ -68,6 +81,9 @@ func (UnimplementedRestaurantServiceServer) GetItemsById(context.Context, *ItemI
 func (UnimplementedRestaurantServiceServer) GetRestaurantById(context.Context, *RestaurantId) (*Restaurant, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetRestaurantById not implemented")
 }
+func (UnimplementedRestaurantServiceServer) CheckDelivery(context.Context, *DeliveryCheck) (*DeliveryCheckResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckDelivery not implemented")
+}
 func (UnimplementedRestaurantServiceServer) mustEmbedUnimplementedRestaurantServiceServer() {}
 
 // UnsafeRestaurantServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -117,6 +133,24 @@ func _RestaurantService_GetRestaurantById_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RestaurantService_CheckDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeliveryCheck)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RestaurantServiceServer).CheckDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/RestaurantService/CheckDelivery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RestaurantServiceServer).CheckDelivery(ctx, req.(*DeliveryCheck))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RestaurantService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "RestaurantService",
 	HandlerType: (*RestaurantServiceServer)(nil),
@@ -129,6 +163,10 @@ var _RestaurantService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetRestaurantById",
 			Handler:    _RestaurantService_GetRestaurantById_Handler,
 		},
+		{
+			MethodName: "CheckDelivery",
+			Handler:    _RestaurantService_CheckDelivery_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "restaurant-service.proto",
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -14,12 +15,16 @@ import (
 var ErrNoMenu = errors.New("restaurant not found")
 
 type MenuItemRepo interface {
-	// GetAllMenuItems retrieves all menu items from the database.
-	GetAllMenuItems(ctx context.Context) ([]models.MenuItem, error)
+	// GetAllMenuItems retrieves a page of menu items from the database.
+	// The returned cursor is empty if there are no more pages.
+	GetAllMenuItems(ctx context.Context, p pagination.Params) ([]models.MenuItem, string, error)
 	// GetRestaurantMenuItems retrieves all menu items for a specific restaurant by its ID.
 	GetRestaurantMenuItems(ctx context.Context, restaurantId string) ([]models.MenuItem, error)
 	// GetMenuItemById retrieves a menu item by its unique ID.
 	GetMenuItemById(ctx context.Context, id string) (*models.MenuItem, error)
+	// GetMenuItemsByIds retrieves all menu items with the given IDs using a single query.
+	// Ids that are invalid or do not belong to an existing menu item are not included in the result.
+	GetMenuItemsByIds(ctx context.Context, ids []string) ([]models.MenuItem, error)
 	// CreateMenuItem creates a new menu item and returns the ID of the created item.
 	CreateMenuItem(ctx context.Context, menuItem *models.MenuItem) (string, error)
 	// UpdateMenuItemById updates an existing menu item by its ID and returns the updated item.
@@ -77,23 +82,8 @@ func (m *menuItemRepo) DeleteMenuItemById(ctx context.Context, id string) error
 }
 
 // GetAllMenuItems implements MenuItemRepo.
-func (m *menuItemRepo) GetAllMenuItems(ctx context.Context) ([]models.MenuItem, error) {
-	cursor, err := m.collection.Find(ctx, bson.D{{Key: "deleted_at", Value: nil}})
-	if err != nil {
-		return nil, err
-	}
-
-	var menuItems []models.MenuItem
-	err = cursor.All(ctx, &menuItems)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(menuItems) == 0 {
-		return []models.MenuItem{}, nil
-	}
-
-	return menuItems, nil
+func (m *menuItemRepo) GetAllMenuItems(ctx context.Context, p pagination.Params) ([]models.MenuItem, string, error) {
+	return pagination.Find[models.MenuItem](ctx, m.collection, bson.D{{Key: "deleted_at", Value: nil}}, p)
 }
 
 // GetMenuItemById implements MenuItemRepo.
@@ -116,6 +106,41 @@ func (m *menuItemRepo) GetMenuItemById(ctx context.Context, id string) (*models.
 	return &menuItem, nil
 }
 
+// GetMenuItemsByIds implements MenuItemRepo.
+func (m *menuItemRepo) GetMenuItemsByIds(ctx context.Context, ids []string) ([]models.MenuItem, error) {
+	// Parse the IDs into ObjectIDs, skipping any invalid ids
+	objIds := make([]bson.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objId, err := bson.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objIds = append(objIds, objId)
+	}
+
+	if len(objIds) == 0 {
+		return []models.MenuItem{}, nil
+	}
+
+	// Query all menu items in one round trip
+	cursor, err := m.collection.Find(ctx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: objIds}}}, {Key: "deleted_at", Value: nil}})
+	if err != nil {
+		return nil, err
+	}
+
+	var menuItems []models.MenuItem
+	err = cursor.All(ctx, &menuItems)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(menuItems) == 0 {
+		return []models.MenuItem{}, nil
+	}
+
+	return menuItems, nil
+}
+
 // GetRestaurantMenuItems implements MenuItemRepo.
 func (m *menuItemRepo) GetRestaurantMenuItems(ctx context.Context, restaurantId string) ([]models.MenuItem, error) {
 	// Parse the ID into a valid ObjectID
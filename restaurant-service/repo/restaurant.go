@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -24,8 +25,10 @@ const (
 )
 
 type RestaurantRepo interface {
-	// GetAllRestaurant retrieves all restaurants from the database.
-	GetAllRestaurant(ctx context.Context, filter RestaurantFilter) ([]models.Restaurant, error)
+	// GetAllRestaurant retrieves a page of restaurants from the database.
+	// If keep is not nil, only restaurants for which keep returns true are included in the page.
+	// The returned cursor is empty if there are no more pages.
+	GetAllRestaurant(ctx context.Context, filter RestaurantFilter, p pagination.Params, keep func(*models.Restaurant) bool) ([]models.Restaurant, string, error)
 	// GetRestaurantById retrieves a single restaurant by its ID.
 	GetRestaurantById(ctx context.Context, id string) (*models.Restaurant, error)
 	// CreateRestaurant creates a new restaurant in the database.
@@ -118,7 +121,7 @@ func (r *restaurantRepo) DeleteRestaurantById(ctx context.Context, id string) er
 }
 
 // GetAllRestaurant implements RestaurantRepo.
-func (r *restaurantRepo) GetAllRestaurant(ctx context.Context, filter RestaurantFilter) ([]models.Restaurant, error) {
+func (r *restaurantRepo) GetAllRestaurant(ctx context.Context, filter RestaurantFilter, p pagination.Params, keep func(*models.Restaurant) bool) ([]models.Restaurant, string, error) {
 	queryFilter := bson.D{{Key: "deleted_at", Value: nil}}
 
 	switch filter {
@@ -128,22 +131,10 @@ func (r *restaurantRepo) GetAllRestaurant(ctx context.Context, filter Restaurant
 		queryFilter = append(queryFilter, bson.E{Key: "approved", Value: false})
 	}
 
-	cursor, err := r.collection.Find(ctx, queryFilter)
-	if err != nil {
-		return nil, err
-	}
-
-	var restaurants []models.Restaurant
-	err = cursor.All(ctx, &restaurants)
-	if err != nil {
-		return nil, err
+	if keep != nil {
+		return pagination.FindFunc(ctx, r.collection, queryFilter, p, keep)
 	}
-
-	if len(restaurants) == 0 {
-		return []models.Restaurant{}, nil
-	}
-
-	return restaurants, nil
+	return pagination.Find[models.Restaurant](ctx, r.collection, queryFilter, p)
 }
 
 // GetRestaurantById implements RestaurantRepo.
@@ -0,0 +1,61 @@
+package repo
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
+)
+
+func TestGetAllRestaurantKeep(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	restaurants := NewRestaurantRepo(db)
+	for i := range 6 {
+		if _, err := restaurants.CreateRestaurant(context.TODO(), &models.Restaurant{Name: strconv.Itoa(i)}); err != nil {
+			t.Fatalf("failed to create restaurant: %s", err)
+		}
+	}
+
+	only := func(names ...string) func(*models.Restaurant) bool {
+		return func(r *models.Restaurant) bool {
+			for _, name := range names {
+				if r.Name == name {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	tests := []struct {
+		name     string
+		limit    int
+		keep     func(*models.Restaurant) bool
+		expected int
+		hasNext  bool
+	}{
+		{"full page with more matches", 1, only("0", "4"), 1, true},
+		{"all matches", 2, only("0", "4"), 2, false},
+		{"no more matches", 1, only("0"), 1, false},
+		{"no matches", 2, only(), 0, false},
+		{"no filter", 5, nil, 5, true},
+	}
+
+	for _, test := range tests {
+		page, next, err := restaurants.GetAllRestaurant(context.TODO(), RestaurantFilterAll, pagination.Params{Limit: test.limit, SortField: "_id"}, test.keep)
+		if err != nil {
+			t.Fatalf("%s: GetAllRestaurant failed: %s", test.name, err)
+		}
+		if len(page) != test.expected {
+			t.Errorf("%s: expected %d restaurants got %d", test.name, test.expected, len(page))
+		}
+		if (next != "") != test.hasNext {
+			t.Errorf("%s: expected next page %v got %q", test.name, test.hasNext, next)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var ErrNoZone = errors.New("delivery zone not found")
+
+type ZoneRepo interface {
+	// GetZones retrieves the delivery zones. Only active zones are returned if activeOnly is true.
+	GetZones(ctx context.Context, activeOnly bool) ([]models.DeliveryZone, error)
+	// CreateZone creates a new delivery zone.
+	CreateZone(ctx context.Context, zone *models.DeliveryZone) (string, error)
+	// UpdateZoneById updates a delivery zone by its ID.
+	UpdateZoneById(ctx context.Context, id string, update *models.DeliveryZoneUpdate) (*models.DeliveryZone, error)
+	// DeleteZoneById deletes a delivery zone by its ID.
+	DeleteZoneById(ctx context.Context, id string) error
+}
+
+type zoneRepo struct {
+	collection *mongo.Collection
+}
+
+// GetZones implements ZoneRepo.
+func (z *zoneRepo) GetZones(ctx context.Context, activeOnly bool) ([]models.DeliveryZone, error) {
+	filter := bson.D{}
+	if activeOnly {
+		filter = append(filter, bson.E{Key: "active", Value: true})
+	}
+
+	cursor, err := z.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := []models.DeliveryZone{}
+	err = cursor.All(ctx, &zones)
+	if err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+// CreateZone implements ZoneRepo.
+func (z *zoneRepo) CreateZone(ctx context.Context, zone *models.DeliveryZone) (string, error) {
+	zone.Id = bson.NilObjectID
+	result, err := z.collection.InsertOne(ctx, zone)
+	if err != nil {
+		return "", err
+	}
+
+	if objId, ok := result.InsertedID.(bson.ObjectID); ok {
+		return objId.Hex(), nil
+	}
+
+	return "", fmt.Errorf("mongo InsertOne result InsertedId is not a ObjectID got %v", result.InsertedID)
+}
+
+// UpdateZoneById implements ZoneRepo.
+func (z *zoneRepo) UpdateZoneById(ctx context.Context, id string, update *models.DeliveryZoneUpdate) (*models.DeliveryZone, error) {
+	objId, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, ErrInvalidId
+	}
+
+	var zone models.DeliveryZone
+	err = z.collection.FindOneAndUpdate(
+		ctx,
+		bson.D{{Key: "_id", Value: objId}},
+		bson.D{{Key: "$set", Value: update}, {Key: "$currentDate", Value: bson.D{{Key: "updated_at", Value: true}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&zone)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNoZone
+		}
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+// DeleteZoneById implements ZoneRepo.
+func (z *zoneRepo) DeleteZoneById(ctx context.Context, id string) error {
+	objId, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidId
+	}
+
+	result, err := z.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: objId}})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNoZone
+	}
+
+	return nil
+}
+
+func NewZoneRepo(con *mongo.Database) ZoneRepo {
+	return &zoneRepo{collection: con.Collection("zones")}
+}
@@ -6,10 +6,19 @@ import (
 	"net"
 	"time"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/zones"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcclient"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/idempotency"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/notify"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/ratelimit"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/gofiber/fiber/v3"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.uber.org/zap"
@@ -20,6 +29,8 @@ import (
 
 //go:generate protoc --go_out=./proto --go_opt=paths=source_relative  --go-grpc_out=./proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/user-service.proto
 
+//go:generate protoc --go_out=./proto --go_opt=paths=source_relative  --go-grpc_out=./proto --go-grpc_opt=paths=source_relative --proto_path ../shared/api/ ../shared/api/money.proto
+
 type Config struct {
 	Server struct {
 		Port int
@@ -28,11 +39,24 @@ type Config struct {
 		Port int
 	}
 	Services struct {
-		User string
+		User    string
+		Clients grpcclient.Config
 	}
+	Delivery zones.Config
 	Database database.MongoConfig
 	Logger   logger.Config
 	Notify   notify.Config
+	Tracing  tracing.Config
+
+	Idempotency idempotency.Config
+	RateLimit   ratelimit.Config
+}
+
+// grpcRules contains the services that are allowed to call the grpc methods of the service.
+var grpcRules = grpcauth.Rules{
+	"/RestaurantService/GetItemsById":      {"order-service"},
+	"/RestaurantService/GetRestaurantById": {"order-service"},
+	"/RestaurantService/CheckDelivery":     {"order-service"},
 }
 
 type Server struct {
@@ -40,6 +64,8 @@ type Server struct {
 	grpc *grpc.Server
 	cfg  *Config
 	db   *mongo.Client
+
+	idempotency *idempotency.Store
 }
 
 // New creates a new server.
@@ -48,7 +74,23 @@ func New(cfg *Config, db *mongo.Client) *Server {
 		cfg:  cfg,
 		db:   db,
 		app:  fiber.New(shared.DefaultFiberConfig),
-		grpc: grpc.NewServer(grpc.ConnectionTimeout(time.Second * 10)),
+		grpc: grpc.NewServer(grpc.ConnectionTimeout(time.Second*10), tracing.ServerOption(), metrics.ServerOption(), requestlog.ServerOption(), grpcauth.ServerOption(grpcRules)),
+	}
+
+	s.app.Use(tracing.Middleware())
+	s.app.Use(requestlog.Middleware())
+	metrics.Register(s.app)
+	health.Register(s.app)
+
+	limits, err := ratelimit.NewMongoStore(context.TODO(), db.Database("restaurant-service"))
+	if err != nil {
+		zap.L().Fatal("Failed to create rate limit store", zap.Error(err))
+	}
+	ratelimit.New(limits, cfg.RateLimit, nil).Register(s.app)
+
+	s.idempotency, err = idempotency.NewStore(context.TODO(), db.Database("restaurant-service"), cfg.Idempotency)
+	if err != nil {
+		zap.L().Fatal("Failed to create idempotency store", zap.Error(err))
 	}
 
 	return s
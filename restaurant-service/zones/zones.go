@@ -0,0 +1,90 @@
+// Package zones checks if restaurants can deliver to a location.
+package zones
+
+import (
+	"context"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+)
+
+// Config contains the serviceability settings.
+type Config struct {
+	// DefaultMaxKm is the maximum delivery distance for restaurants that do not set a maximum delivery distance.
+	DefaultMaxKm float64
+}
+
+// Checker checks if restaurants can deliver to a location.
+// A location can be delivered to if it is inside an active delivery zone and within the maximum delivery distance of the restaurant.
+// The zone check is skipped if there are no active delivery zones.
+type Checker struct {
+	zones repo.ZoneRepo
+	cfg   Config
+}
+
+// New creates a new serviceability checker.
+func New(zones repo.ZoneRepo, cfg Config) *Checker {
+	return &Checker{zones: zones, cfg: cfg}
+}
+
+// CanDeliver checks if the restaurant can deliver to the location.
+func (c *Checker) CanDeliver(ctx context.Context, restaurant *models.Restaurant, lat, lng float64) (bool, error) {
+	zones, err := c.zones.GetZones(ctx, true)
+	if err != nil {
+		return false, err
+	}
+
+	return inZone(zones, lat, lng) && c.inRange(restaurant, lat, lng), nil
+}
+
+// Deliverable returns a function that checks if a restaurant can deliver to the location.
+// ok is false if the location is outside all active delivery zones, in which case no restaurant can deliver to it.
+func (c *Checker) Deliverable(ctx context.Context, lat, lng float64) (keep func(*models.Restaurant) bool, ok bool, err error) {
+	zones, err := c.zones.GetZones(ctx, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !inZone(zones, lat, lng) {
+		return nil, false, nil
+	}
+
+	return func(restaurant *models.Restaurant) bool { return c.inRange(restaurant, lat, lng) }, true, nil
+}
+
+// inRange checks if the location is within the maximum delivery distance of the restaurant.
+// Restaurants without a stored position are only limited by the delivery zones.
+func (c *Checker) inRange(restaurant *models.Restaurant, lat, lng float64) bool {
+	maxKm := restaurant.MaxDeliveryKm
+	if maxKm <= 0 {
+		maxKm = c.cfg.DefaultMaxKm
+	}
+	if maxKm <= 0 {
+		return true
+	}
+
+	// restaurant positions are stored as [latitude, longitude]
+	position := restaurant.Address.Position.Coordinates
+	if position == [2]float64{} {
+		// restaurants created before positions were added do not have a position
+		return true
+	}
+
+	return location.DistanceKm(position[0], position[1], lat, lng) <= maxKm
+}
+
+// inZone checks if the location is inside any of the zones.
+func inZone(zones []models.DeliveryZone, lat, lng float64) bool {
+	if len(zones) == 0 {
+		return true
+	}
+
+	for i := range zones {
+		if zones[i].Area.Contains(lat, lng) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,75 @@
+package zones
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/models"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/restaurant-service/repo"
+)
+
+type fakeZones struct {
+	repo.ZoneRepo
+	zones []models.DeliveryZone
+}
+
+func (f *fakeZones) GetZones(context.Context, bool) ([]models.DeliveryZone, error) {
+	return f.zones, nil
+}
+
+func TestInRange(t *testing.T) {
+	checker := New(nil, Config{DefaultMaxKm: 10})
+
+	// restaurant positions are [latitude, longitude]
+	colombo := models.Address{Position: models.Point{Type: "point", Coordinates: [2]float64{6.9271, 79.8612}}}
+
+	tests := []struct {
+		name       string
+		restaurant models.Restaurant
+		lat, lng   float64
+		expected   bool
+	}{
+		{"within default distance", models.Restaurant{Address: colombo}, 6.95, 79.86, true},
+		{"outside default distance", models.Restaurant{Address: colombo}, 7.1, 79.86, false},
+		{"within restaurant distance", models.Restaurant{Address: colombo, MaxDeliveryKm: 25}, 7.1, 79.86, true},
+		{"outside restaurant distance", models.Restaurant{Address: colombo, MaxDeliveryKm: 1}, 6.95, 79.86, false},
+		{"no position", models.Restaurant{}, 7.1, 79.86, true},
+	}
+
+	for _, test := range tests {
+		if got := checker.inRange(&test.restaurant, test.lat, test.lng); got != test.expected {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+	}
+
+	unlimited := New(nil, Config{})
+	if !unlimited.inRange(&models.Restaurant{Address: colombo}, 8, 81) {
+		t.Errorf("expected no limit when the default distance is 0")
+	}
+}
+
+func TestDeliverable(t *testing.T) {
+	// zone positions are [longitude, latitude]
+	zone := models.DeliveryZone{Active: true, Area: models.Polygon{Type: "Polygon", Coordinates: [][][2]float64{
+		{{79.8, 6.8}, {80.0, 6.8}, {80.0, 7.0}, {79.8, 7.0}, {79.8, 6.8}},
+	}}}
+	checker := New(&fakeZones{zones: []models.DeliveryZone{zone}}, Config{DefaultMaxKm: 10})
+
+	near := models.Restaurant{Address: models.Address{Position: models.Point{Type: "point", Coordinates: [2]float64{6.9271, 79.8612}}}}
+	far := models.Restaurant{Address: models.Address{Position: models.Point{Type: "point", Coordinates: [2]float64{6.81, 79.99}}}}
+
+	keep, ok, err := checker.Deliverable(context.Background(), 6.95, 79.86)
+	if err != nil || !ok {
+		t.Fatalf("expected location to be in a zone, got %v, %v", ok, err)
+	}
+	if !keep(&near) {
+		t.Errorf("expected restaurant within the distance to be kept")
+	}
+	if keep(&far) {
+		t.Errorf("expected restaurant outside the distance to be removed")
+	}
+
+	if _, ok, err = checker.Deliverable(context.Background(), 7.5, 79.86); err != nil || ok {
+		t.Errorf("expected location outside the zones to be rejected, got %v, %v", ok, err)
+	}
+}
@@ -2,6 +2,7 @@ import path from "path";
 import { fileURLToPath } from "url";
 import { loadPackageDefinition, credentials } from "@grpc/grpc-js";
 import protoLoader from "@grpc/proto-loader";
+import { serviceAuth } from "./serviceAuth.js";
 
 
 const __dirname = path.dirname(fileURLToPath(import.meta.url));
@@ -21,6 +22,8 @@ const grpcObject = loadPackageDefinition(packageDefinition);
 const { DeliveryService } = grpcObject;
 
 // Create a gRPC client for OrderService
-const deliveryClient = new DeliveryService(`${process.env.APP_SERVICES_DELIVERY}`, credentials.createInsecure());
+const deliveryClient = new DeliveryService(`${process.env.APP_SERVICES_DELIVERY}`, credentials.createInsecure(), {
+    interceptors: [serviceAuth("review-service")],
+});
 
 export default deliveryClient;
\ No newline at end of file
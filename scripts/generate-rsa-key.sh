@@ -5,3 +5,19 @@ root_dir=$(dirname $(dirname $(realpath $0)))
 
 openssl genrsa -out service.priv.key 2048
 openssl rsa -in service.priv.key -pubout >service.pub.key
+
+# each service signs the tokens sent to other services with its own key.
+# service-keys/service_keys.pub contains the public keys of all services with the name of the service in the Service header.
+services=(order-service restaurant-service delivery-service user-service payment-service review-service)
+
+mkdir -p service-keys
+: >service-keys/service_keys.pub
+for service in ${services[*]}; do
+    openssl genpkey -algorithm ed25519 -out "service-keys/$service.key"
+
+    echo "-----BEGIN PUBLIC KEY-----" >>service-keys/service_keys.pub
+    echo "Service: $service" >>service-keys/service_keys.pub
+    echo >>service-keys/service_keys.pub
+    openssl pkey -in "service-keys/$service.key" -pubout | sed '1d;$d' >>service-keys/service_keys.pub
+    echo "-----END PUBLIC KEY-----" >>service-keys/service_keys.pub
+done
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"bytes"
+	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
@@ -45,6 +47,53 @@ func LoadJWTVerifyKey() (*rsa.PublicKey, error) {
 	return loadKey[*rsa.PublicKey]("jwt_key", "service.pub.key")
 }
 
+// LoadServiceKey loads the private key that the service uses to sign tokens sent to other services.
+func LoadServiceKey() (ed25519.PrivateKey, error) {
+	return loadKey[ed25519.PrivateKey]("service_key", "service.key")
+}
+
+// LoadServicePublicKeys loads the public keys that are used to verify tokens sent by other services.
+// The file contains a PEM block for each service with the name of the service in the Service header.
+func LoadServicePublicKeys() (map[string]ed25519.PublicKey, error) {
+	data, err := LoadSecret("service_public_keys", "service_keys.pub")
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]ed25519.PublicKey{}
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		service := block.Headers["Service"]
+		if block.Type != "PUBLIC KEY" || service == "" {
+			return nil, fmt.Errorf("service public keys must be PUBLIC KEY blocks with a Service header")
+		}
+
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key for %s: %w", service, err)
+		}
+
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key for %s is not an ed25519 key", service)
+		}
+		keys[service] = publicKey
+	}
+
+	if len(bytes.TrimSpace(data)) != 0 {
+		return nil, fmt.Errorf("service public keys have trailing data")
+	} else if len(keys) == 0 {
+		return nil, fmt.Errorf("no service public keys found")
+	}
+
+	return keys, nil
+}
+
 func loadKey[T any](name string, fallback string) (T, error) {
 	var key T
 
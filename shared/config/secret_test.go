@@ -0,0 +1,47 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestLoadServicePublicKeys(t *testing.T) {
+	var bundle []byte
+	expected := map[string]ed25519.PublicKey{}
+	for _, service := range []string{"order-service", "payment-service"} {
+		key, _, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("failed to create key: %s", err)
+		}
+		data, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			t.Fatalf("failed to marshal key: %s", err)
+		}
+
+		expected[service] = key
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Headers: map[string]string{"Service": service}, Bytes: data})...)
+	}
+
+	t.Setenv("APP_SECRET_service_public_keys", string(bundle))
+	keys, err := LoadServicePublicKeys()
+	if err != nil {
+		t.Fatalf("failed to load keys: %s", err)
+	}
+	if len(keys) != len(expected) {
+		t.Errorf("expected %d keys got %d", len(expected), len(keys))
+	}
+	for service, key := range expected {
+		if !key.Equal(keys[service]) {
+			t.Errorf("%s: incorrect public key", service)
+		}
+	}
+
+	block, _ := pem.Decode(bundle)
+	delete(block.Headers, "Service")
+	t.Setenv("APP_SECRET_service_public_keys", string(pem.EncodeToMemory(block)))
+	if _, err := LoadServicePublicKeys(); err == nil {
+		t.Errorf("expected keys without a service name to be rejected")
+	}
+}
@@ -3,6 +3,9 @@ package database
 import (
 	"context"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
@@ -12,12 +15,16 @@ import (
 type MongoConfig struct {
 	// URL the url for the mongo database
 	URL string
+	// Migrate applies the pending migrations when the service starts
+	Migrate bool
 }
 
 // Connects to the mongodb database
 func ConnectMongo(ctx context.Context, cfg MongoConfig) (*mongo.Client, error) {
 	serverAPI := options.ServerAPI(options.ServerAPIVersion1)
-	opts := options.Client().ApplyURI(cfg.URL).SetServerAPIOptions(serverAPI)
+	opts := options.Client().ApplyURI(cfg.URL).SetServerAPIOptions(serverAPI).
+		SetMonitor(tracing.CommandMonitor()).
+		SetPoolMonitor(metrics.PoolMonitor())
 
 	// Create a new client and connect to the server
 	client, err := mongo.Connect(opts)
@@ -31,5 +38,6 @@ func ConnectMongo(ctx context.Context, cfg MongoConfig) (*mongo.Client, error) {
 		return nil, err
 	}
 
+	health.Add("mongo", health.Mongo(client))
 	return client, nil
 }
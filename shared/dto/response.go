@@ -6,6 +6,14 @@ type Response struct {
 	Data any  `json:"data"`
 }
 
+// Page is the format for a response containing a single page of a list.
+// NextCursor is null if there are no more pages.
+type Page struct {
+	Ok         bool    `json:"ok"`
+	Data       any     `json:"data"`
+	NextCursor *string `json:"next_cursor"`
+}
+
 // Response is the format for a response that indicates that an error occurred while processing the request
 type ErrorResponse struct {
 	Ok     bool   `json:"ok"`
@@ -23,6 +31,14 @@ func NamedOk(name string, data any) Response {
 	return Response{Ok: true, Data: map[string]any{name: data}}
 }
 
+// Paged creates a new [Page]. next should be empty if there are no more pages.
+func Paged(data any, next string) Page {
+	if next == "" {
+		return Page{Ok: true, Data: data}
+	}
+	return Page{Ok: true, Data: data, NextCursor: &next}
+}
+
 // Error creates a new error response
 func Error(err string, reason ...any) ErrorResponse {
 	if len(reason) > 0 {
@@ -0,0 +1,50 @@
+// Package eta estimates preparation and travel times for orders and deliveries.
+package eta
+
+import (
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/location"
+)
+
+// Config contains the settings used to estimate times.
+type Config struct {
+	// SpeedKmh is the average driving speed of drivers.
+	SpeedKmh float64
+	// RoadFactor is the ratio between the road distance and the straight line distance.
+	RoadFactor float64
+	// DefaultPrepTime is the preparation time used for restaurants that do not set a preparation time.
+	DefaultPrepTime time.Duration
+	// HandoverTime is the time added for each pickup and drop-off.
+	HandoverTime time.Duration
+}
+
+// PrepTime returns the time needed to prepare an order.
+// Items are prepared in parallel, so the preparation time is the restaurant preparation time
+// or the preparation time of the slowest item, whichever is longer.
+// A restaurant preparation time of 0 uses the default preparation time.
+func (c Config) PrepTime(restaurant time.Duration, items ...time.Duration) time.Duration {
+	prep := restaurant
+	if prep <= 0 {
+		prep = c.DefaultPrepTime
+	}
+
+	for _, item := range items {
+		prep = max(prep, item)
+	}
+
+	return prep
+}
+
+// TravelTime returns the time needed to travel between two coordinates including the handover time.
+// Only the handover time is returned if either coordinate is not set.
+func (c Config) TravelTime(lat1, lng1, lat2, lng2 float64) time.Duration {
+	if c.SpeedKmh <= 0 || (lat1 == 0 && lng1 == 0) || (lat2 == 0 && lng2 == 0) {
+		return c.HandoverTime
+	}
+
+	distance := location.DistanceKm(lat1, lng1, lat2, lng2) * max(c.RoadFactor, 1)
+	travel := time.Duration(distance / c.SpeedKmh * float64(time.Hour))
+
+	return travel.Round(time.Second) + c.HandoverTime
+}
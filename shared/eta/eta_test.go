@@ -0,0 +1,48 @@
+package eta
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrepTime(t *testing.T) {
+	cfg := Config{DefaultPrepTime: 15 * time.Minute}
+
+	tests := []struct {
+		name       string
+		restaurant time.Duration
+		items      []time.Duration
+		expected   time.Duration
+	}{
+		{"default", 0, nil, 15 * time.Minute},
+		{"restaurant", 10 * time.Minute, nil, 10 * time.Minute},
+		{"slowest item", 10 * time.Minute, []time.Duration{5 * time.Minute, 25 * time.Minute}, 25 * time.Minute},
+		{"faster items", 20 * time.Minute, []time.Duration{5 * time.Minute}, 20 * time.Minute},
+		{"items with default", 0, []time.Duration{30 * time.Minute}, 30 * time.Minute},
+	}
+
+	for _, test := range tests {
+		if got := cfg.PrepTime(test.restaurant, test.items...); got != test.expected {
+			t.Errorf("%s: expected %s got %s", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestTravelTime(t *testing.T) {
+	cfg := Config{SpeedKmh: 30, RoadFactor: 1, HandoverTime: 2 * time.Minute}
+
+	// 0.1 degrees of latitude is about 11.1 km which takes about 22 minutes at 30 km/h
+	got := cfg.TravelTime(6.9, 79.8, 7.0, 79.8)
+	if got < 23*time.Minute || got > 25*time.Minute {
+		t.Errorf("expected about 24 minutes got %s", got)
+	}
+
+	cfg.RoadFactor = 1.5
+	if slower := cfg.TravelTime(6.9, 79.8, 7.0, 79.8); slower <= got {
+		t.Errorf("road factor should increase the travel time: %s <= %s", slower, got)
+	}
+
+	if got := cfg.TravelTime(0, 0, 7.0, 79.8); got != cfg.HandoverTime {
+		t.Errorf("missing coordinates should only use the handover time, got %s", got)
+	}
+}
@@ -0,0 +1,237 @@
+// Package grpcauth authenticates grpc calls between services.
+//
+// Each service signs a short lived jwt token with its own private key and sends it with every call.
+// The server verifies the token with the public key of the service named in the token and checks if
+// the calling service is allowed to call the method. A service cannot create tokens for other services
+// because it does not have their private keys.
+package grpcauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tokenLifetime is how long a service token is valid for.
+const tokenLifetime = 5 * time.Minute
+
+// audience is the audience of service tokens.
+// This prevents service tokens from being accepted as user tokens.
+const audience = "services"
+
+var (
+	// ErrNoToken is returned when the call does not contain a service token.
+	ErrNoToken = status.Error(codes.Unauthenticated, "missing service token")
+	// ErrInvalidToken is returned when the service token is invalid or expired.
+	ErrInvalidToken = status.Error(codes.Unauthenticated, "invalid service token")
+	// ErrPermission is returned when the calling service is not allowed to call the method.
+	ErrPermission = status.Error(codes.PermissionDenied, "service is not allowed to call this method")
+)
+
+// Rules contains the services that are allowed to call each method.
+// The key is the full method name (e.g. /OrderService/SetPaymentStatus).
+// Methods that are not in the rules can be called by any authenticated service.
+type Rules map[string][]string
+
+// Identity signs and verifies service tokens.
+type Identity struct {
+	service string
+	key     ed25519.PrivateKey
+	// services contains the public keys of the services keyed by the service name.
+	services map[string]ed25519.PublicKey
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// New creates an identity for the service.
+// key is the private key of the service and services contains the public keys of the services
+// that can call this service keyed by the service name.
+func New(service string, key ed25519.PrivateKey, services map[string]ed25519.PublicKey) (*Identity, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, errors.New("service key is not a valid ed25519 private key")
+	}
+	return &Identity{service: service, key: key, services: services}, nil
+}
+
+// Token returns a token for the service.
+// Tokens are reused until they are close to expiring.
+func (i *Identity) Token() (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	if i.token != "" && now.Add(tokenLifetime/5).Before(i.expires) {
+		return i.token, nil
+	}
+
+	expires := now.Add(tokenLifetime)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{
+		Subject:   i.service,
+		Audience:  jwt.ClaimStrings{audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expires),
+	}).SignedString(i.key)
+	if err != nil {
+		return "", err
+	}
+
+	i.token, i.expires = token, expires
+	return token, nil
+}
+
+// Verify verifies the token and returns the name of the service that created it.
+// The token is verified with the public key of the service in the subject, so tokens signed
+// by a different service are rejected.
+func (i *Identity) Verify(token string) (string, error) {
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(*jwt.Token) (any, error) {
+			key, ok := i.services[claims.Subject]
+			if !ok {
+				return nil, ErrInvalidToken
+			}
+			return key, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" {
+		return "", ErrInvalidToken
+	}
+
+	return claims.Subject, nil
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (i *Identity) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	token, err := i.Token()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"authorization": "Bearer " + token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+// Services communicate over the internal network without tls.
+func (i *Identity) RequireTransportSecurity() bool {
+	return false
+}
+
+// DialOption returns the grpc client option that sends the service token with every call.
+func (i *Identity) DialOption() grpc.DialOption {
+	return grpc.WithPerRPCCredentials(i)
+}
+
+// ServerOption returns the grpc server option that rejects calls from unauthenticated services
+// and calls to methods that the service is not allowed to call.
+func (i *Identity) ServerOption(rules Rules) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(interceptor(func() *Identity { return i }, rules))
+}
+
+func (i *Identity) authorize(ctx context.Context, method string, rules Rules) (string, error) {
+	values := metadata.ValueFromIncomingContext(ctx, "authorization")
+	if len(values) == 0 {
+		return "", ErrNoToken
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return "", ErrNoToken
+	}
+
+	caller, err := i.Verify(token)
+	if err != nil {
+		return "", err
+	}
+
+	allowed, ok := rules[method]
+	if !ok {
+		return caller, nil
+	}
+	for _, service := range allowed {
+		if service == caller {
+			return caller, nil
+		}
+	}
+
+	return "", ErrPermission
+}
+
+type callerKey struct{}
+
+// Caller returns the name of the service that made the call.
+// An empty string is returned if the context does not belong to an authenticated call.
+func Caller(ctx context.Context) string {
+	caller, _ := ctx.Value(callerKey{}).(string)
+	return caller
+}
+
+var self atomic.Pointer[Identity]
+
+// Setup sets the identity used by [DialOption] and [ServerOption].
+func Setup(service string, key ed25519.PrivateKey, services map[string]ed25519.PublicKey) error {
+	identity, err := New(service, key, services)
+	if err != nil {
+		return err
+	}
+
+	self.Store(identity)
+	return nil
+}
+
+// DialOption returns the grpc client option that sends the token of the identity set by [Setup].
+// No token is sent if [Setup] was not called.
+func DialOption() grpc.DialOption {
+	return grpc.WithPerRPCCredentials(defaultCredentials{})
+}
+
+// ServerOption returns the grpc server option that authorizes calls using the identity set by [Setup].
+// All calls are rejected if [Setup] was not called.
+func ServerOption(rules Rules) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(interceptor(self.Load, rules))
+}
+
+func interceptor(getIdentity func() *Identity, rules Rules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		identity := getIdentity()
+		if identity == nil {
+			return nil, status.Error(codes.Unavailable, "service authentication is not configured")
+		}
+
+		caller, err := identity.authorize(ctx, info.FullMethod, rules)
+		if err != nil {
+			return nil, err
+		}
+		return handler(context.WithValue(ctx, callerKey{}, caller), req)
+	}
+}
+
+// defaultCredentials sends the token of the identity set by [Setup].
+type defaultCredentials struct{}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (defaultCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	identity := self.Load()
+	if identity == nil {
+		return nil, nil
+	}
+	return identity.GetRequestMetadata(ctx, uri...)
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials.
+func (defaultCredentials) RequireTransportSecurity() bool {
+	return false
+}
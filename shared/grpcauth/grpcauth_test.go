@@ -0,0 +1,119 @@
+package grpcauth
+
+import (
+	"context"
+	"crypto/ed25519"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testKeys creates a private key for each service and returns the keys with the public keys of the services.
+func testKeys(t *testing.T, services ...string) (map[string]ed25519.PrivateKey, map[string]ed25519.PublicKey) {
+	t.Helper()
+
+	private, public := map[string]ed25519.PrivateKey{}, map[string]ed25519.PublicKey{}
+	for _, service := range services {
+		publicKey, privateKey, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatalf("failed to create key: %s", err)
+		}
+		private[service], public[service] = privateKey, publicKey
+	}
+	return private, public
+}
+
+func TestAuthorize(t *testing.T) {
+	keys, services := testKeys(t, "payment-service", "delivery-service", "review-service", "order-service")
+	payment, _ := New("payment-service", keys["payment-service"], nil)
+	delivery, _ := New("delivery-service", keys["delivery-service"], nil)
+	// review-service signs a token claiming to be payment-service with its own key
+	impersonator, _ := New("payment-service", keys["review-service"], nil)
+	unknown, _ := New("unknown-service", keys["review-service"], nil)
+	server, _ := New("order-service", keys["order-service"], services)
+
+	rules := Rules{"/OrderService/SetPaymentStatus": {"payment-service"}}
+	handle := interceptor(func() *Identity { return server }, rules)
+
+	call := func(client *Identity, method string) (string, error) {
+		ctx := context.Background()
+		if client != nil {
+			md, err := client.GetRequestMetadata(ctx)
+			if err != nil {
+				t.Fatalf("failed to create token: %s", err)
+			}
+			ctx = metadata.NewIncomingContext(ctx, metadata.New(md))
+		}
+
+		var caller string
+		_, err := handle(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, _ any) (any, error) {
+			caller = Caller(ctx)
+			return nil, nil
+		})
+		return caller, err
+	}
+
+	tests := []struct {
+		name   string
+		client *Identity
+		method string
+		caller string
+		err    error
+	}{
+		{"allowed service", payment, "/OrderService/SetPaymentStatus", "payment-service", nil},
+		{"not allowed service", delivery, "/OrderService/SetPaymentStatus", "", ErrPermission},
+		{"method without rules", delivery, "/OrderService/SetDeliveryDriver", "delivery-service", nil},
+		{"key of another service", impersonator, "/OrderService/SetPaymentStatus", "", ErrInvalidToken},
+		{"unknown service", unknown, "/OrderService/GetOrderPrice", "", ErrInvalidToken},
+		{"no token", nil, "/OrderService/GetOrderPrice", "", ErrNoToken},
+	}
+
+	for _, test := range tests {
+		caller, err := call(test.client, test.method)
+		if status.Code(err) != status.Code(test.err) || caller != test.caller {
+			t.Errorf("%s: expected caller %q error %v got caller %q error %v", test.name, test.caller, test.err, caller, err)
+		}
+	}
+}
+
+func TestTokenReuse(t *testing.T) {
+	keys, services := testKeys(t, "order-service")
+	identity, _ := New("order-service", keys["order-service"], services)
+
+	first, err := identity.Token()
+	if err != nil {
+		t.Fatalf("failed to create token: %s", err)
+	}
+	second, _ := identity.Token()
+	if first != second {
+		t.Errorf("expected token to be reused")
+	}
+
+	if caller, err := identity.Verify(first); err != nil || caller != "order-service" {
+		t.Errorf("expected caller order-service got %q error %v", caller, err)
+	}
+}
+
+func TestVerifyAlgorithm(t *testing.T) {
+	keys, services := testKeys(t, "payment-service", "order-service")
+	server, _ := New("order-service", keys["order-service"], services)
+
+	// a token signed with the public key as an hmac secret must not be accepted
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "payment-service",
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString([]byte(services["payment-service"]))
+	if err != nil {
+		t.Fatalf("failed to create token: %s", err)
+	}
+
+	if caller, err := server.Verify(token); err == nil {
+		t.Errorf("expected hmac token to be rejected got caller %q", caller)
+	}
+}
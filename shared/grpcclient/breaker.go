@@ -0,0 +1,112 @@
+package grpcclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var breakerOpened = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "grpc_client_breaker_opened_total",
+	Help: "Number of times the circuit breaker for a service was opened.",
+}, []string{"service"})
+
+// breaker is a circuit breaker that rejects calls after too many consecutive failures.
+// Once the cooldown has passed, a single call is allowed through. The breaker closes if it succeeds.
+type breaker struct {
+	failures int
+	cooldown time.Duration
+
+	mu        sync.Mutex
+	count     int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker(failures int, cooldown time.Duration) *breaker {
+	return &breaker{failures: failures, cooldown: cooldown}
+}
+
+// allow checks if a call can be made.
+// probe is true if the call is the single call allowed through the open breaker after the cooldown.
+// The result of the call must be passed to record with the same probe value.
+func (b *breaker) allow(now time.Time) (ok bool, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.count < b.failures {
+		return true, false
+	}
+
+	// only allow a single call through after the cooldown
+	if now.Before(b.openUntil) || b.probing {
+		return false, false
+	}
+	b.probing = true
+	return true, true
+}
+
+// record records the result of a call.
+// It returns true if the breaker was opened by the call.
+func (b *breaker) record(now time.Time, probe bool, failed bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	} else if b.count >= b.failures {
+		// the call started before the breaker opened. only the probe can close the breaker.
+		return false
+	}
+
+	if !failed {
+		b.count = 0
+		return false
+	}
+
+	b.count++
+	if b.count >= b.failures {
+		b.openUntil = now.Add(b.cooldown)
+		return true
+	}
+	return false
+}
+
+// interceptor returns an interceptor that rejects calls while the breaker is open.
+func (b *breaker) interceptor(service string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if b.failures <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ok, probe := b.allow(time.Now())
+		if !ok {
+			return status.Errorf(codes.Unavailable, "%s is unavailable", service)
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if b.record(time.Now(), probe, isFailure(err)) {
+			breakerOpened.WithLabelValues(service).Inc()
+			zap.L().Warn("Circuit breaker opened", zap.String("service", service), zap.Error(err))
+		}
+		return err
+	}
+}
+
+// isFailure checks if the error indicates that the service is not working.
+// Errors returned by the service for invalid requests are not failures.
+func isFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
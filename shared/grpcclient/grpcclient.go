@@ -0,0 +1,118 @@
+// Package grpcclient creates grpc client connections to other services.
+//
+// Connections created by [Dial] send the tracing, metrics, request id and service authentication data with every call,
+// apply a default deadline to calls without one, retry idempotent methods when the service is unavailable
+// and stop sending calls to a service that keeps failing.
+package grpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // enables client side health checking
+)
+
+// Config contains the config for client connections.
+type Config struct {
+	// Timeout is the deadline used for calls that do not already have a deadline.
+	Timeout time.Duration
+	// MaxAttempts is the maximum number of times an idempotent call is attempted.
+	MaxAttempts int
+	// BreakerFailures is the number of consecutive failed calls that opens the circuit breaker.
+	// The circuit breaker is disabled if this is 0.
+	BreakerFailures int
+	// BreakerCooldown is how long the circuit breaker stays open before a call is allowed through.
+	BreakerCooldown time.Duration
+}
+
+// Dial creates a client connection to the service at addr.
+// idempotent contains the full names of the methods that are safe to retry (e.g. /UserService/GetUserBy).
+// The connection is added to the readiness checks.
+func Dial(service string, addr string, cfg Config, idempotent ...string) (*grpc.ClientConn, error) {
+	serviceConfig, err := buildServiceConfig(cfg, idempotent)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker := newBreaker(cfg.BreakerFailures, cfg.BreakerCooldown)
+
+	con, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithChainUnaryInterceptor(breaker.interceptor(service), deadline(cfg.Timeout)),
+		tracing.DialOption(), metrics.DialOption(), requestlog.DialOption(), grpcauth.DialOption(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	health.Add("grpc:"+service, health.GRPC(con))
+	return con, nil
+}
+
+// deadline returns an interceptor that sets the deadline for calls without a deadline.
+func deadline(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if _, ok := ctx.Deadline(); !ok && timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// buildServiceConfig creates the grpc service config json.
+// See https://github.com/grpc/grpc/blob/master/doc/service_config.md
+func buildServiceConfig(cfg Config, idempotent []string) (string, error) {
+	config := map[string]any{
+		"loadBalancingConfig": []map[string]any{{"round_robin": map[string]any{}}},
+		"healthCheckConfig":   map[string]string{"serviceName": ""},
+	}
+
+	if cfg.MaxAttempts > 1 && len(idempotent) > 0 {
+		retry := methodConfig{RetryPolicy: &retryPolicy{
+			MaxAttempts:          cfg.MaxAttempts,
+			InitialBackoff:       "0.1s",
+			MaxBackoff:           "1s",
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}}
+
+		for _, method := range idempotent {
+			service, name, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+			retry.Name = append(retry.Name, methodName{Service: service, Method: name})
+		}
+		config["methodConfig"] = []methodConfig{retry}
+	}
+
+	data, err := json.Marshal(config)
+	return string(data), err
+}
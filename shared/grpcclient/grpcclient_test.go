@@ -0,0 +1,134 @@
+package grpcclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreaker(t *testing.T) {
+	b := newBreaker(2, time.Minute)
+	now := time.Now()
+
+	b.record(now, false, true)
+	if ok, _ := b.allow(now); !ok {
+		t.Fatal("expected breaker to be closed after a single failure")
+	}
+	if !b.record(now, false, true) {
+		t.Fatal("expected breaker to open after the second failure")
+	}
+	if ok, _ := b.allow(now.Add(time.Second)); ok {
+		t.Fatal("expected open breaker to reject calls")
+	}
+
+	// a single call is allowed after the cooldown
+	later := now.Add(2 * time.Minute)
+	ok, probe := b.allow(later)
+	if !ok || !probe {
+		t.Fatal("expected a probe to be allowed after the cooldown")
+	}
+	if ok, _ := b.allow(later); ok {
+		t.Fatal("expected only one call to be allowed after the cooldown")
+	}
+
+	b.record(later, probe, false)
+	if ok, probe := b.allow(later); !ok || probe {
+		t.Fatal("expected breaker to close after a successful probe")
+	}
+	if ok, _ := b.allow(later); !ok {
+		t.Fatal("expected closed breaker to allow all calls")
+	}
+}
+
+func TestBreakerLateCall(t *testing.T) {
+	b := newBreaker(1, time.Minute)
+	now := time.Now()
+
+	// the late call starts before the breaker opens
+	_, lateProbe := b.allow(now)
+	if !b.record(now, false, true) {
+		t.Fatal("expected breaker to open after the failure")
+	}
+
+	later := now.Add(2 * time.Minute)
+	_, probe := b.allow(later)
+	if !probe {
+		t.Fatal("expected a probe to be allowed after the cooldown")
+	}
+
+	// the late call finishing must not allow another probe or close the breaker
+	b.record(later, lateProbe, false)
+	if ok, _ := b.allow(later); ok {
+		t.Fatal("expected the late call not to close the breaker or allow a second probe")
+	}
+
+	// the probe failing reopens the breaker
+	if !b.record(later, probe, true) {
+		t.Fatal("expected the failed probe to reopen the breaker")
+	}
+	if ok, _ := b.allow(later.Add(time.Second)); ok {
+		t.Fatal("expected reopened breaker to reject calls")
+	}
+}
+
+func TestBreakerInterceptor(t *testing.T) {
+	intercept := newBreaker(1, time.Minute).interceptor("test-service")
+
+	calls := 0
+	invoker := func(_ context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		calls++
+		return status.Error(codes.Unavailable, "down")
+	}
+
+	_ = intercept(context.Background(), "/Test/Method", nil, nil, nil, invoker)
+	err := intercept(context.Background(), "/Test/Method", nil, nil, nil, invoker)
+	if calls != 1 || status.Code(err) != codes.Unavailable {
+		t.Errorf("expected call to be rejected without calling the service got %d calls, error %v", calls, err)
+	}
+
+	// errors caused by the request do not open the breaker
+	intercept = newBreaker(1, time.Minute).interceptor("test-service")
+	notFound := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		return status.Error(codes.NotFound, "not found")
+	}
+	_ = intercept(context.Background(), "/Test/Method", nil, nil, nil, notFound)
+	if err := intercept(context.Background(), "/Test/Method", nil, nil, nil, notFound); status.Code(err) != codes.NotFound {
+		t.Errorf("expected not found error got %v", err)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	intercept := deadline(time.Second)
+
+	var got time.Time
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		got, _ = ctx.Deadline()
+		return nil
+	}
+
+	_ = intercept(context.Background(), "/Test/Method", nil, nil, nil, invoker)
+	if got.IsZero() || time.Until(got) > time.Second {
+		t.Errorf("expected default deadline got %v", got)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := ctx.Deadline()
+	_ = intercept(ctx, "/Test/Method", nil, nil, nil, invoker)
+	if !got.Equal(want) {
+		t.Errorf("expected existing deadline %v to be kept got %v", want, got)
+	}
+}
+
+func TestDial(t *testing.T) {
+	cfg := Config{Timeout: time.Second, MaxAttempts: 3, BreakerFailures: 5, BreakerCooldown: time.Second}
+	con, err := Dial("test-service", "localhost:0", cfg, "/UserService/GetUserBy")
+	if err != nil {
+		t.Fatalf("failed to create client with generated service config: %s", err)
+	}
+	_ = con.Close()
+}
@@ -0,0 +1,181 @@
+// Package health provides the liveness and readiness probes for the services.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// LivePath is the path of the liveness probe.
+	LivePath = "/health/livez"
+	// ReadyPath is the path of the readiness probe.
+	ReadyPath = "/health/readyz"
+)
+
+// DefaultTimeout is the maximum time a single check may take.
+const DefaultTimeout = 2 * time.Second
+
+// Check checks if a dependency is usable.
+// A non nil error indicates that the dependency is not ready.
+type Check func(ctx context.Context) error
+
+// Result is the result of a single check.
+type Result struct {
+	Ok       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the result of running all registered checks.
+type Report struct {
+	Ok     bool              `json:"ok"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs a set of named readiness checks.
+type Checker struct {
+	mu      sync.RWMutex
+	checks  map[string]Check
+	timeout time.Duration
+}
+
+// NewChecker creates a checker with no checks.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{checks: map[string]Check{}, timeout: timeout}
+}
+
+// Add registers a check. A check with the same name is replaced.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Run runs all checks concurrently and returns the report.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Ok: true, Checks: make(map[string]Result, len(checks))}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := c.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			report.Ok = report.Ok && result.Ok
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := Result{Ok: err == nil, Duration: time.Since(start).Round(time.Microsecond).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Handler returns a handler that runs all checks and responds with the report.
+// The status is 503 if any of the checks fail.
+func (c *Checker) Handler() fiber.Handler {
+	return func(ctx fiber.Ctx) error {
+		report := c.Run(ctx.Context())
+
+		status := fiber.StatusOK
+		if !report.Ok {
+			status = fiber.StatusServiceUnavailable
+			for name, result := range report.Checks {
+				if !result.Ok {
+					zap.L().Warn("Readiness check failed", zap.String("check", name), zap.String("error", result.Error))
+				}
+			}
+		}
+
+		return ctx.Status(status).JSON(report)
+	}
+}
+
+// Default is the checker used by the package level functions.
+var Default = NewChecker(DefaultTimeout)
+
+// Add registers a check with the default checker.
+func Add(name string, check Check) {
+	Default.Add(name, check)
+}
+
+// Register registers the liveness and readiness probes.
+// The readiness probe uses the default checker.
+func Register(app *fiber.App) {
+	app.Get(LivePath, func(c fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get(ReadyPath, Default.Handler())
+}
+
+// Mongo returns a check that pings the database.
+func Mongo(client *mongo.Client) Check {
+	return func(ctx context.Context) error {
+		return client.Ping(ctx, nil)
+	}
+}
+
+// GRPC returns a check that waits for the connection to be ready.
+// Idle connections are connected before waiting.
+func GRPC(conn *grpc.ClientConn) Check {
+	return func(ctx context.Context) error {
+		for {
+			state := conn.GetState()
+			switch state {
+			case connectivity.Ready:
+				return nil
+			case connectivity.Idle:
+				conn.Connect()
+			case connectivity.Shutdown:
+				return errors.New("connection is closed")
+			}
+
+			if !conn.WaitForStateChange(ctx, state) {
+				return fmt.Errorf("connection is %s", strings.ToLower(state.String()))
+			}
+		}
+	}
+}
+
+// RegisterServer registers the grpc health service on the server.
+// This is used by clients created with grpcclient to only send calls to servers that are serving.
+func RegisterServer(server *grpc.Server) {
+	healthpb.RegisterHealthServer(server, grpchealth.NewServer())
+}
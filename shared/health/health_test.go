@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestReadiness(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("ok", func(context.Context) error { return nil })
+
+	app := fiber.New()
+	app.Get(ReadyPath, checker.Handler())
+
+	report := readyz(t, app, fiber.StatusOK)
+	if !report.Ok || !report.Checks["ok"].Ok {
+		t.Errorf("expected ok report got %+v", report)
+	}
+
+	checker.Add("failing", func(context.Context) error { return errors.New("unavailable") })
+	checker.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report = readyz(t, app, fiber.StatusServiceUnavailable)
+	if report.Ok || !report.Checks["ok"].Ok {
+		t.Errorf("expected failed report with passing ok check got %+v", report)
+	}
+	if result := report.Checks["failing"]; result.Ok || result.Error != "unavailable" {
+		t.Errorf("unexpected result for failing check %+v", result)
+	}
+	if result := report.Checks["slow"]; result.Ok || result.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("expected slow check to time out got %+v", result)
+	}
+}
+
+func readyz(t *testing.T, app *fiber.App, status int) Report {
+	t.Helper()
+
+	res, err := app.Test(httptest.NewRequest("GET", ReadyPath, nil))
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	if res.StatusCode != status {
+		t.Errorf("expected status %d got %d", status, res.StatusCode)
+	}
+
+	var report Report
+	if err := json.NewDecoder(res.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %s", err)
+	}
+	return report
+}
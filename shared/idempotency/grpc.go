@@ -0,0 +1,133 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// metadataKey is the grpc metadata key containing the idempotency key.
+const metadataKey = "idempotency-key"
+
+// ServerOption returns a server option that replays the stored response for calls to methods with a used key.
+// methods contains the full names of the methods (e.g. /OrderService/SetPaymentStatus).
+// Keys are scoped to the calling service, so the option should be used after [grpcauth.ServerOption].
+//
+// Only successful responses are stored. The key is released if the call fails.
+func (s *Store) ServerOption(methods ...string) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(s.serverInterceptor(methods))
+}
+
+func (s *Store) serverInterceptor(methods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(metadataKey)
+		if len(keys) == 0 {
+			return handler(ctx, req)
+		}
+		if !validKey(keys[0]) {
+			return nil, status.Error(codes.InvalidArgument, ErrInvalidKey.Message)
+		}
+
+		message, ok := req.(proto.Message)
+		if !ok {
+			return nil, status.Errorf(codes.Internal, "request for %s is not a proto message", info.FullMethod)
+		}
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal request: %s", err)
+		}
+
+		id := recordId{Key: keys[0], User: grpcauth.Caller(ctx)}
+		existing, err := s.begin(ctx, id, fingerprint([]byte(info.FullMethod), data))
+		if err != nil {
+			return nil, toStatus(err)
+		}
+
+		if existing != nil {
+			res, err := newResponse(info.FullMethod)
+			if err == nil {
+				err = proto.Unmarshal(existing.Body, res)
+			}
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to replay response: %s", err)
+			}
+			return res, nil
+		}
+
+		res, err := handler(ctx, req)
+		if err != nil {
+			if err := s.release(ctx, id); err != nil {
+				zap.L().Error("Failed to release idempotency key", zap.Error(err))
+			}
+			return res, err
+		}
+
+		body, err := proto.Marshal(res.(proto.Message))
+		if err == nil {
+			err = s.complete(ctx, id, 0, "", body)
+		}
+		if err != nil {
+			zap.L().Error("Failed to store idempotent response", zap.Error(err))
+		}
+		return res, nil
+	}
+}
+
+// newResponse creates an empty response message for the method using the registered proto descriptors.
+func newResponse(fullMethod string) (proto.Message, error) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, err
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(method))
+	if methodDesc == nil {
+		return nil, fmt.Errorf("service %s does not have method %s", service, method)
+	}
+
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(methodDesc.Output().FullName())
+	if err != nil {
+		return nil, err
+	}
+	return messageType.New().Interface(), nil
+}
+
+// toStatus converts the errors returned by the store to grpc status errors.
+func toStatus(err error) error {
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) {
+		return status.Errorf(codes.Internal, "failed to check idempotency key: %s", err)
+	}
+
+	switch fiberErr {
+	case ErrInProgress:
+		return status.Error(codes.Aborted, fiberErr.Message)
+	case ErrKeyReused:
+		return status.Error(codes.FailedPrecondition, fiberErr.Message)
+	default:
+		return status.Error(codes.InvalidArgument, fiberErr.Message)
+	}
+}
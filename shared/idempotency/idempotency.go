@@ -0,0 +1,162 @@
+// Package idempotency makes retried requests safe by replaying the response of the first request.
+//
+// Clients send a unique key with each request that must only be processed once.
+// The response of the first request with a key is stored, and retries with the same key receive the stored response
+// instead of being processed again. Requests with a key that is still being processed are rejected.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// MaxKeyLength is the maximum length of an idempotency key.
+const MaxKeyLength = 255
+
+var (
+	// ErrInvalidKey is returned if the idempotency key is too long or contains characters that are not printable ascii.
+	ErrInvalidKey = fiber.NewError(fiber.StatusBadRequest, "Invalid idempotency key")
+	// ErrInProgress is returned if a request with the same key is being processed.
+	ErrInProgress = fiber.NewError(fiber.StatusConflict, "A request with the same idempotency key is in progress")
+	// ErrKeyReused is returned if the key was already used for a different request.
+	ErrKeyReused = fiber.NewError(fiber.StatusUnprocessableEntity, "Idempotency key was already used for a different request")
+)
+
+// Config contains the config for the idempotency store.
+type Config struct {
+	// TTL is how long responses are stored. Retries after this are processed as new requests.
+	TTL time.Duration
+	// LockTimeout is how long a request can be processed before a retry is allowed to process it again.
+	// This allows retries if the service stopped while processing the request.
+	LockTimeout time.Duration
+}
+
+// Store stores the responses for idempotency keys.
+type Store struct {
+	records records
+	cfg     Config
+}
+
+// recordId identifies a stored response.
+// Keys are scoped to the user (or the calling service for grpc) so that a key cannot be used to read the response of another user.
+type recordId struct {
+	Key  string `bson:"k"`
+	User string `bson:"u"`
+}
+
+// record is a request that was processed or is being processed.
+type record struct {
+	Id recordId `bson:"_id"`
+	// Fingerprint is the hash of the request. It is used to detect keys reused for different requests.
+	Fingerprint string `bson:"fingerprint"`
+	// Done is true if the response was stored.
+	Done        bool      `bson:"done"`
+	LockedUntil time.Time `bson:"locked_until"`
+	ExpiresAt   time.Time `bson:"expires_at"`
+
+	Status      int    `bson:"status,omitempty"`
+	ContentType string `bson:"content_type,omitempty"`
+	Body        []byte `bson:"body,omitempty"`
+}
+
+// NewStore creates a store using the idempotency_keys collection of the database.
+// This creates the index used to remove expired responses.
+func NewStore(ctx context.Context, db *mongo.Database, cfg Config) (*Store, error) {
+	records, err := newMongoRecords(ctx, db.Collection("idempotency_keys"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{records: records, cfg: cfg}, nil
+}
+
+// begin starts processing the request with the given id.
+// If the request was already processed, the stored record is returned.
+// A nil record and error means that the caller must process the request and then call complete or release.
+func (s *Store) begin(ctx context.Context, id recordId, fingerprint string) (*record, error) {
+	now := time.Now()
+
+	// the existing record may expire between the insert and the find so retry once
+	for range 2 {
+		err := s.records.insert(ctx, &record{
+			Id:          id,
+			Fingerprint: fingerprint,
+			LockedUntil: now.Add(s.cfg.LockTimeout),
+			ExpiresAt:   now.Add(s.cfg.TTL),
+		})
+		if err == nil {
+			return nil, nil
+		} else if !errors.Is(err, errExists) {
+			return nil, err
+		}
+
+		existing, err := s.records.find(ctx, id)
+		if errors.Is(err, errNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if existing.Fingerprint != fingerprint {
+			return nil, ErrKeyReused
+		}
+		if existing.Done {
+			return existing, nil
+		}
+		if now.Before(existing.LockedUntil) {
+			return nil, ErrInProgress
+		}
+
+		// the request that held the lock did not finish in time. take over the lock.
+		locked, err := s.records.lock(ctx, id, existing.LockedUntil, now.Add(s.cfg.LockTimeout))
+		if err != nil {
+			return nil, err
+		}
+		if !locked {
+			return nil, ErrInProgress
+		}
+		return nil, nil
+	}
+
+	return nil, ErrInProgress
+}
+
+// complete stores the response of the request.
+func (s *Store) complete(ctx context.Context, id recordId, status int, contentType string, body []byte) error {
+	return s.records.complete(ctx, id, status, contentType, body)
+}
+
+// release removes the lock on a request that failed so that it can be retried.
+func (s *Store) release(ctx context.Context, id recordId) error {
+	return s.records.release(ctx, id)
+}
+
+// validKey checks if the key is not empty, is at most [MaxKeyLength] characters and only contains printable ascii characters.
+func validKey(key string) bool {
+	if len(key) == 0 || len(key) > MaxKeyLength {
+		return false
+	}
+
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// fingerprint hashes the parts of a request.
+func fingerprint(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write(part)
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
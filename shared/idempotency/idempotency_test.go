@@ -0,0 +1,317 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidKey(t *testing.T) {
+	valid := []string{"a", "order-123", "550e8400-e29b-41d4-a716-446655440000", strings.Repeat("a", MaxKeyLength)}
+	invalid := []string{"", "new\nline", "ключ", strings.Repeat("a", MaxKeyLength+1)}
+
+	for _, key := range valid {
+		if !validKey(key) {
+			t.Errorf("expected %q to be valid", key)
+		}
+	}
+	for _, key := range invalid {
+		if validKey(key) {
+			t.Errorf("expected %q to be invalid", key)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := fingerprint([]byte("POST"), []byte("/orders"), []byte(`{"a":1}`))
+	if a != fingerprint([]byte("POST"), []byte("/orders"), []byte(`{"a":1}`)) {
+		t.Error("expected the same request to have the same fingerprint")
+	}
+	if a == fingerprint([]byte("POST"), []byte("/orders"), []byte(`{"a":2}`)) {
+		t.Error("expected requests with a different body to have different fingerprints")
+	}
+	if fingerprint([]byte("ab"), []byte("c")) == fingerprint([]byte("a"), []byte("bc")) {
+		t.Error("expected the parts to be separated")
+	}
+}
+
+// memRecords stores the records in memory.
+// Values are copied like they are when encoded for mongo since fiber reuses the request and response buffers.
+type memRecords struct {
+	mu      sync.Mutex
+	records map[recordId]record
+}
+
+func newMemStore(cfg Config) *Store {
+	return &Store{records: &memRecords{records: map[recordId]record{}}, cfg: cfg}
+}
+
+func (m *memRecords) insert(_ context.Context, rec *record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := recordId{Key: strings.Clone(rec.Id.Key), User: strings.Clone(rec.Id.User)}
+	if _, ok := m.records[id]; ok {
+		return errExists
+	}
+	m.records[id] = *rec
+	return nil
+}
+
+func (m *memRecords) find(_ context.Context, id recordId) (*record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.records[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &rec, nil
+}
+
+func (m *memRecords) lock(_ context.Context, id recordId, current time.Time, until time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec, ok := m.records[id]
+	if !ok || rec.Done || !rec.LockedUntil.Equal(current) {
+		return false, nil
+	}
+	rec.LockedUntil = until
+	m.records[id] = rec
+	return true, nil
+}
+
+func (m *memRecords) complete(_ context.Context, id recordId, status int, contentType string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rec := m.records[id]
+	rec.Done, rec.Status, rec.ContentType, rec.Body = true, status, strings.Clone(contentType), bytes.Clone(body)
+	m.records[id] = rec
+	return nil
+}
+
+func (m *memRecords) release(_ context.Context, id recordId) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.records[id].Done {
+		delete(m.records, id)
+	}
+	return nil
+}
+
+func TestStoreBegin(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore(Config{TTL: time.Hour, LockTimeout: time.Hour})
+	id := recordId{Key: "key", User: "user1"}
+
+	if rec, err := store.begin(ctx, id, "a"); rec != nil || err != nil {
+		t.Fatalf("first request: expected the request to be processed got %v %v", rec, err)
+	}
+	if _, err := store.begin(ctx, id, "a"); !errors.Is(err, ErrInProgress) {
+		t.Errorf("retry while processing: expected error %v got %v", ErrInProgress, err)
+	}
+	if _, err := store.begin(ctx, id, "b"); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("different request: expected error %v got %v", ErrKeyReused, err)
+	}
+	if rec, err := store.begin(ctx, recordId{Key: "key", User: "user2"}, "a"); rec != nil || err != nil {
+		t.Errorf("other user: expected the request to be processed got %v %v", rec, err)
+	}
+
+	if err := store.complete(ctx, id, fiber.StatusCreated, fiber.MIMEApplicationJSON, []byte(`{"id":1}`)); err != nil {
+		t.Fatalf("failed to complete request: %s", err)
+	}
+
+	rec, err := store.begin(ctx, id, "a")
+	if err != nil || rec == nil {
+		t.Fatalf("retry after completing: expected the stored response got %v %v", rec, err)
+	}
+	if rec.Status != fiber.StatusCreated || rec.ContentType != fiber.MIMEApplicationJSON || string(rec.Body) != `{"id":1}` {
+		t.Errorf("retry after completing: expected the stored response got %d %s %s", rec.Status, rec.ContentType, rec.Body)
+	}
+	if _, err := store.begin(ctx, id, "b"); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("different request after completing: expected error %v got %v", ErrKeyReused, err)
+	}
+
+	// a completed response is not removed by release
+	if err := store.release(ctx, id); err != nil {
+		t.Fatalf("failed to release request: %s", err)
+	}
+	if rec, err := store.begin(ctx, id, "a"); rec == nil || err != nil {
+		t.Errorf("retry after release: expected the stored response got %v %v", rec, err)
+	}
+}
+
+func TestStoreRelease(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore(Config{TTL: time.Hour, LockTimeout: time.Hour})
+	id := recordId{Key: "key"}
+
+	if _, err := store.begin(ctx, id, "a"); err != nil {
+		t.Fatalf("failed to begin request: %s", err)
+	}
+	if err := store.release(ctx, id); err != nil {
+		t.Fatalf("failed to release request: %s", err)
+	}
+
+	// the key can be used for any request after it is released
+	if rec, err := store.begin(ctx, id, "b"); rec != nil || err != nil {
+		t.Errorf("expected the request to be processed got %v %v", rec, err)
+	}
+}
+
+func TestStoreLockTakeover(t *testing.T) {
+	ctx := context.Background()
+	// the lock expires immediately
+	store := newMemStore(Config{TTL: time.Hour})
+	id := recordId{Key: "key"}
+
+	if _, err := store.begin(ctx, id, "a"); err != nil {
+		t.Fatalf("failed to begin request: %s", err)
+	}
+	if rec, err := store.begin(ctx, id, "a"); rec != nil || err != nil {
+		t.Errorf("retry after lock timeout: expected the request to be processed got %v %v", rec, err)
+	}
+	if _, err := store.begin(ctx, id, "b"); !errors.Is(err, ErrKeyReused) {
+		t.Errorf("different request after lock timeout: expected error %v got %v", ErrKeyReused, err)
+	}
+
+	// another request took over the lock after it was read
+	records := store.records.(*memRecords)
+	if locked, err := records.lock(ctx, id, time.Time{}, time.Now()); locked || err != nil {
+		t.Errorf("expected the lock to fail if it was changed got %v %v", locked, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	store := newMemStore(Config{TTL: time.Hour, LockTimeout: time.Hour})
+
+	calls := 0
+	app := fiber.New()
+	app.Use(store.Middleware())
+	app.Post("/orders", func(c fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+	app.Post("/fail", func(c fiber.Ctx) error {
+		calls++
+		return fiber.ErrInternalServerError
+	})
+
+	send := func(path string, key string, body string) (int, string, string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(Header, key)
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		data, _ := io.ReadAll(res.Body)
+		return res.StatusCode, res.Header.Get(ReplayedHeader), string(data)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		key      string
+		body     string
+		status   int
+		replayed bool
+		response string
+		calls    int
+	}{
+		{name: "first request", path: "/orders", key: "a", body: "1", status: fiber.StatusCreated, response: `{"call":1}`, calls: 1},
+		{name: "retry", path: "/orders", key: "a", body: "1", status: fiber.StatusCreated, replayed: true, response: `{"call":1}`, calls: 1},
+		{name: "reused key", path: "/orders", key: "a", body: "2", status: fiber.StatusUnprocessableEntity, calls: 1},
+		{name: "new key", path: "/orders", key: "b", body: "2", status: fiber.StatusCreated, response: `{"call":2}`, calls: 2},
+		{name: "server error", path: "/fail", key: "c", status: fiber.StatusInternalServerError, calls: 3},
+		{name: "retry after server error", path: "/fail", key: "c", status: fiber.StatusInternalServerError, calls: 4},
+	}
+
+	for _, test := range tests {
+		status, replayed, body := send(test.path, test.key, test.body)
+		if status != test.status {
+			t.Errorf("%s: expected status %d got %d", test.name, test.status, status)
+		}
+		if (replayed == "true") != test.replayed {
+			t.Errorf("%s: expected replayed %v got %q", test.name, test.replayed, replayed)
+		}
+		if test.response != "" && body != test.response {
+			t.Errorf("%s: expected response %s got %s", test.name, test.response, body)
+		}
+		if calls != test.calls {
+			t.Errorf("%s: expected %d calls got %d", test.name, test.calls, calls)
+		}
+	}
+}
+
+func TestMiddlewareSkip(t *testing.T) {
+	// the store is not used for these requests
+	store := &Store{}
+
+	app := fiber.New()
+	app.Use(store.Middleware())
+	app.All("/", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	tests := []struct {
+		method string
+		key    string
+		status int
+	}{
+		{fiber.MethodPost, "", fiber.StatusOK},
+		{fiber.MethodGet, "key", fiber.StatusOK},
+		{fiber.MethodPost, strings.Repeat("a", MaxKeyLength+1), fiber.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, "/", nil)
+		if test.key != "" {
+			req.Header.Set(Header, test.key)
+		}
+
+		res, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		if res.StatusCode != test.status {
+			t.Errorf("%s with key %.10q: expected status %d got %d", test.method, test.key, test.status, res.StatusCode)
+		}
+	}
+}
+
+func TestNewResponse(t *testing.T) {
+	res, err := newResponse(healthpb.Health_Check_FullMethodName)
+	if err != nil {
+		t.Fatalf("failed to create response: %s", err)
+	}
+	if _, ok := res.(*healthpb.HealthCheckResponse); !ok {
+		t.Errorf("expected a health check response got %T", res)
+	}
+
+	if _, err := newResponse("/grpc.health.v1.Health/Unknown"); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	tests := map[error]codes.Code{
+		ErrInProgress:           codes.Aborted,
+		ErrKeyReused:            codes.FailedPrecondition,
+		ErrInvalidKey:           codes.InvalidArgument,
+		errors.New("timed out"): codes.Internal,
+	}
+
+	for err, code := range tests {
+		if got := status.Code(toStatus(err)); got != code {
+			t.Errorf("%s: expected code %s got %s", err, code, got)
+		}
+	}
+}
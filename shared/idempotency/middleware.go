@@ -0,0 +1,82 @@
+package idempotency
+
+import (
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// Header is the request header containing the idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set on responses that were replayed from a previous request.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// Middleware returns a middleware that replays the stored response for requests with a used [Header].
+// Requests without the header and GET, HEAD and OPTIONS requests are processed normally.
+// Keys are scoped to the authenticated user, so the middleware should be used after the auth middleware.
+//
+// Responses with a status below 500 are stored. If the request fails with a server error, the key is released
+// so that the request can be retried.
+func (s *Store) Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		key := c.Get(Header)
+		if key == "" {
+			return c.Next()
+		}
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		if !validKey(key) {
+			return ErrInvalidKey
+		}
+
+		id := recordId{Key: key}
+		if user := auth.GetUser(c); user != nil {
+			id.User = user.UserId
+		}
+
+		existing, err := s.begin(c.Context(), id, fingerprint([]byte(c.Method()), []byte(c.OriginalURL()), c.Body()))
+		if err != nil {
+			return err
+		}
+
+		if existing != nil {
+			c.Set(ReplayedHeader, "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			return c.Status(existing.Status).Send(existing.Body)
+		}
+
+		// errors are handled here so that the error response is stored
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				s.releaseKey(c, id)
+				return err
+			}
+		}
+
+		if c.Response().StatusCode() >= fiber.StatusInternalServerError {
+			s.releaseKey(c, id)
+			return nil
+		}
+
+		err = s.complete(c.Context(), id, c.Response().StatusCode(), string(c.Response().Header.ContentType()), c.Response().Body())
+		if err != nil {
+			requestlog.L(c.Context()).Error("Failed to store idempotent response", zap.Error(err))
+		}
+		return nil
+	}
+}
+
+func (s *Store) releaseKey(c fiber.Ctx, id recordId) {
+	if err := s.release(c.Context(), id); err != nil {
+		requestlog.L(c.Context()).Error("Failed to release idempotency key", zap.Error(err))
+	}
+}
@@ -0,0 +1,96 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+var (
+	// errExists is returned by records.insert if a record with the same id exists.
+	errExists = errors.New("record exists")
+	// errNotFound is returned by records.find if the record does not exist.
+	errNotFound = errors.New("record not found")
+)
+
+// records stores the idempotency records.
+type records interface {
+	// insert adds a new record.
+	insert(ctx context.Context, rec *record) error
+	// find gets the record with the given id.
+	find(ctx context.Context, id recordId) (*record, error)
+	// lock sets the lock of a request that is not done if the lock was not changed since it was read.
+	// This returns false if another request changed the lock.
+	lock(ctx context.Context, id recordId, current time.Time, until time.Time) (bool, error)
+	// complete stores the response of the request.
+	complete(ctx context.Context, id recordId, status int, contentType string, body []byte) error
+	// release deletes the record if the request is not done.
+	release(ctx context.Context, id recordId) error
+}
+
+// mongoRecords stores the records in a mongo collection.
+type mongoRecords struct {
+	collection *mongo.Collection
+}
+
+// newMongoRecords creates the index used to remove expired records in the collection.
+func newMongoRecords(ctx context.Context, collection *mongo.Collection) (*mongoRecords, error) {
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &mongoRecords{collection: collection}, nil
+}
+
+func (m *mongoRecords) insert(ctx context.Context, rec *record) error {
+	_, err := m.collection.InsertOne(ctx, rec)
+	if mongo.IsDuplicateKeyError(err) {
+		return errExists
+	}
+	return err
+}
+
+func (m *mongoRecords) find(ctx context.Context, id recordId) (*record, error) {
+	var rec record
+	err := m.collection.FindOne(ctx, bson.D{{Key: "_id", Value: id}}).Decode(&rec)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, errNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (m *mongoRecords) lock(ctx context.Context, id recordId, current time.Time, until time.Time) (bool, error) {
+	result, err := m.collection.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: id}, {Key: "done", Value: false}, {Key: "locked_until", Value: current}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "locked_until", Value: until}}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (m *mongoRecords) complete(ctx context.Context, id recordId, status int, contentType string, body []byte) error {
+	_, err := m.collection.UpdateOne(ctx, bson.D{{Key: "_id", Value: id}}, bson.D{{Key: "$set", Value: bson.D{
+		{Key: "done", Value: true},
+		{Key: "status", Value: status},
+		{Key: "content_type", Value: contentType},
+		{Key: "body", Value: body},
+	}}})
+	return err
+}
+
+func (m *mongoRecords) release(ctx context.Context, id recordId) error {
+	_, err := m.collection.DeleteOne(ctx, bson.D{{Key: "_id", Value: id}, {Key: "done", Value: false}})
+	return err
+}
@@ -0,0 +1,36 @@
+package location
+
+import "math"
+
+// earthRadiusKm is the mean radius of the earth in kilometers.
+const earthRadiusKm = 6371.0
+
+func toRad(deg float64) float64 { return deg * math.Pi / 180 }
+
+// DistanceKm returns the great-circle distance between two coordinates in kilometers.
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// BearingDeg returns the initial bearing from the first coordinate to the second in degrees.
+// The result is in the range [0, 360) where 0 is north and 90 is east.
+func BearingDeg(lat1, lng1, lat2, lng2 float64) float64 {
+	dLng := toRad(lng2 - lng1)
+
+	y := math.Sin(dLng) * math.Cos(toRad(lat2))
+	x := math.Cos(toRad(lat1))*math.Sin(toRad(lat2)) - math.Sin(toRad(lat1))*math.Cos(toRad(lat2))*math.Cos(dLng)
+
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// AngleDiffDeg returns the smallest difference between two bearings in degrees.
+func AngleDiffDeg(a, b float64) float64 {
+	diff := math.Mod(math.Abs(a-b), 360)
+	return math.Min(diff, 360-diff)
+}
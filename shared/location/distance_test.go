@@ -0,0 +1,62 @@
+package location
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceKm(t *testing.T) {
+	// one degree of latitude is about 111.2km
+	if got := DistanceKm(6.0, 79.8, 7.0, 79.8); math.Abs(got-111.2) > 0.1 {
+		t.Errorf("expected about 111.2km got %v", got)
+	}
+	if got := DistanceKm(6.9, 79.8, 6.9, 79.8); got != 0 {
+		t.Errorf("expected 0 for the same point got %v", got)
+	}
+}
+
+func TestBearingDeg(t *testing.T) {
+	tests := []struct {
+		name      string
+		lat, lng  float64
+		expected  float64
+		tolerance float64
+	}{
+		{"north", 7.0, 79.8, 0, 0.01},
+		{"east", 6.9, 79.9, 90, 0.01},
+		{"south", 6.8, 79.8, 180, 0.01},
+		{"west", 6.9, 79.7, 270, 0.01},
+		{"north east", 7.0, 79.9, 45, 0.5},
+	}
+
+	for _, test := range tests {
+		got := BearingDeg(6.9, 79.8, test.lat, test.lng)
+		if math.Abs(got-test.expected) > test.tolerance {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+		if got < 0 || got >= 360 {
+			t.Errorf("%s: bearing %v is out of range", test.name, got)
+		}
+	}
+}
+
+func TestAngleDiffDeg(t *testing.T) {
+	tests := []struct {
+		a, b     float64
+		expected float64
+	}{
+		{0, 0, 0},
+		{90, 45, 45},
+		{45, 90, 45},
+		{0, 180, 180},
+		{10, 350, 20},
+		{350, 10, 20},
+		{270, 90, 180},
+	}
+
+	for _, test := range tests {
+		if got := AngleDiffDeg(test.a, test.b); math.Abs(got-test.expected) > 1e-9 {
+			t.Errorf("AngleDiffDeg(%v, %v): expected %v got %v", test.a, test.b, test.expected, got)
+		}
+	}
+}
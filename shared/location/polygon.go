@@ -0,0 +1,63 @@
+package location
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLatLng is returned when a coordinate pair cannot be parsed.
+var ErrInvalidLatLng = errors.New("invalid latitude and longitude")
+
+// PointInRing checks if the coordinate is inside a closed ring of [longitude, latitude] positions using ray casting.
+// Points on the edge of the ring may be reported as inside or outside.
+func PointInRing(lat, lng float64, ring [][2]float64) bool {
+	inside := false
+
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i][0], ring[i][1]
+		xj, yj := ring[j][0], ring[j][1]
+
+		if (yi > lat) != (yj > lat) && lng < (xj-xi)*(lat-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// PointInPolygon checks if the coordinate is inside a GeoJSON polygon.
+// The first ring is the outer boundary and the other rings are holes.
+func PointInPolygon(lat, lng float64, rings [][][2]float64) bool {
+	if len(rings) == 0 || !PointInRing(lat, lng, rings[0]) {
+		return false
+	}
+
+	for _, hole := range rings[1:] {
+		if PointInRing(lat, lng, hole) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseLatLng parses a "latitude,longitude" pair.
+func ParseLatLng(value string) (lat, lng float64, err error) {
+	latStr, lngStr, ok := strings.Cut(value, ",")
+	if !ok {
+		return 0, 0, ErrInvalidLatLng
+	}
+
+	lat, err = strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, ErrInvalidLatLng
+	}
+
+	lng, err = strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return 0, 0, ErrInvalidLatLng
+	}
+
+	return lat, lng, nil
+}
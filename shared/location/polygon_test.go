@@ -0,0 +1,45 @@
+package location
+
+import "testing"
+
+func TestPointInPolygon(t *testing.T) {
+	// square around Colombo with a hole in the middle
+	polygon := [][][2]float64{
+		{{79.8, 6.8}, {80.0, 6.8}, {80.0, 7.0}, {79.8, 7.0}, {79.8, 6.8}},
+		{{79.88, 6.88}, {79.92, 6.88}, {79.92, 6.92}, {79.88, 6.92}, {79.88, 6.88}},
+	}
+
+	tests := []struct {
+		name     string
+		lat, lng float64
+		expected bool
+	}{
+		{"inside", 6.85, 79.85, true},
+		{"outside", 7.1, 79.85, false},
+		{"in hole", 6.9, 79.9, false},
+		{"latitude and longitude swapped", 79.85, 6.85, false},
+	}
+
+	for _, test := range tests {
+		if got := PointInPolygon(test.lat, test.lng, polygon); got != test.expected {
+			t.Errorf("%s: expected %v got %v", test.name, test.expected, got)
+		}
+	}
+
+	if PointInPolygon(6.85, 79.85, nil) {
+		t.Errorf("empty polygon should not contain any points")
+	}
+}
+
+func TestParseLatLng(t *testing.T) {
+	lat, lng, err := ParseLatLng("6.9271, 79.8612")
+	if err != nil || lat != 6.9271 || lng != 79.8612 {
+		t.Errorf("expected 6.9271,79.8612 got %v,%v (%v)", lat, lng, err)
+	}
+
+	for _, value := range []string{"", "6.9", "a,b", "91,0", "0,181"} {
+		if _, _, err := ParseLatLng(value); err == nil {
+			t.Errorf("%q: expected an error", value)
+		}
+	}
+}
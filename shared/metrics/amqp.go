@@ -0,0 +1,20 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var amqpPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "amqp_published_total",
+	Help: "Number of messages published to RabbitMQ by queue and result.",
+}, []string{"queue", "result"})
+
+// ObservePublish records the result of publishing a message to the queue.
+func ObservePublish(queue string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	amqpPublished.WithLabelValues(queue, result).Inc()
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcServerHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "Number of gRPC calls handled by the server by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcServerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "Time taken to handle gRPC calls by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	grpcClientHandled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_client_handled_total",
+		Help: "Number of gRPC calls made to other services by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcClientDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_client_handling_seconds",
+		Help:    "Time taken by gRPC calls made to other services by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+)
+
+// ServerOption returns the grpc server option that records metrics for each call.
+func ServerOption() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		grpcServerHandled.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+		grpcServerDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+
+		return res, err
+	})
+}
+
+// DialOption returns the grpc client option that records metrics for each call.
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		grpcClientHandled.WithLabelValues(method, status.Code(err).String()).Inc()
+		grpcClientDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+
+		return err
+	})
+}
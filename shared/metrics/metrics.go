@@ -0,0 +1,67 @@
+// Package metrics exposes Prometheus metrics for the services.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path is the path that metrics are served on.
+const Path = "/metrics"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests handled by route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Register registers the request metrics middleware and the metrics endpoint.
+// This should be called before any authentication middleware is registered.
+func Register(app *fiber.App) {
+	app.Get(Path, Handler())
+	app.Use(Middleware())
+}
+
+// Handler returns the handler that serves the metrics in the Prometheus text format.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.Handler())
+}
+
+// Middleware records the number of requests and the time taken to handle them.
+// Requests are grouped by the route pattern to keep the number of series bounded.
+func Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		route := c.Route().Path
+		httpRequests.WithLabelValues(c.Method(), route, strconv.Itoa(statusCode(c, err))).Inc()
+		httpDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
+
+// statusCode returns the status code that will be sent for the request.
+// Errors are converted to responses by the error handler after the middleware returns.
+func statusCode(c fiber.Ctx, err error) int {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		return fiberErr.Code
+	} else if err != nil {
+		return fiber.StatusInternalServerError
+	}
+	return c.Response().StatusCode()
+}
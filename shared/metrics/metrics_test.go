@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestMetrics(t *testing.T) {
+	app := fiber.New()
+	Register(app)
+	app.Get("/items/:id", func(c fiber.Ctx) error {
+		return fiber.ErrNotFound
+	})
+
+	for _, id := range []string{"1", "2"} {
+		if _, err := app.Test(httptest.NewRequest("GET", "/items/"+id, nil)); err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+	}
+
+	res, err := app.Test(httptest.NewRequest("GET", Path, nil))
+	if err != nil {
+		t.Fatalf("metrics request failed: %s", err)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("failed to read metrics: %s", err)
+	}
+
+	expected := `http_requests_total{method="GET",route="/items/:id",status="404"} 2`
+	if !strings.Contains(string(body), expected) {
+		t.Errorf("expected metrics to contain %s got:\n%s", expected, body)
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+var (
+	mongoOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections",
+		Help: "Number of open connections in the mongo connection pool.",
+	})
+
+	mongoInUse = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "mongo_pool_connections_in_use",
+		Help: "Number of connections checked out from the mongo connection pool.",
+	})
+
+	mongoCheckoutFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_pool_checkout_failed_total",
+		Help: "Number of failed attempts to check out a connection from the mongo connection pool by reason.",
+	}, []string{"reason"})
+)
+
+// PoolMonitor returns a mongo pool monitor that records the connection pool stats.
+func PoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				mongoOpen.Inc()
+			case event.ConnectionClosed:
+				mongoOpen.Dec()
+			case event.ConnectionCheckedOut:
+				mongoInUse.Inc()
+			case event.ConnectionCheckedIn:
+				mongoInUse.Dec()
+			case event.ConnectionCheckOutFailed:
+				mongoCheckoutFailed.WithLabelValues(evt.Reason).Inc()
+			}
+		},
+	}
+}
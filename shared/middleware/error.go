@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
 	"github.com/gofiber/fiber/v3"
 	"go.uber.org/zap"
 )
@@ -32,20 +33,22 @@ func ErrorHandler(logger ...*zap.Logger) fiber.ErrorHandler {
 
 	return func(ctx fiber.Ctx, err error) error {
 		if err != nil {
+			log := requestlog.Logger(ctx.Context(), getLogger())
+
 			// If the error is a fiber.Error, it is an api error that should be sent to the client.
 			var fiberError *fiber.Error
 			if errors.As(err, &fiberError) {
-				getLogger().Warn("Request returned error", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
+				log.Warn("Request returned error", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
 				return ctx.Status(fiberError.Code).JSON(dto.Error(fiberError.Message))
 			}
 
 			// If the error is a validation error, send a bad request error with the validation error details
 			if verr, ok := err.(validationError); ok {
-				getLogger().Warn("Invalid request from client", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
+				log.Warn("Invalid request from client", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
 				return ctx.Status(fiber.StatusBadRequest).JSON(dto.Error(verr.Error(), verr.ValidationErrors()))
 			}
 
-			getLogger().Error("Error occurred while handling request", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
+			log.Error("Error occurred while handling request", zap.Error(err), zap.String("path", string(ctx.Request().URI().Path())))
 			return ctx.Status(fiber.StatusInternalServerError).JSON(dto.Error("An internal error occurred while handling the request"))
 		}
 		return nil
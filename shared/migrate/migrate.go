@@ -0,0 +1,232 @@
+// Package migrate applies versioned changes such as indexes and backfills to a mongo database.
+//
+// Each service has an ordered list of migrations. The version of every applied migration is recorded in the
+// migrations collection and only migrations that were not applied are run.
+//
+// Migrations must be idempotent. A migration is run again if the service stops before the version is recorded,
+// and several instances of a service may run the same migration when they start at the same time.
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.uber.org/zap"
+)
+
+// Collection is the collection containing the applied migrations.
+const Collection = "migrations"
+
+// ErrVersionOrder is returned if the migration versions are not unique and in ascending order.
+var ErrVersionOrder = errors.New("migration versions must be unique and in ascending order")
+
+// Step changes the database.
+type Step func(ctx context.Context, db *mongo.Database) error
+
+// Migration is a versioned change to the database.
+type Migration struct {
+	// Version identifies the migration. Versions must not be changed once the migration is released.
+	Version int
+	// Description describes the changes made by the migration.
+	Description string
+	// Up applies the migration.
+	Up Step
+}
+
+// Record is an applied migration.
+type Record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+// Applied returns the migrations that were applied to the database ordered by version.
+func Applied(ctx context.Context, db *mongo.Database) ([]Record, error) {
+	cursor, err := db.Collection(Collection).Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Pending returns the migrations that were not applied to the database.
+func Pending(ctx context.Context, db *mongo.Database, migrations []Migration) ([]Migration, error) {
+	if err := validate(migrations); err != nil {
+		return nil, err
+	}
+
+	records, err := Applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, record := range records {
+		applied[record.Version] = true
+	}
+
+	pending := []Migration{}
+	for _, migration := range migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// Run applies the pending migrations in order of version and returns the number of applied migrations.
+// Run stops at the first migration that fails. The migrations before it stay applied.
+func Run(ctx context.Context, db *mongo.Database, migrations []Migration) (int, error) {
+	pending, err := Pending(ctx, db, migrations)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, migration := range pending {
+		zap.L().Info("Applying migration", zap.Int("version", migration.Version), zap.String("description", migration.Description))
+
+		if err := migration.Up(ctx, db); err != nil {
+			return i, fmt.Errorf("migration %d failed: %w", migration.Version, err)
+		}
+
+		_, err := db.Collection(Collection).InsertOne(ctx, Record{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()})
+		// another instance applied the migration at the same time
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return i, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+	}
+
+	return len(pending), nil
+}
+
+// Command runs the migrate command.
+// With no arguments or "up", the pending migrations are applied. "status" lists all migrations and when they were applied.
+func Command(ctx context.Context, db *mongo.Database, migrations []Migration, args []string, out io.Writer) error {
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		count, err := Run(ctx, db, migrations)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Applied %d migrations\n", count)
+		return nil
+
+	case "status":
+		if err := validate(migrations); err != nil {
+			return err
+		}
+		records, err := Applied(ctx, db)
+		if err != nil {
+			return err
+		}
+
+		applied := make(map[int]Record, len(records))
+		for _, record := range records {
+			applied[record.Version] = record
+		}
+		for _, migration := range migrations {
+			status := "pending"
+			if record, ok := applied[migration.Version]; ok {
+				status = "applied " + record.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(out, "%4d  %-28s  %s\n", migration.Version, status, migration.Description)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up or status", command)
+	}
+}
+
+// Startup runs the migrate command if the service was started with it (e.g. order-service migrate status),
+// and returns true so that the service can exit once the command completes.
+// Otherwise, the pending migrations are applied if apply is true.
+func Startup(ctx context.Context, db *mongo.Database, migrations []Migration, apply bool) (exit bool, err error) {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		return true, Command(ctx, db, migrations, os.Args[2:], os.Stdout)
+	}
+
+	if !apply {
+		return false, nil
+	}
+
+	count, err := Run(ctx, db, migrations)
+	if count > 0 {
+		zap.L().Info("Applied migrations", zap.Int("count", count))
+	}
+	return false, err
+}
+
+// validate checks that the versions are unique and in ascending order.
+func validate(migrations []Migration) error {
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version <= migrations[i-1].Version {
+			return fmt.Errorf("%w: %d after %d", ErrVersionOrder, migrations[i].Version, migrations[i-1].Version)
+		}
+	}
+	return nil
+}
+
+// Steps returns a step that runs the steps in order.
+func Steps(steps ...Step) Step {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, step := range steps {
+			if err := step(ctx, db); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// CreateIndexes returns a step that creates the indexes on the collection.
+// Creating an index that already exists with the same options does nothing.
+func CreateIndexes(collection string, indexes ...mongo.IndexModel) Step {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes)
+		if err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", collection, err)
+		}
+		return nil
+	}
+}
+
+// Index creates an index on the fields. Prefix a field with - to index it in descending order.
+func Index(fields ...string) mongo.IndexModel {
+	keys := bson.D{}
+	for _, field := range fields {
+		if name, desc := strings.CutPrefix(field, "-"); desc {
+			keys = append(keys, bson.E{Key: name, Value: -1})
+		} else {
+			keys = append(keys, bson.E{Key: field, Value: 1})
+		}
+	}
+
+	return mongo.IndexModel{Keys: keys}
+}
+
+// TTLIndex creates an index that removes documents once the time in field is older than expireAfter.
+func TTLIndex(field string, expireAfter time.Duration) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(expireAfter.Seconds())),
+	}
+}
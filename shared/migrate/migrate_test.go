@@ -0,0 +1,87 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestValidate(t *testing.T) {
+	if err := validate([]Migration{{Version: 1}, {Version: 2}, {Version: 5}}); err != nil {
+		t.Errorf("expected ascending versions to be valid got %s", err)
+	}
+
+	for _, versions := range [][]int{{1, 1}, {2, 1}, {1, 3, 2}} {
+		migrations := []Migration{}
+		for _, version := range versions {
+			migrations = append(migrations, Migration{Version: version})
+		}
+
+		if err := validate(migrations); !errors.Is(err, ErrVersionOrder) {
+			t.Errorf("%v: expected ErrVersionOrder got %v", versions, err)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := Index("user_id", "-created_at")
+
+	want := bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}
+	keys, ok := index.Keys.(bson.D)
+	if !ok || len(keys) != len(want) {
+		t.Fatalf("expected keys %v got %v", want, index.Keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Errorf("expected key %v got %v", want[i], keys[i])
+		}
+	}
+}
+
+func TestTTLIndex(t *testing.T) {
+	index := TTLIndex("expires_at", time.Hour)
+
+	var opts options.IndexOptions
+	for _, set := range index.Options.List() {
+		if err := set(&opts); err != nil {
+			t.Fatalf("failed to apply index options: %s", err)
+		}
+	}
+	if opts.ExpireAfterSeconds == nil || *opts.ExpireAfterSeconds != 3600 {
+		t.Errorf("expected documents to expire after 3600 seconds got %v", opts.ExpireAfterSeconds)
+	}
+}
+
+func TestSteps(t *testing.T) {
+	var order []int
+	step := func(i int) Step {
+		return func(context.Context, *mongo.Database) error {
+			order = append(order, i)
+			if i == 2 {
+				return errors.New("failed")
+			}
+			return nil
+		}
+	}
+
+	err := Steps(step(1), step(2), step(3))(context.Background(), nil)
+	if err == nil {
+		t.Error("expected the error of the failed step")
+	}
+	if len(order) != 2 || order[0] != 1 || order[1] != 2 {
+		t.Errorf("expected steps to run in order until the failed step got %v", order)
+	}
+}
+
+func TestCommandUnknown(t *testing.T) {
+	var out bytes.Buffer
+	if err := Command(context.Background(), nil, nil, []string{"down"}, &out); err == nil {
+		t.Error("expected an error for an unknown command")
+	}
+}
@@ -0,0 +1,110 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MarshalJSON encodes the amount as a decimal number in major units.
+// The currency is not included so that the JSON format stays compatible with plain number prices.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a decimal number in major units or an object containing the amount in minor units.
+// Numbers in exponent form (e.g. 1.5e2) are accepted. null leaves the value unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	if bytes.HasPrefix(data, []byte("{")) {
+		var v struct {
+			Amount   int64    `json:"amount"`
+			Currency Currency `json:"currency"`
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*m = New(v.Amount, v.Currency)
+		return nil
+	}
+
+	parsed, err := Parse(expandExponent(string(bytes.Trim(data, `"`))), m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalBSONValue decodes a money document.
+// Numbers are also accepted so that prices stored before amounts were stored in minor units can still be read.
+// These are always in major units: prices were stored as doubles by the services, and as integers when whole
+// numbers were inserted by the mongo shell or seed scripts. Integers are never treated as minor units.
+func (m *Money) UnmarshalBSONValue(typ byte, data []byte) error {
+	raw := bson.RawValue{Type: bson.Type(typ), Value: data}
+
+	switch raw.Type {
+	case bson.TypeEmbeddedDocument:
+		type t Money
+		var v t
+		if err := raw.Unmarshal(&v); err != nil {
+			return err
+		}
+		*m = New(v.Amount, v.Currency)
+	case bson.TypeDouble:
+		*m = FromFloat(raw.Double(), DefaultCurrency)
+	case bson.TypeInt32:
+		*m = FromFloat(float64(raw.Int32()), DefaultCurrency)
+	case bson.TypeInt64:
+		*m = FromFloat(float64(raw.Int64()), DefaultCurrency)
+	case bson.TypeNull, bson.TypeUndefined:
+		*m = Money{}
+	default:
+		return fmt.Errorf("cannot decode %s into money", raw.Type)
+	}
+
+	return nil
+}
+
+// expandExponent rewrites a number in exponent form (e.g. 1.5e2) as a decimal number (150.) so that it can be parsed
+// without converting it to a float. Other values are returned unchanged.
+func expandExponent(s string) string {
+	i := strings.IndexAny(s, "eE")
+	if i < 0 {
+		return s
+	}
+
+	// larger exponents cannot be represented and are rejected by Parse
+	exp, err := strconv.Atoi(s[i+1:])
+	if err != nil || exp > 18 || exp < -18 {
+		return s
+	}
+
+	num, sign := s[:i], ""
+	if strings.HasPrefix(num, "-") {
+		num, sign = num[1:], "-"
+	}
+
+	whole, frac, _ := strings.Cut(num, ".")
+	digits := whole + frac
+	point := len(whole) + exp
+
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+
+	return sign + digits[:point] + "." + digits[point:]
+}
@@ -0,0 +1,226 @@
+// Package money contains a fixed point money type used for prices, discounts and fees.
+//
+// Amounts are stored as an integer number of minor units (cents) with a currency code so that
+// adding prices together never introduces floating point errors.
+//
+// Rounding rules:
+//   - Converting a decimal value to [Money] rounds to the nearest minor unit, with ties rounded away from zero.
+//   - Percentages (discounts, fees, taxes) are calculated once on the total amount, not on each item,
+//     and are rounded to the nearest minor unit with ties rounded away from zero.
+//   - A discount is calculated by rounding the discount amount and subtracting it from the original amount,
+//     so that the discount and the discounted total always add up to the original amount.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned when combining amounts with different currencies.
+var ErrCurrencyMismatch = errors.New("currency mismatch")
+
+// ErrInvalidAmount is returned when parsing an invalid decimal amount.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Currency is an ISO 4217 currency code.
+type Currency string
+
+const (
+	LKR Currency = "LKR"
+	USD Currency = "USD"
+)
+
+// DefaultCurrency is the currency used when a currency is not specified.
+const DefaultCurrency = LKR
+
+// exponents contains the number of minor unit digits for currencies that do not use 2 digits.
+var exponents = map[Currency]int{
+	"JPY": 0,
+	"KRW": 0,
+	"BHD": 3,
+	"KWD": 3,
+	"OMR": 3,
+}
+
+// Exponent returns the number of digits after the decimal point for the currency.
+func (c Currency) Exponent() int {
+	if exp, ok := exponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+// orDefault returns the currency or the default currency if the currency is empty.
+func (c Currency) orDefault() Currency {
+	if c == "" {
+		return DefaultCurrency
+	}
+	return c
+}
+
+// Money is an amount of money in minor units of the currency.
+type Money struct {
+	// Amount is the amount in minor units (e.g. cents).
+	Amount   int64    `bson:"amount"`
+	Currency Currency `bson:"currency"`
+}
+
+// New creates a new Money value from an amount in minor units.
+func New(minor int64, currency Currency) Money {
+	return Money{Amount: minor, Currency: currency.orDefault()}
+}
+
+// Zero returns a zero amount in the given currency.
+func Zero(currency Currency) Money {
+	return New(0, currency)
+}
+
+// FromFloat converts an amount in major units to Money.
+// The value is rounded to the nearest minor unit with ties rounded away from zero.
+func FromFloat(major float64, currency Currency) Money {
+	// the shortest decimal representation is used so that values such as 1.005 round as written.
+	m, err := Parse(strconv.FormatFloat(major, 'f', -1, 64), currency)
+	if err != nil {
+		// NaN and Inf cannot be represented
+		return Zero(currency)
+	}
+	return m
+}
+
+// Parse parses a decimal amount in major units such as "1250.50".
+// The value is rounded to the nearest minor unit with ties rounded away from zero.
+func Parse(s string, currency Currency) (Money, error) {
+	currency = currency.orDefault()
+	exp := currency.Exponent()
+
+	s = strings.TrimSpace(s)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if (whole == "" && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	// pad the fraction to the number of minor digits, keeping the next digit for rounding.
+	frac += strings.Repeat("0", exp+1)
+
+	amount, err := strconv.ParseInt(whole+frac[:exp], 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	if frac[exp] >= '5' {
+		amount++
+	}
+	if neg {
+		amount = -amount
+	}
+
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Float returns the amount in major units.
+// This should only be used for display purposes or for clients that do not support minor units.
+func (m Money) Float() float64 {
+	return float64(m.Amount) / math.Pow10(m.Currency.orDefault().Exponent())
+}
+
+// String returns the amount in major units as a decimal string without the currency.
+func (m Money) String() string {
+	exp := m.Currency.orDefault().Exponent()
+
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	if exp == 0 {
+		return sign + strconv.FormatInt(amount, 10)
+	}
+
+	digits := fmt.Sprintf("%0*d", exp+1, amount)
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// IsZero returns true if the amount is zero.
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Equal returns true if both amounts have the same value and currency.
+func (m Money) Equal(o Money) bool {
+	return m.Amount == o.Amount && m.Currency.orDefault() == o.Currency.orDefault()
+}
+
+// Add adds the given amount. It returns [ErrCurrencyMismatch] if the currencies are different.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency.orDefault() != o.Currency.orDefault() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.Amount+o.Amount, m.Currency), nil
+}
+
+// Sub subtracts the given amount. It returns [ErrCurrencyMismatch] if the currencies are different.
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency.orDefault() != o.Currency.orDefault() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	return New(m.Amount-o.Amount, m.Currency), nil
+}
+
+// Mul multiplies the amount by a quantity.
+func (m Money) Mul(qty int64) Money {
+	return New(m.Amount*qty, m.Currency)
+}
+
+// Percent returns the given percentage of the amount rounded to the nearest minor unit.
+// The percentage is rounded to two decimal places (basis points) before it is applied.
+func (m Money) Percent(percent float64) Money {
+	bp := int64(math.Round(percent * 100))
+	return New(divRound(m.Amount*bp, 10000), m.Currency)
+}
+
+// Discount returns the amount after removing the given percentage and the removed amount.
+// The returned values always add up to the original amount.
+func (m Money) Discount(percent float64) (total Money, discount Money) {
+	discount = m.Percent(percent)
+	return New(m.Amount-discount.Amount, m.Currency), discount
+}
+
+// divRound divides a by b rounding to the nearest integer with ties rounded away from zero.
+func divRound(a, b int64) int64 {
+	q, r := a/b, a%b
+	if 2*abs(r) >= abs(b) {
+		if (a < 0) != (b < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,196 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"0", 0},
+		{"10", 1000},
+		{"10.5", 1050},
+		{".25", 25},
+		{"1.005", 101},
+		{"1.004", 100},
+		{"-1.005", -101},
+		{"1250.50", 125050},
+	}
+
+	for _, test := range tests {
+		m, err := Parse(test.in, LKR)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %s", test.in, err)
+			continue
+		}
+		if m.Amount != test.want {
+			t.Errorf("Parse(%q) = %d, want %d", test.in, m.Amount, test.want)
+		}
+	}
+
+	for _, in := range []string{"", ".", "abc", "1.2.3", "1e5", "--1"} {
+		if _, err := Parse(in, LKR); err == nil {
+			t.Errorf("Parse(%q) should fail", in)
+		}
+	}
+}
+
+func TestFromFloat(t *testing.T) {
+	if m := FromFloat(0.1+0.2, LKR); m.Amount != 30 {
+		t.Errorf("FromFloat(0.1+0.2) = %d, want 30", m.Amount)
+	}
+	if m := FromFloat(1.005, LKR); m.Amount != 101 {
+		t.Errorf("FromFloat(1.005) = %d, want 101", m.Amount)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Money]string{
+		New(0, LKR):       "0.00",
+		New(5, LKR):       "0.05",
+		New(125050, LKR):  "1250.50",
+		New(-105, LKR):    "-1.05",
+		New(1500, "JPY"):  "1500",
+		New(12345, "KWD"): "12.345",
+	}
+
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("String() = %s, want %s", got, want)
+		}
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		percent  float64
+		total    int64
+		discount int64
+	}{
+		{1000, 10, 900, 100},
+		{999, 10, 899, 100},    // 99.9 rounds up
+		{1005, 10, 904, 101},   // 100.5 rounds away from zero
+		{1004, 10, 904, 100},   // 100.4 rounds down
+		{333, 33.33, 222, 111}, // 110.9889 rounds up
+	}
+
+	for _, test := range tests {
+		total, discount := New(test.amount, LKR).Discount(test.percent)
+		if total.Amount != test.total || discount.Amount != test.discount {
+			t.Errorf("Discount(%d, %v) = (%d, %d), want (%d, %d)",
+				test.amount, test.percent, total.Amount, discount.Amount, test.total, test.discount)
+		}
+		if total.Amount+discount.Amount != test.amount {
+			t.Errorf("Discount(%d, %v): total and discount do not add up", test.amount, test.percent)
+		}
+	}
+}
+
+func TestAddCurrencyMismatch(t *testing.T) {
+	if _, err := New(100, LKR).Add(New(100, USD)); err != ErrCurrencyMismatch {
+		t.Errorf("expected ErrCurrencyMismatch got %v", err)
+	}
+
+	sum, err := New(100, LKR).Add(New(250, ""))
+	if err != nil || sum.Amount != 350 {
+		t.Errorf("expected 350 got %d (%v)", sum.Amount, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(struct{ Price Money }{New(125050, LKR)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"Price":1250.50}` {
+		t.Errorf("unexpected json %s", data)
+	}
+
+	var v struct{ Price Money }
+	for _, in := range []string{`{"Price":1250.5}`, `{"Price":"1250.50"}`, `{"Price":{"amount":125050,"currency":"LKR"}}`, `{"Price":1.2505e3}`, `{"Price":125050E-2}`} {
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Errorf("failed to unmarshal %s: %s", in, err)
+		}
+		if !v.Price.Equal(New(125050, LKR)) {
+			t.Errorf("unmarshal %s: got %v", in, v.Price)
+		}
+	}
+
+	// null does not change the value
+	if err := json.Unmarshal([]byte(`{"Price":null}`), &v); err != nil || !v.Price.Equal(New(125050, LKR)) {
+		t.Errorf("unmarshal null: got %v (%v)", v.Price, err)
+	}
+	if err := json.Unmarshal([]byte(`{"Price":1e400}`), &v); err == nil {
+		t.Error("expected an error for an exponent that is too large")
+	}
+}
+
+func TestExpandExponent(t *testing.T) {
+	tests := map[string]string{
+		"1250.5":   "1250.5",
+		"1e2":      "100.",
+		"1.5E2":    "150.",
+		"-2.5e1":   "-25.",
+		"125e-2":   "1.25",
+		"5e-3":     ".005",
+		"0.5e-1":   ".05",
+		"1.2345e2": "123.45",
+	}
+
+	for in, want := range tests {
+		if got := expandExponent(in); got != want {
+			t.Errorf("expandExponent(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBSON(t *testing.T) {
+	type doc struct {
+		Price Money `bson:"price"`
+	}
+
+	data, err := bson.Marshal(doc{Price: New(125050, LKR)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded doc
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Price.Equal(New(125050, LKR)) {
+		t.Errorf("expected 1250.50 got %v", decoded.Price)
+	}
+
+	// prices were previously stored as doubles
+	legacy, err := bson.Marshal(bson.M{"price": 1250.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bson.Unmarshal(legacy, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Price.Equal(New(125050, LKR)) {
+		t.Errorf("expected 1250.50 got %v", decoded.Price)
+	}
+
+	// whole numbers inserted by the mongo shell are integers in major units
+	for _, price := range []any{int32(1250), int64(1250)} {
+		legacy, err := bson.Marshal(bson.M{"price": price})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bson.Unmarshal(legacy, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Price.Equal(New(125000, LKR)) {
+			t.Errorf("%T: expected 1250.00 got %v", price, decoded.Price)
+		}
+	}
+}
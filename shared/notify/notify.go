@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
 )
 
 type Config struct {
@@ -50,6 +53,18 @@ func (n *Notify) Connect(_ context.Context, cfg Config) (err error) {
 		return err
 	}
 
+	health.Add("rabbitmq:"+cfg.Queue, n.Check)
+	return nil
+}
+
+// ErrNotConnected is returned by [Notify.Check] if the connection or channel is closed.
+var ErrNotConnected = errors.New("not connected to rabbitmq")
+
+// Check checks if the connection and the channel are still open.
+func (n *Notify) Check(context.Context) error {
+	if n.con == nil || n.con.IsClosed() || n.channel == nil || n.channel.IsClosed() {
+		return ErrNotConnected
+	}
 	return nil
 }
 
@@ -59,6 +74,10 @@ func (n *Notify) Send(ctx context.Context, msg message) error {
 		return err
 	}
 
+	// send the trace context to the consumer
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, tableCarrier(headers))
+
 	err = n.channel.PublishWithContext(ctx,
 		"",
 		n.queue.Name,
@@ -66,8 +85,10 @@ func (n *Notify) Send(ctx context.Context, msg message) error {
 		false,
 		amqp.Publishing{
 			ContentType: "application/json",
+			Headers:     headers,
 			Body:        buf,
 		})
+	metrics.ObservePublish(n.queue.Name, err)
 	if err != nil {
 		return err
 	}
@@ -75,6 +96,29 @@ func (n *Notify) Send(ctx context.Context, msg message) error {
 	return nil
 }
 
+// tableCarrier adapts amqp headers to a propagation.TextMapCarrier.
+type tableCarrier amqp.Table
+
+// Get implements propagation.TextMapCarrier.
+func (t tableCarrier) Get(key string) string {
+	value, _ := t[key].(string)
+	return value
+}
+
+// Set implements propagation.TextMapCarrier.
+func (t tableCarrier) Set(key string, value string) {
+	t[key] = value
+}
+
+// Keys implements propagation.TextMapCarrier.
+func (t tableCarrier) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for key := range t {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (n *Notify) Close() error {
 	return errors.Join(
 		n.channel.Close(), n.con.Close(),
@@ -0,0 +1,303 @@
+// Package pagination implements cursor based pagination for list endpoints.
+//
+// Lists accept the following query parameters:
+//   - limit: the maximum number of items to return
+//   - cursor: the next_cursor returned with the previous page
+//   - sort: the field to sort by. Prefix the field with - to sort in descending order
+//   - from, to: only return items created in the range. Accepts RFC 3339 times or dates (2006-01-02)
+package pagination
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	// DefaultLimit is the number of items returned if the limit is not set.
+	DefaultLimit = 50
+	// MaxLimit is the maximum number of items that can be requested.
+	MaxLimit = 100
+)
+
+var (
+	// ErrInvalidLimit is returned if the limit is not a number between 1 and [MaxLimit].
+	ErrInvalidLimit = fiber.NewError(fiber.StatusBadRequest, "Limit must be a number between 1 and "+strconv.Itoa(MaxLimit))
+	// ErrInvalidCursor is returned if the cursor is malformed or was created with a different sort order.
+	ErrInvalidCursor = fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+	// ErrInvalidSort is returned if the list cannot be sorted by the field.
+	ErrInvalidSort = fiber.NewError(fiber.StatusBadRequest, "Invalid sort field")
+	// ErrInvalidDate is returned if the from or to date is malformed.
+	ErrInvalidDate = fiber.NewError(fiber.StatusBadRequest, "Invalid date range")
+)
+
+// Options contains the sort fields and the date field supported by a list.
+type Options struct {
+	// Sort maps the sort names accepted in the query to document fields.
+	// Items are sorted by _id if sort is not set.
+	Sort map[string]string
+	// DateField is the document field used by the from and to filters.
+	// The creation time in the _id is used if this is empty.
+	DateField string
+}
+
+// Params contains the pagination parameters for a request.
+type Params struct {
+	// Limit is the maximum number of items to return.
+	Limit int
+	// SortField is the document field to sort by.
+	SortField string
+	// Desc sorts the items in descending order.
+	Desc bool
+	// From and To filter the items by the date field. Zero values are ignored.
+	From, To time.Time
+
+	dateField string
+	after     *cursor
+}
+
+// cursor points to the last item of a page.
+type cursor struct {
+	Sort  string        `bson:"s"`
+	Desc  bool          `bson:"d"`
+	Value bson.RawValue `bson:"v"`
+	Id    bson.RawValue `bson:"i"`
+}
+
+// FromQuery reads the pagination parameters from the request query.
+func FromQuery(c fiber.Ctx, opts Options) (Params, error) {
+	params := Params{Limit: DefaultLimit, SortField: "_id", dateField: opts.DateField}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 || value > MaxLimit {
+			return Params{}, ErrInvalidLimit
+		}
+		params.Limit = value
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		name, desc := strings.CutPrefix(sort, "-")
+		field, ok := opts.Sort[name]
+		if !ok {
+			return Params{}, ErrInvalidSort
+		}
+		params.SortField, params.Desc = field, desc
+	}
+
+	var err error
+	if params.From, err = parseDate(c.Query("from"), false); err != nil {
+		return Params{}, err
+	}
+	if params.To, err = parseDate(c.Query("to"), true); err != nil {
+		return Params{}, err
+	}
+	if !params.From.IsZero() && !params.To.IsZero() && !params.From.Before(params.To) {
+		return Params{}, ErrInvalidDate
+	}
+
+	if value := c.Query("cursor"); value != "" {
+		after, err := decodeCursor(value)
+		if err != nil || after.Sort != params.SortField || after.Desc != params.Desc {
+			return Params{}, ErrInvalidCursor
+		}
+		params.after = after
+	}
+
+	return params, nil
+}
+
+// parseDate parses a RFC 3339 time or a date.
+// If end is true, dates are moved to the end of the day so that the range includes the whole day.
+func parseDate(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Filter returns the filter that selects the items matching filter that are in the date range and after the cursor.
+func (p Params) Filter(filter bson.D) bson.D {
+	conditions := bson.A{}
+
+	dateField := p.dateField
+	date := func(t time.Time) any { return t }
+	if dateField == "" {
+		dateField = "_id"
+		date = func(t time.Time) any { return bson.NewObjectIDFromTimestamp(t) }
+	}
+	if !p.From.IsZero() {
+		conditions = append(conditions, bson.D{{Key: dateField, Value: bson.D{{Key: "$gte", Value: date(p.From)}}}})
+	}
+	if !p.To.IsZero() {
+		conditions = append(conditions, bson.D{{Key: dateField, Value: bson.D{{Key: "$lt", Value: date(p.To)}}}})
+	}
+
+	if p.after != nil {
+		op := "$gt"
+		if p.Desc {
+			op = "$lt"
+		}
+
+		if p.SortField == "_id" {
+			conditions = append(conditions, bson.D{{Key: "_id", Value: bson.D{{Key: op, Value: p.after.Id}}}})
+		} else {
+			conditions = append(conditions, p.afterValue(op))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return filter
+	}
+	return bson.D{{Key: "$and", Value: append(bson.A{filter}, conditions...)}}
+}
+
+// afterValue returns the condition selecting the items after the cursor when sorting by a field other than _id.
+// Null and missing values sort before all other values, but comparison operators never match them,
+// so they are handled separately.
+func (p Params) afterValue(op string) bson.D {
+	if p.after.Value.Type == bson.TypeNull {
+		if p.Desc {
+			// only the other items without a value are left
+			return bson.D{{Key: p.SortField, Value: nil}, {Key: "_id", Value: bson.D{{Key: op, Value: p.after.Id}}}}
+		}
+		return bson.D{{Key: "$or", Value: bson.A{
+			bson.D{{Key: p.SortField, Value: bson.D{{Key: "$ne", Value: nil}}}},
+			bson.D{{Key: p.SortField, Value: nil}, {Key: "_id", Value: bson.D{{Key: op, Value: p.after.Id}}}},
+		}}}
+	}
+
+	after := bson.A{
+		bson.D{{Key: p.SortField, Value: bson.D{{Key: op, Value: p.after.Value}}}},
+		bson.D{{Key: p.SortField, Value: p.after.Value}, {Key: "_id", Value: bson.D{{Key: op, Value: p.after.Id}}}},
+	}
+	if p.Desc {
+		// items without a value are at the end of the list
+		after = append(after, bson.D{{Key: p.SortField, Value: nil}})
+	}
+	return bson.D{{Key: "$or", Value: after}}
+}
+
+// Sort returns the sort order for the query.
+// Items with the same sort value are sorted by _id so that the order is stable.
+func (p Params) Sort() bson.D {
+	direction := 1
+	if p.Desc {
+		direction = -1
+	}
+
+	if p.SortField == "_id" {
+		return bson.D{{Key: "_id", Value: direction}}
+	}
+	return bson.D{{Key: p.SortField, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// Find finds one page of documents matching the filter.
+// The returned cursor is empty if there are no more pages.
+func Find[T any](ctx context.Context, collection *mongo.Collection, filter bson.D, p Params) ([]T, string, error) {
+	// one extra document is fetched to check if there is a next page
+	result, err := collection.Find(ctx, p.Filter(filter), options.Find().SetSort(p.Sort()).SetLimit(int64(p.Limit)+1))
+	if err != nil {
+		return nil, "", err
+	}
+	defer result.Close(ctx)
+
+	items := make([]T, 0, p.Limit)
+	var last bson.Raw
+	for result.Next(ctx) {
+		if len(items) == p.Limit {
+			next, err := p.cursorFor(last)
+			return items, next, err
+		}
+
+		var item T
+		if err := result.Decode(&item); err != nil {
+			return nil, "", err
+		}
+		items = append(items, item)
+		last = append(last[:0], result.Current...)
+	}
+
+	return items, "", result.Err()
+}
+
+// FindFunc finds one page of documents matching the filter for which keep returns true.
+// Documents are checked after they are loaded, so documents are read until the page is full or there are no more
+// documents. The returned cursor is empty if there are no more documents that are kept.
+func FindFunc[T any](ctx context.Context, collection *mongo.Collection, filter bson.D, p Params, keep func(*T) bool) ([]T, string, error) {
+	result, err := collection.Find(ctx, p.Filter(filter), options.Find().SetSort(p.Sort()).SetBatchSize(int32(p.Limit)+1))
+	if err != nil {
+		return nil, "", err
+	}
+	defer result.Close(ctx)
+
+	items := make([]T, 0, p.Limit)
+	var last bson.Raw
+	for result.Next(ctx) {
+		var item T
+		if err := result.Decode(&item); err != nil {
+			return nil, "", err
+		}
+		if !keep(&item) {
+			continue
+		}
+
+		// the next page is only created if it contains at least one document
+		if len(items) == p.Limit {
+			next, err := p.cursorFor(last)
+			return items, next, err
+		}
+		items = append(items, item)
+		last = append(last[:0], result.Current...)
+	}
+
+	return items, "", result.Err()
+}
+
+// cursorFor creates the cursor pointing to the document.
+func (p Params) cursorFor(doc bson.Raw) (string, error) {
+	c := cursor{Sort: p.SortField, Desc: p.Desc, Id: doc.Lookup("_id"), Value: bson.RawValue{Type: bson.TypeNull}}
+	if p.SortField != "_id" {
+		if value, err := doc.LookupErr(strings.Split(p.SortField, ".")...); err == nil {
+			c.Value = value
+		}
+	}
+
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err := bson.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
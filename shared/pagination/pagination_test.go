@@ -0,0 +1,179 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var testOptions = Options{Sort: map[string]string{"name": "name"}, DateField: "created_at"}
+
+func parse(t *testing.T, query string) (Params, error) {
+	t.Helper()
+
+	var params Params
+	var err error
+	app := fiber.New()
+	app.Get("/", func(c fiber.Ctx) error {
+		params, err = FromQuery(c, testOptions)
+		return nil
+	})
+
+	if _, testErr := app.Test(httptest.NewRequest("GET", "/?"+query, nil)); testErr != nil {
+		t.Fatalf("request failed: %s", testErr)
+	}
+	return params, err
+}
+
+func TestFromQuery(t *testing.T) {
+	params, err := parse(t, "")
+	if err != nil || params.Limit != DefaultLimit || params.SortField != "_id" || params.Desc {
+		t.Errorf("unexpected default params %+v error %v", params, err)
+	}
+
+	params, err = parse(t, "limit=10&sort=-name&from=2025-01-01&to=2025-01-31")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if params.Limit != 10 || params.SortField != "name" || !params.Desc {
+		t.Errorf("unexpected params %+v", params)
+	}
+	if want := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC); !params.To.Equal(want) {
+		t.Errorf("expected to date to include the whole day got %s", params.To)
+	}
+
+	errs := map[string]error{
+		"limit=0":                                  ErrInvalidLimit,
+		"limit=1000":                               ErrInvalidLimit,
+		"sort=password":                            ErrInvalidSort,
+		"from=yesterday":                           ErrInvalidDate,
+		"from=2025-02-01&to=2025-01-01":            ErrInvalidDate,
+		"cursor=not-a-cursor":                      ErrInvalidCursor,
+		"cursor=" + testCursor(t, "_id"):           nil,
+		"sort=name&cursor=" + testCursor(t, "_id"): ErrInvalidCursor,
+	}
+	for query, want := range errs {
+		if _, err := parse(t, query); !errors.Is(err, want) {
+			t.Errorf("%s: expected error %v got %v", query, want, err)
+		}
+	}
+}
+
+func testCursor(t *testing.T, sort string) string {
+	t.Helper()
+
+	doc, _ := bson.Marshal(bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "name", Value: "test"}})
+	value, err := Params{SortField: sort}.cursorFor(doc)
+	if err != nil {
+		t.Fatalf("failed to create cursor: %s", err)
+	}
+	return value
+}
+
+func TestFilter(t *testing.T) {
+	id := bson.NewObjectID()
+	doc, _ := bson.Marshal(bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "b"}})
+
+	params := Params{Limit: 10, SortField: "name"}
+	if filter := params.Filter(bson.D{{Key: "deleted_at", Value: nil}}); len(filter) != 1 || filter[0].Key != "deleted_at" {
+		t.Errorf("expected filter to be unchanged without cursor or date range got %v", filter)
+	}
+
+	value, err := params.cursorFor(doc)
+	if err != nil {
+		t.Fatalf("failed to create cursor: %s", err)
+	}
+	params.after, err = decodeCursor(value)
+	if err != nil {
+		t.Fatalf("failed to decode cursor: %s", err)
+	}
+	if params.after.Value.StringValue() != "b" || params.after.Id.ObjectID() != id {
+		t.Errorf("cursor does not point to the document got %+v", params.after)
+	}
+
+	filter := params.Filter(bson.D{})
+	data, err := bson.Marshal(filter)
+	if err != nil {
+		t.Fatalf("failed to marshal filter: %s", err)
+	}
+
+	raw := bson.Raw(data)
+	// {$and: [{}, {$or: [{name: {$gt: "b"}}, {name: "b", _id: {$gt: id}}]}]}
+	and := raw.Lookup("$and").Array()
+	or := and.Index(1).Document().Lookup("$or").Array()
+	if or.Index(0).Document().Lookup("name", "$gt").StringValue() != "b" {
+		t.Errorf("expected items with a greater sort value to be selected got %s", raw)
+	}
+	if or.Index(1).Document().Lookup("_id", "$gt").ObjectID() != id {
+		t.Errorf("expected items with the same sort value and greater id to be selected got %s", raw)
+	}
+}
+
+func TestFilterNullValues(t *testing.T) {
+	id := bson.ObjectID{1}
+	withValue, _ := bson.Marshal(bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "b"}})
+	withNull, _ := bson.Marshal(bson.D{{Key: "_id", Value: id}, {Key: "name", Value: nil}})
+	missing, _ := bson.Marshal(bson.D{{Key: "_id", Value: id}})
+
+	idJSON := `{"$oid":"` + id.Hex() + `"}`
+	tests := []struct {
+		name     string
+		doc      []byte
+		desc     bool
+		expected string
+	}{
+		{
+			name:     "ascending value",
+			doc:      withValue,
+			expected: `{"$or":[{"name":{"$gt":"b"}},{"name":"b","_id":{"$gt":` + idJSON + `}}]}`,
+		},
+		{
+			name:     "descending value includes nulls",
+			doc:      withValue,
+			desc:     true,
+			expected: `{"$or":[{"name":{"$lt":"b"}},{"name":"b","_id":{"$lt":` + idJSON + `}},{"name":null}]}`,
+		},
+		{
+			name:     "ascending null",
+			doc:      withNull,
+			expected: `{"$or":[{"name":{"$ne":null}},{"name":null,"_id":{"$gt":` + idJSON + `}}]}`,
+		},
+		{
+			name:     "ascending missing",
+			doc:      missing,
+			expected: `{"$or":[{"name":{"$ne":null}},{"name":null,"_id":{"$gt":` + idJSON + `}}]}`,
+		},
+		{
+			name:     "descending null",
+			doc:      withNull,
+			desc:     true,
+			expected: `{"name":null,"_id":{"$lt":` + idJSON + `}}`,
+		},
+	}
+
+	for _, test := range tests {
+		params := Params{Limit: 10, SortField: "name", Desc: test.desc}
+
+		value, err := params.cursorFor(test.doc)
+		if err != nil {
+			t.Fatalf("%s: failed to create cursor: %s", test.name, err)
+		}
+		params.after, err = decodeCursor(value)
+		if err != nil {
+			t.Fatalf("%s: failed to decode cursor: %s", test.name, err)
+		}
+
+		filter := params.Filter(bson.D{})
+		data, err := bson.MarshalExtJSON(filter[0].Value.(bson.A)[1], true, false)
+		if err != nil {
+			t.Fatalf("%s: failed to marshal filter: %s", test.name, err)
+		}
+		if string(data) != test.expected {
+			t.Errorf("%s: expected %s got %s", test.name, test.expected, data)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package ratelimit limits the number of requests clients can make to a service.
+//
+// Requests are counted using a sliding window. The counts are kept in a [Store] shared by all instances of the
+// service, so the limits hold when the service has several replicas.
+//
+// Authenticated users are limited by user id, and other clients are limited by ip address. Routes can be split into
+// groups that have their own limits and counts, and users with a role can be given a higher limit.
+package ratelimit
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+)
+
+// DefaultGroup is the group of the routes that are not in a configured group.
+const DefaultGroup = "default"
+
+// Response headers describing the limit of the request.
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+// ErrLimitReached is returned if the client made too many requests.
+var ErrLimitReached = fiber.NewError(fiber.StatusTooManyRequests, "Too many requests")
+
+// Limit is the number of requests allowed in each window. A limit of 0 disables the limit.
+type Limit struct {
+	// Anonymous is the limit for clients without a valid token. Anonymous clients are identified by ip address.
+	Anonymous int
+	// User is the limit for authenticated users.
+	User int
+}
+
+// Group contains the limit for a group of routes.
+type Group struct {
+	// Paths contains the path prefixes of the routes in the group.
+	// If a path matches several groups, the group with the longest prefix is used.
+	Paths []string
+	// Methods contains the request methods of the routes in the group. If it is empty, all methods are matched.
+	Methods []string
+	Limit   `mapstructure:",squash"`
+}
+
+// Config contains the config for the rate limiter.
+type Config struct {
+	// Window is the length of the window the requests are counted in.
+	Window time.Duration
+	// ProxyHeader is the header containing the client ip address set by the api gateway.
+	// If the header is empty or missing, the ip address of the connection is used.
+	ProxyHeader string
+
+	// Limit is the limit for routes that are not in a group.
+	Limit `mapstructure:",squash"`
+
+	// Roles multiplies the user limit for users with the role. The largest multiplier of the roles of the user is used.
+	Roles map[string]float64
+	// Groups contains the route groups with their own limits.
+	Groups map[string]Group
+}
+
+// Limiter limits the rate of requests.
+type Limiter struct {
+	store Store
+	cfg   Config
+	key   *rsa.PublicKey
+}
+
+// New creates a limiter that counts requests in the store.
+// key is used to verify the tokens of users. If key is nil, the default key is loaded by [auth.New].
+func New(store Store, cfg Config, key *rsa.PublicKey) *Limiter {
+	return &Limiter{store: store, cfg: cfg, key: key}
+}
+
+// Register registers the middleware for the app.
+// Tokens are checked before the limit so that users are limited by user id. Requests with a missing or invalid
+// token are limited as anonymous clients and are rejected by the auth middleware of the route if it requires a token.
+func (l *Limiter) Register(app *fiber.App) {
+	app.Use(auth.New(auth.Config{Key: l.key, Skip: func(fiber.Ctx) bool { return true }}))
+	app.Use(l.Middleware())
+}
+
+// Middleware returns a middleware that rejects requests with [ErrLimitReached] if the client made too many requests.
+// The RateLimit headers are set on all responses. The limit is not checked if the store fails.
+func (l *Limiter) Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		group, limit := l.group(c.Method(), c.Path())
+
+		var key string
+		var allowed int
+		if user := auth.GetUser(c); user != nil {
+			key = group + ":user:" + user.UserId
+			allowed = l.userLimit(limit.User, user.Roles)
+		} else {
+			key = group + ":ip:" + l.clientIP(c)
+			allowed = limit.Anonymous
+		}
+
+		if allowed <= 0 {
+			return c.Next()
+		}
+
+		now := time.Now()
+		start := now.Truncate(l.cfg.Window)
+
+		counts, err := l.store.Hit(c.Context(), key, start, l.cfg.Window)
+		if err != nil {
+			requestlog.L(c.Context()).Error("Failed to check rate limit", zap.Error(err))
+			return c.Next()
+		}
+
+		used := counts.estimate(now.Sub(start), l.cfg.Window)
+		reset := int(math.Ceil(start.Add(l.cfg.Window).Sub(now).Seconds()))
+
+		c.Set(HeaderLimit, strconv.Itoa(allowed))
+		c.Set(HeaderRemaining, strconv.Itoa(max(0, allowed-used)))
+		c.Set(HeaderReset, strconv.Itoa(reset))
+		c.Set(HeaderPolicy, fmt.Sprintf("%d;w=%d", allowed, int(l.cfg.Window.Seconds())))
+
+		if used > allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(reset))
+			return ErrLimitReached
+		}
+		return c.Next()
+	}
+}
+
+// group returns the name and limit of the group containing the route.
+func (l *Limiter) group(method string, path string) (string, Limit) {
+	name, limit, matched := DefaultGroup, l.cfg.Limit, 0
+
+	for groupName, group := range l.cfg.Groups {
+		if len(group.Methods) > 0 && !slices.Contains(group.Methods, method) {
+			continue
+		}
+		for _, prefix := range group.Paths {
+			if len(prefix) > matched && strings.HasPrefix(path, prefix) {
+				name, limit, matched = groupName, group.Limit, len(prefix)
+			}
+		}
+	}
+
+	return name, limit
+}
+
+// userLimit returns the limit for a user with the roles.
+func (l *Limiter) userLimit(limit int, roles []string) int {
+	multiplier := 1.0
+	for _, role := range roles {
+		if m, ok := l.cfg.Roles[role]; ok && m > multiplier {
+			multiplier = m
+		}
+	}
+
+	return int(float64(limit) * multiplier)
+}
+
+// clientIP returns the ip address of the client.
+func (l *Limiter) clientIP(c fiber.Ctx) string {
+	if l.cfg.ProxyHeader != "" {
+		// X-Forwarded-For contains the addresses of all proxies after the client
+		ip, _, _ := strings.Cut(c.Get(l.cfg.ProxyHeader), ",")
+		if ip = strings.TrimSpace(ip); ip != "" {
+			return ip
+		}
+	}
+
+	return c.IP()
+}
@@ -0,0 +1,207 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
+	"github.com/gofiber/fiber/v3"
+)
+
+// memoryStore counts requests in memory.
+type memoryStore struct {
+	mu     sync.Mutex
+	starts map[string]time.Time
+	counts map[string]Counts
+	keys   []string
+}
+
+func (s *memoryStore) Hit(_ context.Context, key string, start time.Time, window time.Duration) (Counts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := s.counts[key]
+	switch s.starts[key] {
+	case start:
+		counts.Current++
+	case start.Add(-window):
+		counts = Counts{Current: 1, Previous: counts.Current}
+	default:
+		counts = Counts{Current: 1}
+	}
+
+	s.starts[key] = start
+	s.counts[key] = counts
+	s.keys = append(s.keys, key)
+	return counts, nil
+}
+
+func newTestApp(cfg Config) (*fiber.App, *memoryStore) {
+	store := &memoryStore{starts: map[string]time.Time{}, counts: map[string]Counts{}}
+	app := fiber.New()
+
+	// the token is set using a header instead of a jwt token
+	app.Use(func(c fiber.Ctx) error {
+		if id := c.Get("X-User"); id != "" {
+			c.RequestCtx().SetUserValue("user", &auth.UserToken{UserId: id, Roles: []string{c.Get("X-Role")}})
+		}
+		return c.Next()
+	})
+	app.Use(New(store, cfg, nil).Middleware())
+	app.All("/*", func(c fiber.Ctx) error { return c.SendString("ok") })
+
+	return app, store
+}
+
+func request(t *testing.T, app *fiber.App, path string, headers ...string) (int, fiber.Map) {
+	t.Helper()
+
+	req := httptest.NewRequest(fiber.MethodGet, path, nil)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+
+	return res.StatusCode, fiber.Map{
+		HeaderLimit:     res.Header.Get(HeaderLimit),
+		HeaderRemaining: res.Header.Get(HeaderRemaining),
+		"Retry-After":   res.Header.Get(fiber.HeaderRetryAfter),
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	app, _ := newTestApp(Config{Window: time.Hour, Limit: Limit{Anonymous: 2, User: 5}})
+
+	for i := 1; i <= 2; i++ {
+		status, headers := request(t, app, "/")
+		if status != fiber.StatusOK {
+			t.Fatalf("request %d: expected status 200 got %d", i, status)
+		}
+		if headers[HeaderLimit] != "2" || headers[HeaderRemaining] != strconv.Itoa(2-i) {
+			t.Errorf("request %d: unexpected headers %v", i, headers)
+		}
+	}
+
+	status, headers := request(t, app, "/")
+	if status != fiber.StatusTooManyRequests {
+		t.Errorf("expected status 429 after the limit got %d", status)
+	}
+	if headers[HeaderRemaining] != "0" || headers["Retry-After"] == "" {
+		t.Errorf("unexpected headers after the limit %v", headers)
+	}
+
+	// users are counted separately from the ip address
+	status, headers = request(t, app, "/", "X-User", "user1")
+	if status != fiber.StatusOK || headers[HeaderLimit] != "5" {
+		t.Errorf("expected user request to use the user limit got status %d headers %v", status, headers)
+	}
+}
+
+func TestMiddlewareRoles(t *testing.T) {
+	app, _ := newTestApp(Config{Window: time.Hour, Limit: Limit{Anonymous: 1, User: 5}, Roles: map[string]float64{"admin": 10}})
+
+	if _, headers := request(t, app, "/", "X-User", "user1", "X-Role", "admin"); headers[HeaderLimit] != "50" {
+		t.Errorf("expected the admin limit to be 50 got %v", headers[HeaderLimit])
+	}
+	if _, headers := request(t, app, "/", "X-User", "user2", "X-Role", "customer"); headers[HeaderLimit] != "5" {
+		t.Errorf("expected the customer limit to be 5 got %v", headers[HeaderLimit])
+	}
+}
+
+func TestMiddlewareGroups(t *testing.T) {
+	app, store := newTestApp(Config{
+		Window: time.Hour,
+		Limit:  Limit{Anonymous: 10, User: 10},
+		Groups: map[string]Group{
+			"auth":  {Paths: []string{"/auth/"}, Limit: Limit{Anonymous: 2}},
+			"oauth": {Paths: []string{"/auth/oauth/"}, Limit: Limit{Anonymous: 3}},
+		},
+	})
+
+	tests := []struct {
+		path  string
+		limit string
+		key   string
+	}{
+		{"/orders", "10", "default:ip:0.0.0.0"},
+		{"/auth/login", "2", "auth:ip:0.0.0.0"},
+		{"/auth/oauth/login", "3", "oauth:ip:0.0.0.0"},
+	}
+
+	for i, test := range tests {
+		if _, headers := request(t, app, test.path); headers[HeaderLimit] != test.limit {
+			t.Errorf("%s: expected limit %s got %v", test.path, test.limit, headers[HeaderLimit])
+		}
+		if store.keys[i] != test.key {
+			t.Errorf("%s: expected key %s got %s", test.path, test.key, store.keys[i])
+		}
+	}
+
+	// the limit is disabled for users in the auth group
+	if _, headers := request(t, app, "/auth/login", "X-User", "user1"); headers[HeaderLimit] != "" {
+		t.Errorf("expected no limit got %v", headers[HeaderLimit])
+	}
+}
+
+func TestMiddlewareGroupMethods(t *testing.T) {
+	app, store := newTestApp(Config{
+		Window: time.Hour,
+		Limit:  Limit{Anonymous: 10},
+		Groups: map[string]Group{"browse": {Paths: []string{"/restaurants"}, Methods: []string{fiber.MethodGet}, Limit: Limit{Anonymous: 300}}},
+	})
+
+	tests := []struct {
+		method string
+		key    string
+	}{
+		{fiber.MethodGet, "browse:ip:0.0.0.0"},
+		{fiber.MethodPost, "default:ip:0.0.0.0"},
+		{fiber.MethodPatch, "default:ip:0.0.0.0"},
+	}
+
+	for i, test := range tests {
+		if _, err := app.Test(httptest.NewRequest(test.method, "/restaurants/1", nil)); err != nil {
+			t.Fatalf("request failed: %s", err)
+		}
+		if store.keys[i] != test.key {
+			t.Errorf("%s: expected key %s got %s", test.method, test.key, store.keys[i])
+		}
+	}
+}
+
+func TestMiddlewareProxyHeader(t *testing.T) {
+	app, store := newTestApp(Config{Window: time.Hour, ProxyHeader: "X-Forwarded-For", Limit: Limit{Anonymous: 1}})
+
+	request(t, app, "/", "X-Forwarded-For", "10.0.0.1, 172.16.0.1")
+	request(t, app, "/")
+
+	if store.keys[0] != "default:ip:10.0.0.1" {
+		t.Errorf("expected the client ip from the header got %s", store.keys[0])
+	}
+	if store.keys[1] != "default:ip:0.0.0.0" {
+		t.Errorf("expected the connection ip without the header got %s", store.keys[1])
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	counts := Counts{Current: 3, Previous: 10}
+
+	tests := map[time.Duration]int{
+		0:                13,
+		time.Second * 30: 8,
+		time.Second * 59: 4,
+	}
+	for elapsed, want := range tests {
+		if got := counts.estimate(elapsed, time.Minute); got != want {
+			t.Errorf("%s: expected %d got %d", elapsed, want, got)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Counts contains the number of requests made in the current and previous window.
+type Counts struct {
+	Current  int64 `bson:"count"`
+	Previous int64 `bson:"previous"`
+}
+
+// estimate returns the number of requests made in the last window using the time elapsed in the current window.
+// The requests in the previous window are assumed to be spread evenly.
+func (c Counts) estimate(elapsed, window time.Duration) int {
+	weight := 1 - float64(elapsed)/float64(window)
+	return int(c.Current) + int(math.Ceil(float64(c.Previous)*weight))
+}
+
+// Store counts the requests made by clients.
+type Store interface {
+	// Hit records a request for the key in the window starting at start and returns the counts of the key
+	// including the request.
+	Hit(ctx context.Context, key string, start time.Time, window time.Duration) (Counts, error)
+}
+
+// MongoStore stores the request counts in mongo.
+type MongoStore struct {
+	collection *mongo.Collection
+}
+
+// NewMongoStore creates a store using the rate_limits collection of the database.
+// This creates the index used to remove the counts of expired windows.
+func NewMongoStore(ctx context.Context, db *mongo.Database) (*MongoStore, error) {
+	collection := db.Collection("rate_limits")
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MongoStore{collection: collection}, nil
+}
+
+// Hit implements [Store].
+// The counts are updated atomically, so requests to different instances of the service are counted correctly.
+func (s *MongoStore) Hit(ctx context.Context, key string, start time.Time, window time.Duration) (Counts, error) {
+	inWindow := bson.D{{Key: "$eq", Value: bson.A{"$start", start}}}
+	inPrevious := bson.D{{Key: "$eq", Value: bson.A{"$start", start.Add(-window)}}}
+
+	// the counts are moved to previous when the first request of a window is made
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "previous", Value: bson.D{{Key: "$cond", Value: bson.A{
+			inWindow, "$previous", bson.D{{Key: "$cond", Value: bson.A{inPrevious, "$count", 0}}},
+		}}}},
+		{Key: "count", Value: bson.D{{Key: "$cond", Value: bson.A{
+			inWindow, bson.D{{Key: "$add", Value: bson.A{"$count", 1}}}, 1,
+		}}}},
+		{Key: "start", Value: start},
+		{Key: "expires_at", Value: start.Add(2 * window)},
+	}}}}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var counts Counts
+	err := s.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(&counts)
+	if mongo.IsDuplicateKeyError(err) {
+		// another request inserted the key at the same time
+		err = s.collection.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: key}}, update, opts).Decode(&counts)
+	}
+
+	return counts, err
+}
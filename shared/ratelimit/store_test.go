@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestMongoStoreHit(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	store, err := NewMongoStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("failed to create store: %s", err)
+	}
+
+	window := time.Minute
+	start := time.Now().Truncate(window)
+
+	tests := []struct {
+		name  string
+		key   string
+		start time.Time
+		want  Counts
+	}{
+		{"first request", "a", start, Counts{Current: 1}},
+		{"same window", "a", start, Counts{Current: 2}},
+		{"other key", "b", start, Counts{Current: 1}},
+		{"next window", "a", start.Add(window), Counts{Current: 1, Previous: 2}},
+		{"same window after moving counts", "a", start.Add(window), Counts{Current: 2, Previous: 2}},
+		{"skipped window", "a", start.Add(3 * window), Counts{Current: 1}},
+	}
+
+	for _, test := range tests {
+		counts, err := store.Hit(context.Background(), test.key, test.start, window)
+		if err != nil {
+			t.Fatalf("%s: hit failed: %s", test.name, err)
+		}
+		if counts != test.want {
+			t.Errorf("%s: expected %+v got %+v", test.name, test.want, counts)
+		}
+	}
+
+	var doc struct {
+		ExpiresAt time.Time `bson:"expires_at"`
+	}
+	if err := store.collection.FindOne(context.Background(), bson.D{{Key: "_id", Value: "a"}}).Decode(&doc); err != nil {
+		t.Fatalf("failed to find counts: %s", err)
+	}
+	if want := start.Add(5 * window); !doc.ExpiresAt.Equal(want) {
+		t.Errorf("expected the counts to expire at %s got %s", want, doc.ExpiresAt)
+	}
+}
+
+func TestMongoStoreConcurrentHits(t *testing.T) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	store, err := NewMongoStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("failed to create store: %s", err)
+	}
+
+	const requests = 20
+	start := time.Now().Truncate(time.Minute)
+
+	var wg sync.WaitGroup
+	for range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Hit(context.Background(), "key", start, time.Minute); err != nil {
+				t.Errorf("hit failed: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	counts, err := store.Hit(context.Background(), "key", start, time.Minute)
+	if err != nil {
+		t.Fatalf("hit failed: %s", err)
+	}
+	if counts.Current != requests+1 {
+		t.Errorf("expected %d requests to be counted got %d", requests+1, counts.Current)
+	}
+}
@@ -0,0 +1,36 @@
+package requestlog
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// metadataKey is the grpc metadata key that contains the request id.
+const metadataKey = "x-request-id"
+
+// ServerOption returns the grpc server option that adds the request id sent by the client to the request context.
+// A new id is not created if the client did not send one.
+func ServerOption() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(serverInterceptor)
+}
+
+// DialOption returns the grpc client option that sends the request id in the context to the server.
+func DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(clientInterceptor)
+}
+
+func serverInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if ids := metadata.ValueFromIncomingContext(ctx, metadataKey); len(ids) > 0 && validId(ids[0]) {
+		ctx = WithId(ctx, ids[0])
+	}
+	return handler(ctx, req)
+}
+
+func clientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := ID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, metadataKey, id)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
@@ -0,0 +1,115 @@
+// Package requestlog assigns an id to each request and logs every request handled by the server.
+package requestlog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
+	"github.com/gofiber/fiber/v3"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Header is the header that contains the request id.
+const Header = "X-Request-ID"
+
+// maxIdLength is the maximum length of a request id accepted from the client.
+const maxIdLength = 128
+
+type ctxKey struct{}
+
+type requestInfo struct {
+	id  string
+	log *zap.Logger
+}
+
+// WithId returns a copy of ctx that contains the request id and a logger for the request.
+func WithId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, requestInfo{id: id, log: tracing.L(ctx).With(zap.String("request_id", id))})
+}
+
+// ID returns the request id in the context.
+// An empty string is returned if the context does not have a request id.
+func ID(ctx context.Context) string {
+	info, _ := ctx.Value(ctxKey{}).(requestInfo)
+	return info.id
+}
+
+// Logger returns a logger that includes the request id and the trace id in the context.
+func Logger(ctx context.Context, log *zap.Logger) *zap.Logger {
+	log = tracing.Logger(ctx, log)
+	if id := ID(ctx); id != "" {
+		return log.With(zap.String("request_id", id))
+	}
+	return log
+}
+
+// L returns the logger for the request in the context.
+// If the context does not belong to a request, the global logger is returned.
+func L(ctx context.Context) *zap.Logger {
+	if info, ok := ctx.Value(ctxKey{}).(requestInfo); ok {
+		return info.log
+	}
+	return tracing.L(ctx)
+}
+
+// Middleware returns a middleware that assigns a request id and logs the request once it is handled.
+// The id sent by the client in the X-Request-ID header is used if it is valid.
+// Errors returned by handlers are passed to the app error handler so that the logged status matches the response.
+// This should be registered after the tracing middleware.
+func Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		start := time.Now()
+
+		id := c.Get(Header)
+		if !validId(id) {
+			id = uuid.NewString()
+		}
+		c.Set(Header, id)
+		c.SetContext(WithId(c.Context(), id))
+
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		fields := []zap.Field{
+			zap.String("method", c.Method()),
+			zap.String("route", c.Route().Path),
+			zap.Int("status", c.Response().StatusCode()),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", len(c.Response().Body())),
+		}
+		if user := auth.GetUser(c); user != nil {
+			fields = append(fields, zap.String("user_id", user.UserId))
+		}
+
+		log := L(c.Context())
+		// health checks and metrics scrapes are too frequent to log at info level
+		if strings.HasPrefix(c.Path(), "/health/") || c.Path() == "/metrics" {
+			log.Debug("Request handled", fields...)
+		} else {
+			log.Info("Request handled", fields...)
+		}
+
+		return nil
+	}
+}
+
+// validId checks if the id is safe to include in logs and responses.
+func validId(id string) bool {
+	if id == "" || len(id) > maxIdLength {
+		return false
+	}
+
+	for _, r := range id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
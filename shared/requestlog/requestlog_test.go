@@ -0,0 +1,84 @@
+package requestlog
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMiddleware(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	var handlerId string
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/items/:id", func(c fiber.Ctx) error {
+		handlerId = ID(c.Context())
+		return fiber.ErrNotFound
+	})
+
+	req := httptest.NewRequest("GET", "/items/1", nil)
+	req.Header.Set(Header, "req-1")
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+
+	if handlerId != "req-1" || res.Header.Get(Header) != "req-1" {
+		t.Errorf("expected request id req-1 got handler %q header %q", handlerId, res.Header.Get(Header))
+	}
+
+	entries := logs.FilterMessage("Request handled").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 access log got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-1" || fields["route"] != "/items/:id" || fields["status"] != int64(fiber.StatusNotFound) {
+		t.Errorf("unexpected access log fields %v", fields)
+	}
+
+	// invalid ids are replaced
+	req = httptest.NewRequest("GET", "/items/1", nil)
+	req.Header.Set(Header, "bad id")
+	if _, err = app.Test(req); err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+	if handlerId == "" || handlerId == "bad id" {
+		t.Errorf("expected a generated request id got %q", handlerId)
+	}
+}
+
+func TestGrpcPropagation(t *testing.T) {
+	var sent metadata.MD
+	invoker := func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		sent, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+
+	err := clientInterceptor(WithId(context.Background(), "req-2"), "/test", nil, nil, nil, invoker)
+	if err != nil {
+		t.Fatalf("call failed: %s", err)
+	}
+
+	var received string
+	handler := func(ctx context.Context, _ any) (any, error) {
+		received = ID(ctx)
+		return nil, nil
+	}
+
+	_, err = serverInterceptor(metadata.NewIncomingContext(context.Background(), sent), nil, nil, handler)
+	if err != nil {
+		t.Fatalf("call failed: %s", err)
+	}
+	if received != "req-2" {
+		t.Errorf("expected server request id req-2 got %q", received)
+	}
+}
@@ -5,18 +5,17 @@ import (
 	"os"
 	"os/signal"
 
-	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/ratelimit"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/validate"
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/compress"
-	"github.com/gofiber/fiber/v3/middleware/healthcheck"
-	"github.com/gofiber/fiber/v3/middleware/limiter"
-	"go.uber.org/zap"
 )
 
-const DefaultRateLimit = 60
-
 // DefaultFiberConfig is the default fiber config used for servers.
 var DefaultFiberConfig = fiber.Config{
 	ErrorHandler:    middleware.ErrorHandler(),
@@ -25,24 +24,16 @@ var DefaultFiberConfig = fiber.Config{
 }
 
 // WithDefaultMiddleware registers default middleware for the server.
-func WithDefaultMiddleware(app *fiber.App) *fiber.App {
+// Requests are rate limited using limiter.
+func WithDefaultMiddleware(app *fiber.App, limiter *ratelimit.Limiter) *fiber.App {
 	app.Use(middleware.Recover())
-	app.Use("/health/livez", healthcheck.NewHealthChecker(healthcheck.Config{
-		Probe: func(fiber.Ctx) bool {
-			zap.L().Debug("Health check")
-			return true
-		},
-	}))
+	app.Use(tracing.Middleware())
+	app.Use(requestlog.Middleware())
+	metrics.Register(app)
+	health.Register(app)
 
 	app.Use(compress.New())
-	app.Use(limiter.New(limiter.Config{
-		MaxFunc:           func(fiber.Ctx) int { return DefaultRateLimit },
-		Max:               DefaultRateLimit,
-		LimiterMiddleware: limiter.SlidingWindow{},
-		LimitReached: func(c fiber.Ctx) error {
-			return c.Status(fiber.StatusTooManyRequests).JSON(dto.Error("Too many requests"))
-		},
-	}))
+	limiter.Register(app)
 
 	return app
 }
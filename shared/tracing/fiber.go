@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIdHeader is the response header that contains the trace id of the request.
+const TraceIdHeader = "X-Trace-Id"
+
+// headerCarrier adapts fasthttp request headers to a [propagation.TextMapCarrier].
+type headerCarrier struct {
+	header *fasthttp.RequestHeader
+}
+
+var _ propagation.TextMapCarrier = headerCarrier{}
+
+// Get implements propagation.TextMapCarrier.
+func (h headerCarrier) Get(key string) string {
+	return string(h.header.Peek(key))
+}
+
+// Set implements propagation.TextMapCarrier.
+func (h headerCarrier) Set(key string, value string) {
+	h.header.Set(key, value)
+}
+
+// Keys implements propagation.TextMapCarrier.
+func (h headerCarrier) Keys() []string {
+	keys := []string{}
+	h.header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// Middleware starts a server span for each request.
+// The trace context sent by the client is used as the parent of the span.
+// The span is stored in the request context, handlers should use [fiber.Ctx.Context] to pass it to other calls.
+func Middleware() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.Context(), headerCarrier{&c.Request().Header})
+
+		ctx, span := tracer().Start(ctx, c.Method()+" "+c.Path(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+			),
+		)
+		defer span.End()
+
+		c.SetContext(ctx)
+		if span.SpanContext().HasTraceID() {
+			c.Set(TraceIdHeader, span.SpanContext().TraceID().String())
+		}
+
+		err := c.Next()
+
+		// use the route pattern as the span name to group requests to the same handler
+		span.SetName(c.Method() + " " + c.Route().Path)
+		span.SetAttributes(semconv.HTTPRoute(c.Route().Path))
+
+		status := c.Response().StatusCode()
+		if fiberErr, ok := err.(*fiber.Error); ok {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+			if err != nil {
+				span.RecordError(err)
+			}
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CommandMonitor returns a mongo command monitor that creates a client span for each command.
+// Command arguments are not recorded because they may contain user data.
+func CommandMonitor() *event.CommandMonitor {
+	var spans sync.Map
+
+	finish := func(requestId int64, err error) {
+		value, ok := spans.LoadAndDelete(requestId)
+		if !ok {
+			return
+		}
+
+		span := value.(trace.Span)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			// only trace commands made while handling a traced request
+			if !trace.SpanContextFromContext(ctx).IsValid() {
+				return
+			}
+
+			_, span := tracer().Start(ctx, "mongo."+evt.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					semconv.DBSystemMongoDB,
+					semconv.DBNamespace(evt.DatabaseName),
+					semconv.DBOperationName(evt.CommandName),
+				),
+			)
+			spans.Store(evt.RequestID, span)
+		},
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			finish(evt.RequestID, nil)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			finish(evt.RequestID, evt.Failure)
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Package tracing sets up OpenTelemetry tracing for the services.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// instrumentation is the name of the tracer used by this package.
+const instrumentation = "github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
+
+// Config contains the tracing config.
+type Config struct {
+	// Enabled controls if spans are exported. Trace context is propagated even if this is false.
+	Enabled bool
+	// Endpoint is the host and port of the OTLP gRPC collector.
+	Endpoint string
+	// Insecure disables TLS for the connection to the collector.
+	Insecure bool
+	// SampleRatio is the fraction of new traces that are sampled. Traces started by other services follow the parent.
+	SampleRatio float64
+}
+
+// Setup sets up the global tracer provider and propagator.
+// The returned function flushes and stops the exporter and should be called before the service exits.
+func Setup(ctx context.Context, service string, cfg Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(service)))
+	if err != nil {
+		return nil, errors.Join(err, exporter.Shutdown(ctx))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	zap.S().Infof("Exporting traces to %s", cfg.Endpoint)
+	return provider.Shutdown, nil
+}
+
+// tracer returns the tracer used to create spans.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentation)
+}
+
+// Fields returns the zap fields for the trace and span id in the context.
+// No fields are returned if the context does not contain a valid span.
+func Fields(ctx context.Context) []zap.Field {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String("trace_id", spanCtx.TraceID().String()),
+		zap.String("span_id", spanCtx.SpanID().String()),
+	}
+}
+
+// Logger returns a logger that includes the trace and span id in the context.
+func Logger(ctx context.Context, log *zap.Logger) *zap.Logger {
+	if fields := Fields(ctx); fields != nil {
+		return log.With(fields...)
+	}
+	return log
+}
+
+// L returns the global logger with the trace and span id in the context.
+func L(ctx context.Context) *zap.Logger {
+	return Logger(ctx, zap.L())
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddlewarePropagation(t *testing.T) {
+	if _, err := Setup(context.Background(), "test", Config{}); err != nil {
+		t.Fatalf("setup failed: %s", err)
+	}
+
+	var handlerTraceId string
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Get("/", func(c fiber.Ctx) error {
+		handlerTraceId = trace.SpanContextFromContext(c.Context()).TraceID().String()
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	const traceId = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("traceparent", "00-"+traceId+"-00f067aa0ba902b7-01")
+
+	res, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("request failed: %s", err)
+	}
+
+	if handlerTraceId != traceId {
+		t.Errorf("expected handler context trace id %s got %s", traceId, handlerTraceId)
+	}
+	if got := res.Header.Get(TraceIdHeader); got != traceId {
+		t.Errorf("expected %s header %s got %s", TraceIdHeader, traceId, got)
+	}
+}
+
+func TestFields(t *testing.T) {
+	if fields := Fields(context.Background()); fields != nil {
+		t.Errorf("expected no fields for context without span got %v", fields)
+	}
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}})
+	fields := Fields(trace.ContextWithSpanContext(context.Background(), spanCtx))
+	if len(fields) != 2 || fields[0].String != spanCtx.TraceID().String() || fields[1].String != spanCtx.SpanID().String() {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/money"
 	"github.com/go-playground/locales/en"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
@@ -34,6 +35,14 @@ func New() *Validator {
 		return name
 	})
 
+	// validate money values using the amount in major units so that tags such as min=1 work as expected
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if m, ok := field.Interface().(money.Money); ok {
+			return m.Float()
+		}
+		return nil
+	}, money.Money{})
+
 	return &Validator{validator: validate, translator: translator}
 }
 
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
+
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/config"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	service "github.com/SE-WE-22-Projects/DS-Food-Delivery/upload-service"
 	"go.uber.org/zap"
 )
@@ -15,6 +18,14 @@ func main() {
 	// create the logger
 	logger.SetupGlobalLogger(cfg.Logger)
 
+	serverCtx := shared.AppContext()
+
+	shutdownTracing, err := tracing.Setup(serverCtx, "upload-service", cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal("Failed to setup tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background()) //nolint: errcheck
+
 	s, err := service.New(cfg)
 	if err != nil {
 		zap.L().Fatal("Failed to create server", zap.Error(err))
@@ -25,7 +36,7 @@ func main() {
 		zap.L().Fatal("Failed to register routes", zap.Error(err))
 	}
 
-	err = s.Start(shared.AppContext())
+	err = s.Start(serverCtx)
 	if err != nil {
 		zap.L().Fatal("Server error", zap.Error(err))
 	}
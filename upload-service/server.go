@@ -7,9 +7,13 @@ import (
 	"sync"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/gofiber/fiber/v3"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
@@ -38,6 +42,7 @@ type Config struct {
 		Key    string
 		Bucket string
 	}
+	Tracing tracing.Config
 }
 
 type Server struct {
@@ -65,6 +70,16 @@ func New(cfg *Config) (*Server, error) {
 	}
 
 	s.app.Use(middleware.Recover())
+	s.app.Use(tracing.Middleware())
+	s.app.Use(requestlog.Middleware())
+	metrics.Register(s.app)
+	health.Register(s.app)
+
+	// files are cached in the upload directory even when S3 is used
+	health.Add("uploads", func(context.Context) error {
+		_, err := s.fs.Stat("/")
+		return err
+	})
 
 	if s.cfg.S3.Use {
 		sess, err := session.NewSession(&aws.Config{
@@ -56,9 +56,10 @@ func (a *App) createSession(ctx context.Context, user *models.User, userIP, user
 		UserID:    user.ID,
 		CreatedAt: time.Now(),
 		ExpiresAt: time.Now().Add(TokenDuration),
-		Refresh:   newRefresh,
-		UA:        userAgent,
-		IP:        userIP,
+		RefreshExpiresAt: time.Now().Add(RefreshDuration),
+		Refresh:          newRefresh,
+		UA:               userAgent,
+		IP:               userIP,
 	})
 	if err != nil {
 		return nil, err
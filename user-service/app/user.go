@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/app/oauth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/repo"
@@ -43,31 +44,14 @@ func NewApp(client *mongo.Client, oauthCfg oauth.Config, key *rsa.PrivateKey) *A
 }
 func (a *App) UserRepo() repo.UserRepo { return a.users }
 
-// GetAllUsers gets all users.
-func (a *App) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	users, err := a.users.GetAllUsers(ctx, false)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return []models.User{}, nil
-	}
-
-	return users, nil
+// GetAllUsers gets a page of users.
+func (a *App) GetAllUsers(ctx context.Context, p pagination.Params) ([]models.User, string, error) {
+	return a.users.GetAllUsers(ctx, false, p)
 }
 
-func (a *App) GetAllDrivers(ctx context.Context) ([]models.User, error) {
-	users, err := a.users.GetAllUsers(ctx, true)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return []models.User{}, nil
-	}
-
-	return users, nil
+// GetAllDrivers gets a page of users that are drivers.
+func (a *App) GetAllDrivers(ctx context.Context, p pagination.Params) ([]models.User, string, error) {
+	return a.users.GetAllUsers(ctx, true, p)
 }
 
 // GetUser gets the user with the given id.
@@ -4,12 +4,20 @@ import (
 	"context"
 	"errors"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/models"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/proto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/repo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+var driverStatuses = map[proto.DriverStatus]models.DriverStatus{
+	proto.DriverStatus_DriverNotAvailable: models.DriverUnavailable,
+	proto.DriverStatus_DriverAvailable:    models.DriverAvailable,
+	proto.DriverStatus_DriverBusy:         models.DriverBusy,
+}
+
 type Handler struct {
 	db repo.UserRepo
 	proto.UnimplementedUserServiceServer
@@ -38,3 +46,22 @@ func (h *Handler) GetUserBy(ctx context.Context, req *proto.UserRequest) (*proto
 	}, nil
 
 }
+
+func (h *Handler) SetDriverStatus(ctx context.Context, req *proto.DriverStatusUpdate) (*emptypb.Empty, error) {
+	driverStatus, ok := driverStatuses[req.Status]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid driver status")
+	}
+
+	err := h.db.SetDriverStatus(ctx, req.UserId, driverStatus)
+	if err != nil {
+		if errors.Is(err, repo.ErrInvalidID) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		} else if errors.Is(err, repo.ErrNoUser) {
+			return nil, status.Error(codes.NotFound, "user is not a driver")
+		}
+		return nil, err
+	}
+
+	return &emptypb.Empty{}, nil
+}
@@ -36,7 +36,7 @@ func (a *Auth) Register(c fiber.Ctx) error {
 		return err
 	}
 
-	userID, err := a.app.CreateUser(c.RequestCtx(), req.ToUser(), false)
+	userID, err := a.app.CreateUser(c.Context(), req.ToUser(), false)
 	if err != nil {
 		return err
 	}
@@ -58,7 +58,7 @@ func (a *Auth) Login(c fiber.Ctx) error {
 	userIP = string(slices.Clone([]byte(userIP)))
 	userAgent = string(slices.Clone([]byte(userAgent)))
 
-	res, err := a.app.LoginWithPassword(c.RequestCtx(), models.LoginRequest{Email: req.Email, Password: req.Password, IP: userIP, UA: userAgent})
+	res, err := a.app.LoginWithPassword(c.Context(), models.LoginRequest{Email: req.Email, Password: req.Password, IP: userIP, UA: userAgent})
 	if err != nil {
 		return err
 	}
@@ -73,7 +73,7 @@ func (a *Auth) CheckSession(c fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	valid, err := a.app.ValidSession(c.RequestCtx(), user)
+	valid, err := a.app.ValidSession(c.Context(), user)
 	if err != nil {
 		return err
 	}
@@ -97,7 +97,7 @@ func (a *Auth) RefreshSession(c fiber.Ctx) error {
 	userIP = string(slices.Clone([]byte(userIP)))
 	userAgent = string(slices.Clone([]byte(userAgent)))
 
-	res, err := a.app.RefreshSession(c.RequestCtx(), refresh, userIP, userAgent)
+	res, err := a.app.RefreshSession(c.Context(), refresh, userIP, userAgent)
 	if err != nil {
 		return err
 	}
@@ -133,7 +133,7 @@ func (a *Auth) OAuthCallback(c fiber.Ctx) error {
 		userAgent = string(slices.Clone([]byte(userAgent)))
 		code = string(slices.Clone([]byte(code)))
 
-		res, err := a.app.OAuthLogin(c.RequestCtx(), code, state, userIP, userAgent)
+		res, err := a.app.OAuthLogin(c.Context(), code, state, userIP, userAgent)
 		if err != nil {
 			return err
 		}
@@ -147,7 +147,7 @@ func (a *Auth) OAuthCallback(c fiber.Ctx) error {
 	}
 
 	userID := user.UserId
-	err := a.app.OAuthLink(c.RequestCtx(), userID, code, state)
+	err := a.app.OAuthLink(c.Context(), userID, code, state)
 	if err != nil {
 		return err
 	}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/app"
 	"github.com/gofiber/fiber/v3"
 )
@@ -16,7 +17,7 @@ func NewApplication(app *app.App) *Driver {
 
 // HandleGetAll gets all drivers
 func (a *Driver) HandleGetAll(c fiber.Ctx) error {
-	data, err := a.app.GetAllPendingDriverRegs(c.RequestCtx())
+	data, err := a.app.GetAllPendingDriverRegs(c.Context())
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -25,7 +26,7 @@ func (a *Driver) HandleGetAll(c fiber.Ctx) error {
 }
 
 func (a *Driver) HandleGetAllAccepted(c fiber.Ctx) error {
-	data, err := a.app.GetAllApprovedDriverRegs(c.RequestCtx())
+	data, err := a.app.GetAllApprovedDriverRegs(c.Context())
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -35,7 +36,7 @@ func (a *Driver) HandleGetAllAccepted(c fiber.Ctx) error {
 
 // HandleGetAll gets all drivers
 func (a *Driver) HandleGetAllRejected(c fiber.Ctx) error {
-	data, err := a.app.GetAllRejectedDriverRegs(c.RequestCtx())
+	data, err := a.app.GetAllRejectedDriverRegs(c.Context())
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -50,7 +51,7 @@ func (a *Driver) GetAllAppsByUser(c fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	data, err := a.app.GetAllRegByUserID(c.RequestCtx(), user.UserId)
+	data, err := a.app.GetAllRegByUserID(c.Context(), user.UserId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -65,7 +66,7 @@ func (a *Driver) GetUserAppCurrent(c fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	data, err := a.app.GetCurrentRegByUserID(c.RequestCtx(), user.UserId)
+	data, err := a.app.GetCurrentRegByUserID(c.Context(), user.UserId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -80,7 +81,7 @@ func (a *Driver) WithdrawOwnApp(c fiber.Ctx) error {
 		return fiber.ErrUnauthorized
 	}
 
-	err := a.app.WithdrawRegByID(c.RequestCtx(), user.UserId)
+	err := a.app.WithdrawRegByID(c.Context(), user.UserId)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -99,7 +100,7 @@ func (a *Driver) CreateDriverApp(c fiber.Ctx) error {
 		return sendError(c, err)
 	}
 
-	appID, err := a.app.CreateDriverRegRequest(c.RequestCtx(), user.UserId, data.ToRequest())
+	appID, err := a.app.CreateDriverRegRequest(c.Context(), user.UserId, data.ToRequest())
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -113,7 +114,7 @@ func (a *Driver) HandleGetByID(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.Error("invalid application id"))
 	}
 
-	reg, err := a.app.GetRegByID(c.RequestCtx(), appID)
+	reg, err := a.app.GetRegByID(c.Context(), appID)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -138,7 +139,7 @@ func (a *Driver) HandleApproveApp(c fiber.Ctx) error {
 		return sendError(c, err)
 	}
 
-	err := a.app.UpdateReqApproveStatus(c.RequestCtx(), appID, user.UserId, data.Approved, data.Reason)
+	err := a.app.UpdateReqApproveStatus(c.Context(), appID, user.UserId, data.Approved, data.Reason)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -147,10 +148,15 @@ func (a *Driver) HandleApproveApp(c fiber.Ctx) error {
 }
 
 func (a *Driver) HandleGetAllDrivers(c fiber.Ctx) error {
-	users, err := a.app.GetAllDrivers(c.RequestCtx())
+	page, err := pagination.FromQuery(c, userPages)
 	if err != nil {
 		return sendError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(dto.Ok(users))
+	users, next, err := a.app.GetAllDrivers(c.Context(), page)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(users, next))
 }
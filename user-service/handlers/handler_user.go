@@ -2,11 +2,18 @@ package handlers
 
 import (
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/dto"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/app"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/models"
 	"github.com/gofiber/fiber/v3"
 )
 
+// userPages contains the sort fields supported by the user lists.
+var userPages = pagination.Options{
+	Sort:      map[string]string{"name": "name", "email": "email", "created_at": "created_at"},
+	DateField: "created_at",
+}
+
 type User struct {
 	app *app.App
 }
@@ -17,14 +24,19 @@ func NewUser(app *app.App) (*User, error) {
 	return handler, nil
 }
 
-// HandleGetUsers handles sending a list of all users.
+// HandleGetUsers handles sending a page of all users.
 func (u *User) HandleGetUsers(c fiber.Ctx) error {
-	users, err := u.app.GetAllUsers(c.RequestCtx())
+	page, err := pagination.FromQuery(c, userPages)
+	if err != nil {
+		return sendError(c, err)
+	}
+
+	users, next, err := u.app.GetAllUsers(c.Context(), page)
 	if err != nil {
 		return sendError(c, err)
 	}
 
-	return c.Status(fiber.StatusOK).JSON(dto.Ok(users))
+	return c.Status(fiber.StatusOK).JSON(dto.Paged(users, next))
 }
 
 // HandleAddUser handles adding a new user.
@@ -35,7 +47,7 @@ func (u *User) HandleAddUser(c fiber.Ctx) error {
 		return sendError(c, err)
 	}
 
-	userID, err := u.app.CreateUser(c.RequestCtx(), req.ToUser(), true)
+	userID, err := u.app.CreateUser(c.Context(), req.ToUser(), true)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -57,7 +69,7 @@ func (u *User) HandleUpdateUser(c fiber.Ctx) error {
 		return sendError(c, err)
 	}
 
-	updated, err := u.app.UpdateUser(c.RequestCtx(), userID, req)
+	updated, err := u.app.UpdateUser(c.Context(), userID, req)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -73,7 +85,7 @@ func (u *User) HandleGetUserImage(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.Error("User id is not specified or is invalid"))
 	}
 
-	imgURL, err := u.app.GetUserProfileImage(c.RequestCtx(), userID)
+	imgURL, err := u.app.GetUserProfileImage(c.Context(), userID)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -93,7 +105,7 @@ func (u *User) HandleGetUser(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.Error("User id is not specified or is invalid"))
 	}
 
-	user, err := u.app.GetUser(c.RequestCtx(), userID)
+	user, err := u.app.GetUser(c.Context(), userID)
 	if err != nil {
 		return sendError(c, err)
 	}
@@ -109,7 +121,7 @@ func (u *User) HandleDeleteUser(c fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.Error("User id is not specified or is invalid"))
 	}
 
-	err := u.app.DeleteUser(c.RequestCtx(), userID)
+	err := u.app.DeleteUser(c.Context(), userID)
 	if err != nil {
 		return sendError(c, err)
 	}
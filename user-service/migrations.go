@@ -0,0 +1,61 @@
+package userservice
+
+import (
+	"context"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/migrate"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// Migrations contains the database migrations of the service.
+// New migrations must be added at the end with a higher version.
+var Migrations = []migrate.Migration{
+	{
+		Version:     1,
+		Description: "Add user, session and driver application indexes",
+		Up: migrate.Steps(
+			migrate.CreateIndexes("user",
+				migrate.Index("email"),
+				migrate.Index("driver_profile.status"),
+				migrate.Index("name"),
+				migrate.Index("created_at"),
+			),
+			migrate.CreateIndexes("session",
+				migrate.Index("user_id"),
+				migrate.TTLIndex("refresh_expires_at", 0),
+			),
+			migrate.CreateIndexes("driver-applications", migrate.Index("user_id"), migrate.Index("status")),
+		),
+	},
+	{
+		Version:     2,
+		Description: "Move the user address to address_v2",
+		Up:          backfillAddressV2,
+	},
+}
+
+// backfillAddressV2 moves the old address of users created before address_v2 was added.
+// The old address is a single line and has no location, so it is stored as the street and the address is
+// marked as incomplete until the user updates it.
+func backfillAddressV2(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("user").UpdateMany(ctx,
+		bson.D{{Key: "address_v2", Value: bson.D{{Key: "$exists", Value: false}}}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.D{{Key: "address_v2", Value: bson.D{
+				{Key: "no", Value: ""},
+				{Key: "street", Value: bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$address"}}, "string"}}},
+					"$address",
+					"",
+				}}}},
+				{Key: "town", Value: ""},
+				{Key: "city", Value: ""},
+				{Key: "postal_code", Value: ""},
+				{Key: "incomplete", Value: true},
+			}}}}},
+			{{Key: "$unset", Value: "address"}},
+		},
+	)
+	return err
+}
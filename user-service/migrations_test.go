@@ -0,0 +1,67 @@
+package userservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/yehan2002/is/v2"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type migrationTests struct{}
+
+func TestMigrations(t *testing.T) {
+	is.Suite(t, &migrationTests{})
+}
+
+func (m *migrationTests) TestBackfillAddressV2(is is.Is) {
+	db, closer := database.ConnectTestDB()
+	defer closer()
+
+	users := db.Collection("user")
+	current := bson.D{{Key: "no", Value: "1"}, {Key: "street", Value: "Main Street"}, {Key: "town", Value: "Town"},
+		{Key: "city", Value: "City"}, {Key: "postal_code", Value: "10000"},
+		{Key: "location", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{79.8, 6.9}}}}}
+
+	_, err := users.InsertMany(context.TODO(), []any{
+		bson.D{{Key: "_id", Value: "legacy"}, {Key: "address", Value: "12 Old Road, Colombo"}},
+		bson.D{{Key: "_id", Value: "no-address"}},
+		bson.D{{Key: "_id", Value: "current"}, {Key: "address_v2", Value: current}},
+	})
+	is.Ok(err, "users should be inserted")
+
+	err = backfillAddressV2(context.TODO(), db)
+	is.Ok(err, "migration should succeed")
+	// the migration must be idempotent
+	err = backfillAddressV2(context.TODO(), db)
+	is.Ok(err, "migration should succeed when run again")
+
+	address := func(id string) bson.M {
+		var user bson.M
+		err := users.FindOne(context.TODO(), bson.D{{Key: "_id", Value: id}}).Decode(&user)
+		is.Ok(err, "user should exist")
+		_, hasOld := user["address"]
+		is(!hasOld, "old address should be removed")
+
+		address, ok := user["address_v2"].(bson.M)
+		is(ok, "address_v2 should be set")
+		return address
+	}
+
+	legacy := address("legacy")
+	is.Equal(legacy["street"], "12 Old Road, Colombo", "old address should be moved to the street")
+	is.Equal(legacy["city"], "", "other fields should be empty")
+	is.Equal(legacy["incomplete"], true, "address should be incomplete")
+	_, hasLocation := legacy["location"]
+	is(!hasLocation, "location should not be set")
+
+	empty := address("no-address")
+	is.Equal(empty["street"], "", "street should be empty if there was no address")
+	is.Equal(empty["incomplete"], true, "address should be incomplete")
+
+	updated := address("current")
+	is.Equal(updated["street"], "Main Street", "current address should not be changed")
+	_, incomplete := updated["incomplete"]
+	is(!incomplete, "current address should not be incomplete")
+}
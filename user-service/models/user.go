@@ -42,6 +42,8 @@ type Session struct {
 	UserID    bson.ObjectID `bson:"user_id" json:"user"`
 	CreatedAt time.Time     `bson:"create_at" json:"created_at"`
 	ExpiresAt time.Time     `bson:"expires_at" json:"expires_at"`
+	// RefreshExpiresAt is when the refresh token of the session expires. The session is deleted after this.
+	RefreshExpiresAt time.Time `bson:"refresh_expires_at,omitempty" json:"-"`
 
 	Refresh    string `bson:"refresh" json:"-"`
 	CanRefresh bool   `bson:"can_refresh" json:"-"`
@@ -66,6 +68,9 @@ type Address struct {
 	City       string `json:"city" bson:"city" validate:"min=1"`
 	PostalCode string `json:"postal_code" bson:"postal_code" validate:"min=1"`
 	Position   Point  `json:"position" bson:"location"`
+	// Incomplete is set for addresses moved from the old single line address.
+	// These only contain the street and no location, so the user has to update the address.
+	Incomplete bool `json:"incomplete" bson:"incomplete,omitempty"`
 }
 
 type Point struct {
@@ -75,6 +80,9 @@ type Point struct {
 }
 
 func (a *Address) Address() string {
+	if a.Incomplete {
+		return a.Street
+	}
 	return fmt.Sprintf("%s, %s, %s, %s, Sri Lanka %s", a.No, a.Street, a.Town, a.City, a.PostalCode)
 }
 
@@ -7,6 +7,7 @@ import (
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // This is a compile-time assertion to ensure that this generated file
@@ -19,6 +20,8 @@ const _ = grpc.SupportPackageIsVersion7
 type UserServiceClient interface {
 	// Gets the user
 	GetUserBy(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserDetails, error)
+	// Sets the availability of a driver. Returns NOT_FOUND if the user is not a driver.
+	SetDriverStatus(ctx context.Context, in *DriverStatusUpdate, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type userServiceClient struct {
@@ -38,12 +41,23 @@ func (c *userServiceClient) GetUserBy(ctx context.Context, in *UserRequest, opts
 	return out, nil
 }
 
+func (c *userServiceClient) SetDriverStatus(ctx context.Context, in *DriverStatusUpdate, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/UserService/SetDriverStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // UserServiceServer is the server API for UserService service.
 // All implementations must embed UnimplementedUserServiceServer
 // for forward compatibility
 type UserServiceServer interface {
 	// Gets the user
 	GetUserBy(context.Context, *UserRequest) (*UserDetails, error)
+	// Sets the availability of a driver. Returns NOT_FOUND if the user is not a driver.
+	SetDriverStatus(context.Context, *DriverStatusUpdate) (*emptypb.Empty, error)
 	mustEmbedUnimplementedUserServiceServer()
 }
 
@@ -54,6 +68,9 @@ type UnimplementedUserServiceServer struct {
 func (UnimplementedUserServiceServer) GetUserBy(context.Context, *UserRequest) (*UserDetails, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetUserBy not implemented")
 }
+func (UnimplementedUserServiceServer) SetDriverStatus(context.Context, *DriverStatusUpdate) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDriverStatus not implemented")
+}
 func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
 
 // UnsafeUserServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -85,6 +102,24 @@ func _UserService_GetUserBy_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _UserService_SetDriverStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DriverStatusUpdate)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServiceServer).SetDriverStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/UserService/SetDriverStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServiceServer).SetDriverStatus(ctx, req.(*DriverStatusUpdate))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _UserService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "UserService",
 	HandlerType: (*UserServiceServer)(nil),
@@ -93,6 +128,10 @@ var _UserService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetUserBy",
 			Handler:    _UserService_GetUserBy_Handler,
 		},
+		{
+			MethodName: "SetDriverStatus",
+			Handler:    _UserService_SetDriverStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "user-service.proto",
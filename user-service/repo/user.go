@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -18,8 +19,8 @@ var ErrNoUser = errors.New("user with the given id was not found")
 var ErrInvalidID = errors.New("given Id is invalid")
 
 type UserRepo interface {
-	// Gets all users in the database
-	GetAllUsers(ctx context.Context, driversOnly bool) ([]models.User, error)
+	// Gets a page of users in the database. The returned cursor is empty if there are no more pages.
+	GetAllUsers(ctx context.Context, driversOnly bool, p pagination.Params) ([]models.User, string, error)
 	// CreateUser creates a new user using the given data.
 	CreateUser(ctx context.Context, user *models.User) (string, error)
 	// GetUserByID gets the user with the given id.
@@ -41,35 +42,23 @@ type UserRepo interface {
 	// DeleteUserByID deletes the user with the given id.
 	// If the user does not exist, [ErrNoUser] is returned.
 	DeleteUserByID(ctx context.Context, id string) error
+	// SetDriverStatus sets the availability status of the driver.
+	// If the user does not exist or is not a driver, [ErrNoUser] is returned.
+	SetDriverStatus(ctx context.Context, id string, status models.DriverStatus) error
 }
 
 type userRepo struct {
 	collection *mongo.Collection
 }
 
-// Gets all users in the database
-func (u *userRepo) GetAllUsers(ctx context.Context, driversOnly bool) ([]models.User, error) {
+// Gets a page of users in the database
+func (u *userRepo) GetAllUsers(ctx context.Context, driversOnly bool, p pagination.Params) ([]models.User, string, error) {
 	query := bson.D{{Key: "deleted_at", Value: nil}}
 	if driversOnly {
 		query = append(query, bson.E{Key: "driver_profile", Value: bson.E{Key: "$ne", Value: nil}})
 	}
 
-	cursor, err := u.collection.Find(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-
-	var users []models.User
-	err = cursor.All(ctx, &users)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(users) == 0 {
-		return []models.User{}, nil
-	}
-
-	return users, nil
+	return pagination.Find[models.User](ctx, u.collection, query, p)
 }
 
 // CreateUser creates a new user using the given data.
@@ -132,6 +121,30 @@ func (u *userRepo) DeleteUserByID(ctx context.Context, id string) error {
 	return err
 }
 
+// SetDriverStatus implements UserRepo.
+func (u *userRepo) SetDriverStatus(ctx context.Context, id string, status models.DriverStatus) error {
+	objID, err := bson.ObjectIDFromHex(id)
+	if err != nil {
+		return ErrInvalidID
+	}
+
+	result, err := u.collection.UpdateOne(ctx,
+		bson.D{{Key: "_id", Value: objID}, {Key: "deleted_at", Value: nil}, {Key: "driver_profile", Value: bson.D{{Key: "$ne", Value: nil}}}},
+		bson.D{
+			{Key: "$set", Value: bson.D{{Key: "driver_profile.status", Value: status}}},
+			{Key: "$currentDate", Value: bson.D{{Key: "updated_at", Value: true}}},
+		})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNoUser
+	}
+
+	return nil
+}
+
 // findUser finds a user that matches the given filter.
 func findUser(ctx context.Context, col *mongo.Collection, filter bson.E) (*models.User, error) {
 	result := col.FindOne(ctx, bson.D{filter, {Key: "deleted_at", Value: nil}})
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/pagination"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/models"
 	"github.com/yehan2002/is/v2"
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -68,9 +69,15 @@ func (u *userTests) TestGetAllUser(is is.Is) {
 	})
 	is(err == nil, "user should be created successfully")
 
-	users, err := repo.GetAllUsers(context.TODO(), false)
+	users, next, err := repo.GetAllUsers(context.TODO(), false, pagination.Params{Limit: pagination.DefaultLimit, SortField: "_id"})
 	is(err == nil, "GetAllUsers should be successful")
 	is(len(users) == 2, "two users should be returned")
+	is(next == "", "there should not be a next page")
+
+	users, next, err = repo.GetAllUsers(context.TODO(), false, pagination.Params{Limit: 1, SortField: "_id"})
+	is(err == nil, "GetAllUsers should be successful")
+	is(len(users) == 1, "one user should be returned")
+	is(next != "", "there should be a next page")
 }
 
 func (u *userTests) TestGetUserByEmail(is is.Is) {
@@ -3,6 +3,7 @@ package userservice
 import (
 	"bytes"
 
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/health"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/middleware/auth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/handlers"
@@ -94,6 +95,7 @@ func (s *Server) RegisterRoutes() error {
 
 	{
 		proto.RegisterUserServiceServer(s.grpc, grpc.NewGRPC(s.app.UserRepo()))
+		health.RegisterServer(s.grpc)
 	}
 
 	return nil
@@ -9,7 +9,12 @@ import (
 
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/database"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/grpcauth"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/logger"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/metrics"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/ratelimit"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/requestlog"
+	"github.com/SE-WE-22-Projects/DS-Food-Delivery/shared/tracing"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/app"
 	"github.com/SE-WE-22-Projects/DS-Food-Delivery/user-service/app/oauth"
 	"github.com/gofiber/fiber/v3"
@@ -35,6 +40,15 @@ type Config struct {
 	OAuth    oauth.Config
 	Database database.MongoConfig
 	Logger   logger.Config
+	Tracing  tracing.Config
+
+	RateLimit ratelimit.Config
+}
+
+// grpcRules contains the services that are allowed to call the grpc methods of the service.
+var grpcRules = grpcauth.Rules{
+	"/UserService/GetUserBy":       {"order-service", "restaurant-service", "delivery-service"},
+	"/UserService/SetDriverStatus": {"delivery-service"},
 }
 
 type Server struct {
@@ -53,11 +67,15 @@ func New(cfg *Config, mongoDB *mongo.Client, key *rsa.PrivateKey) *Server {
 		db:    mongoDB,
 		key:   key,
 		fiber: fiber.New(shared.DefaultFiberConfig),
-		grpc:  grpc.NewServer(grpc.ConnectionTimeout(time.Second * 10)),
+		grpc:  grpc.NewServer(grpc.ConnectionTimeout(time.Second*10), tracing.ServerOption(), metrics.ServerOption(), requestlog.ServerOption(), grpcauth.ServerOption(grpcRules)),
 		app:   app.NewApp(mongoDB, cfg.OAuth, key),
 	}
 
-	shared.WithDefaultMiddleware(server.fiber)
+	limits, err := ratelimit.NewMongoStore(context.TODO(), mongoDB.Database("user-service"))
+	if err != nil {
+		zap.L().Fatal("Failed to create rate limit store", zap.Error(err))
+	}
+	shared.WithDefaultMiddleware(server.fiber, ratelimit.New(limits, cfg.RateLimit, &key.PublicKey))
 
 	return server
 }
@@ -68,8 +68,10 @@ export const removeCoupon = async (userId: string): Promise<Cart> => {
     return resp.data;
 }
 
-export const createOrder = async (userId: string, address: AddressType): Promise<string> => {
-    const resp = await client.post(`orders/from-cart/${userId}`, { address: address });
+// idempotencyKey should stay the same when retrying a request that failed without a response
+// so that the order is only created once.
+export const createOrder = async (userId: string, address: AddressType, idempotencyKey: string): Promise<string> => {
+    const resp = await client.post(`orders/from-cart/${userId}`, { address: address }, { headers: { "Idempotency-Key": idempotencyKey } });
     return resp.data.orderId;
 }
 
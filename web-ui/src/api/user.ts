@@ -10,7 +10,9 @@ export type UserInfo = {
         street: string,
         town: string,
         city: string,
-        postal_code: string
+        postal_code: string,
+        // set for old addresses that only contain the street
+        incomplete?: boolean
     },
     roles: string[],
     profile_image: string
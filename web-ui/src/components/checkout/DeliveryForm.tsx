@@ -25,6 +25,8 @@ const DeliveryForm = () => {
     return (
         <div className='mx-2 border p-6 rounded-2xl'>
             <h2 className='font-semibold text-lg  '>Delivery Details</h2>
+            {user.data?.address.incomplete &&
+                <p className='text-sm text-muted-foreground mt-2'>Your saved address is incomplete. Please fill in the missing details and select the location on the map.</p>}
             <div className='flex flex-col gap-4'>
                 <div className='flex gap-4 mt-4 md:flex-row flex-col'>
                     <FormField
@@ -14,6 +14,8 @@ import { Separator } from '@/components/ui/separator';
 import { Skeleton } from '@/components/ui/skeleton';
 import DeliveryForm from '@/components/checkout/DeliveryForm';
 import { Cart } from '@/api/cart';
+import { isAxiosError } from 'axios';
+import { randomUUID } from '@/lib/utils';
 
 const FormSchema = z.object({
     no: z.string().min(1, { message: "No is required" }),
@@ -30,6 +32,7 @@ const Checkout = () => {
     const userId = useUserStore(state => state.userId);
     const queryClient = useQueryClient();
     const [error, setError] = useState<string>()
+    const [orderKey, setOrderKey] = useState(() => randomUUID())
 
     const form = useForm<FormData>({
         resolver: zodResolver(FormSchema),
@@ -57,10 +60,13 @@ const Checkout = () => {
     const onSubmit = async (values: FormData) => {
         let orderId: string = ""
         try {
-            orderId = await api.cart.createOrder(userId!, values);
+            orderId = await api.cart.createOrder(userId!, values, orderKey);
+            setOrderKey(randomUUID());
             queryClient.invalidateQueries({ queryKey: ['cart', userId] });
             toast.success("Created order successfully");
         } catch (e) {
+            // the key is kept if the request failed without a response so that retrying does not create a second order
+            if (isAxiosError(e) && e.response) setOrderKey(randomUUID());
             toast.error("Failed to create order");
             console.error(e);
             return;